@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"dots-api/lib/logger"
+	"dots-api/lib/queue"
 	"dots-api/lib/utils"
 
 	"github.com/go-chi/chi/v5"
@@ -30,6 +31,7 @@ type App struct {
 	Log        logger.Contract
 	Redis      *redis.Client
 	NewRelic   *newrelic.Application
+	Queue      queue.Queue
 }
 
 // Validator set validator instance
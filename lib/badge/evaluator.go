@@ -0,0 +1,280 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Source provides the member statistics the rule evaluators measure against.
+type Source interface {
+	CountGamesPlayed(ctx context.Context, userId int64, gameCodes []string, bookingPrice float64, needGM bool) (int64, error)
+	CountParticipations(ctx context.Context, userId int64, startDate, endDate string) (int64, error)
+	TotalSpend(ctx context.Context, userId int64) (int64, error)
+	CountTournamentWon(ctx context.Context, userId int64) (int64, error)
+	CountGameCollections(ctx context.Context, userId int64) (int64, error)
+	CountCafeVisits(ctx context.Context, userId int64, cafeCode string) (int64, error)
+	CountCoPlayers(ctx context.Context, userId int64) (int64, error)
+	CountGameCategories(ctx context.Context, userId int64, category string) (int64, error)
+	CountGameMasterSessions(ctx context.Context, userId int64) (int64, error)
+	CountRoomRankFinishes(ctx context.Context, userId int64, maxRank int64, gameCode string) (int64, error)
+	CountMemberHostSessions(ctx context.Context, userId int64) (int64, error)
+}
+
+// SetSource provides the IDs of every member reaching a rule target, computed
+// with one aggregate query per rule instead of one query per member.
+type SetSource interface {
+	UsersByGamesPlayed(ctx context.Context, gameCodes []string, bookingPrice float64, needGM bool, min int64) ([]int64, error)
+	UsersByParticipations(ctx context.Context, startDate, endDate string, min int64) ([]int64, error)
+	UsersByTotalSpend(ctx context.Context, min int64) ([]int64, error)
+	UsersByTournamentWon(ctx context.Context, min int64) ([]int64, error)
+	UsersByGameCollections(ctx context.Context, min int64) ([]int64, error)
+	UsersByCafeVisits(ctx context.Context, cafeCode string, min int64) ([]int64, error)
+	UsersByCoPlayers(ctx context.Context, min int64) ([]int64, error)
+	UsersByGameCategories(ctx context.Context, category string, min int64) ([]int64, error)
+	UsersByGameMasterSessions(ctx context.Context, min int64) ([]int64, error)
+	UsersByRoomRankFinishes(ctx context.Context, maxRank int64, gameCode string, min int64) ([]int64, error)
+	UsersByMemberHostSessions(ctx context.Context, min int64) ([]int64, error)
+}
+
+// Evaluator checks one badge rule type.
+type Evaluator interface {
+	// Parse validates a raw rule value and returns the typed value stored in badges_rules.
+	Parse(value interface{}) (interface{}, error)
+	// Measure returns the member's current value against the target of the rule.
+	Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error)
+	// Qualify returns the IDs of every member satisfying the rule.
+	Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error)
+}
+
+// Rule is a single key condition of a badge.
+type Rule struct {
+	KeyCondition string
+	Value        interface{}
+}
+
+// Progress is the current value of a member against the target of a rule.
+type Progress struct {
+	Current int64 `json:"current"`
+	Target  int64 `json:"target"`
+}
+
+// Satisfied reports whether the current value reached the target.
+func (p Progress) Satisfied() bool {
+	return p.Target > 0 && p.Current >= p.Target
+}
+
+// Percentage returns the completion of the rule between 0 and 100.
+func (p Progress) Percentage() int {
+	if p.Target <= 0 {
+		return 0
+	}
+	if p.Current >= p.Target {
+		return 100
+	}
+
+	return int(p.Current * 100 / p.Target)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Evaluator{}
+)
+
+// Register adds an evaluator for a key condition, replacing any previous one.
+func Register(keyCondition string, e Evaluator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[keyCondition] = e
+}
+
+// Lookup returns the evaluator registered for a key condition.
+func Lookup(keyCondition string) (Evaluator, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	e, ok := registry[keyCondition]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s", utils.ErrInvalidBadgeRuleKey, keyCondition)
+	}
+
+	return e, nil
+}
+
+// Keys returns the registered key conditions in alphabetical order.
+func Keys() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	keys := make([]string, 0, len(registry))
+	for k := range registry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ValidOperator reports whether op is a supported rule composition.
+func ValidOperator(op string) bool {
+	return utils.Contains(utils.BadgeRuleOperator, op)
+}
+
+// Evaluate checks every rule of a badge for one member and combines the
+// results with the badge operator. A badge without rules is never earned.
+func Evaluate(ctx context.Context, src Source, userId int64, operator string, rules []Rule) (bool, error) {
+	if len(rules) == 0 {
+		return false, nil
+	}
+
+	if operator == "" {
+		operator = utils.BadgeRuleOperatorAll
+	}
+	if !ValidOperator(operator) {
+		return false, fmt.Errorf("%s: %s", utils.ErrInvalidBadgeRuleOperator, operator)
+	}
+
+	for _, rule := range rules {
+		e, err := Lookup(rule.KeyCondition)
+		if err != nil {
+			return false, err
+		}
+
+		progress, err := e.Measure(ctx, src, userId, rule.Value)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == utils.BadgeRuleOperatorAny && progress.Satisfied():
+			return true, nil
+		case operator == utils.BadgeRuleOperatorAll && !progress.Satisfied():
+			return false, nil
+		}
+	}
+
+	return operator == utils.BadgeRuleOperatorAll, nil
+}
+
+// MeasureAll returns the progress of a member on every rule of a badge and the
+// overall completion percentage: the average over the rules for "all" badges
+// and the best rule for "any" badges.
+func MeasureAll(ctx context.Context, src Source, userId int64, operator string, rules []Rule) ([]Progress, int, error) {
+	if operator == "" {
+		operator = utils.BadgeRuleOperatorAll
+	}
+	if !ValidOperator(operator) {
+		return nil, 0, fmt.Errorf("%s: %s", utils.ErrInvalidBadgeRuleOperator, operator)
+	}
+
+	var (
+		list  = make([]Progress, 0, len(rules))
+		total int
+		best  int
+	)
+	for _, rule := range rules {
+		e, err := Lookup(rule.KeyCondition)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		progress, err := e.Measure(ctx, src, userId, rule.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, progress)
+
+		percentage := progress.Percentage()
+		total += percentage
+		if percentage > best {
+			best = percentage
+		}
+	}
+
+	if len(list) == 0 {
+		return list, 0, nil
+	}
+	if operator == utils.BadgeRuleOperatorAny {
+		return list, best, nil
+	}
+
+	return list, total / len(list), nil
+}
+
+// EvaluateUsers returns the IDs of every member earning a badge, combining the
+// qualifying set of each rule with the badge operator. The result is sorted.
+func EvaluateUsers(ctx context.Context, src SetSource, operator string, rules []Rule) ([]int64, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	if operator == "" {
+		operator = utils.BadgeRuleOperatorAll
+	}
+	if !ValidOperator(operator) {
+		return nil, fmt.Errorf("%s: %s", utils.ErrInvalidBadgeRuleOperator, operator)
+	}
+
+	var result map[int64]struct{}
+	for i, rule := range rules {
+		e, err := Lookup(rule.KeyCondition)
+		if err != nil {
+			return nil, err
+		}
+
+		userIds, err := e.Qualify(ctx, src, rule.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		set := make(map[int64]struct{}, len(userIds))
+		for _, id := range userIds {
+			set[id] = struct{}{}
+		}
+
+		switch {
+		case i == 0:
+			result = set
+		case operator == utils.BadgeRuleOperatorAll:
+			for id := range result {
+				if _, ok := set[id]; !ok {
+					delete(result, id)
+				}
+			}
+		default:
+			for id := range set {
+				result[id] = struct{}{}
+			}
+		}
+
+		if operator == utils.BadgeRuleOperatorAll && len(result) == 0 {
+			return nil, nil
+		}
+	}
+
+	userIds := make([]int64, 0, len(result))
+	for id := range result {
+		userIds = append(userIds, id)
+	}
+	sort.Slice(userIds, func(i, j int) bool { return userIds[i] < userIds[j] })
+
+	return userIds, nil
+}
+
+// decode converts a raw rule value (as read from JSON or jsonb) into out.
+func decode(value interface{}, out interface{}) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%s: %v", utils.ErrUnmarshallingBadgeRule, err)
+	}
+
+	if err = json.Unmarshal(valueJSON, out); err != nil {
+		return fmt.Errorf("%s: %v", utils.ErrUnmarshallingBadgeRule, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// members holds a member reaching most targets (1) and one missing them (2).
+var members = fakeSource{
+	1: {
+		gamesPlayed:    map[string]int64{"GAME-A": 2, "GAME-B": 1},
+		participations: []string{"2026-01-05", "2026-02-10", "2026-03-15"},
+		spend:          500000,
+		tournamentsWon: 2,
+		collections:    5,
+		cafeVisits:     map[string]int64{"CAFE-A": 3},
+		coPlayers:      4,
+		categories:     map[string]int64{utils.GameMechanic: 3, utils.GameType: 2},
+		gmSessions:     3,
+		rankFinishes:   map[int64]int64{1: 2, 3: 1},
+		hostSessions:   2,
+	},
+	2: {
+		gamesPlayed:    map[string]int64{"GAME-A": 1},
+		participations: []string{"2025-12-31", "2026-04-01"},
+		spend:          100000,
+		collections:    1,
+		cafeVisits:     map[string]int64{"CAFE-A": 1, "CAFE-B": 5},
+		coPlayers:      1,
+		categories:     map[string]int64{utils.GameMechanic: 1},
+		rankFinishes:   map[int64]int64{2: 1},
+	},
+}
+
+func TestRuleEvaluators(t *testing.T) {
+	cases := []struct {
+		name      string
+		key       string
+		value     string
+		progress  map[int64]Progress
+		qualified []int64
+	}{
+		{
+			name:      "board game",
+			key:       utils.SpesificBoardGameCategory,
+			value:     `{"game_code": ["GAME-A", "GAME-B"], "total_played": 3, "booking_price": 0}`,
+			progress:  map[int64]Progress{1: {3, 3}, 2: {1, 3}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "time limit window",
+			key:       utils.TimeLimit,
+			value:     `{"category": "time_limit", "start_date": "2026-01-01", "end_date": "2026-03-01", "total_played": 2}`,
+			progress:  map[int64]Progress{1: {2, 2}, 2: {0, 2}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "time limit window defaults to one participation",
+			key:       utils.TimeLimit,
+			value:     `{"category": "time_limit", "start_date": "2026-04-01", "end_date": "2026-04-30"}`,
+			progress:  map[int64]Progress{1: {0, 1}, 2: {1, 1}},
+			qualified: []int64{2},
+		},
+		{
+			name:      "life time ignores the end date",
+			key:       utils.TimeLimit,
+			value:     `{"category": "life_time", "start_date": "2026-02-01", "end_date": "2026-02-02", "total_played": 2}`,
+			progress:  map[int64]Progress{1: {2, 2}, 2: {1, 2}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "total spend",
+			key:       utils.TotalSpend,
+			value:     `250000`,
+			progress:  map[int64]Progress{1: {500000, 250000}, 2: {100000, 250000}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "tournament won",
+			key:       utils.TournamentWon,
+			value:     `1`,
+			progress:  map[int64]Progress{1: {2, 1}, 2: {0, 1}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "playing games",
+			key:       utils.PlayingGames,
+			value:     `5`,
+			progress:  map[int64]Progress{1: {5, 5}, 2: {1, 5}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "cafe visit",
+			key:       utils.CafeVisit,
+			value:     `{"cafe_code": "CAFE-A", "total_visit": 3}`,
+			progress:  map[int64]Progress{1: {3, 3}, 2: {1, 3}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "social play",
+			key:       utils.SocialPlay,
+			value:     `3`,
+			progress:  map[int64]Progress{1: {4, 3}, 2: {1, 3}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "game diversity by mechanic",
+			key:       utils.GameDiversity,
+			value:     `{"category": "game_mechanic", "total": 3}`,
+			progress:  map[int64]Progress{1: {3, 3}, 2: {1, 3}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "game diversity by type",
+			key:       utils.GameDiversity,
+			value:     `{"category": "game_type", "total": 2}`,
+			progress:  map[int64]Progress{1: {2, 2}, 2: {0, 2}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "game master session",
+			key:       utils.GameMasterSession,
+			value:     `1`,
+			progress:  map[int64]Progress{1: {3, 1}, 2: {0, 1}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "room rank finish",
+			key:       utils.RoomRankFinish,
+			value:     `{"max_rank": 1, "total_finish": 2}`,
+			progress:  map[int64]Progress{1: {2, 2}, 2: {0, 2}},
+			qualified: []int64{1},
+		},
+		{
+			name:      "member host session",
+			key:       utils.MemberHostSession,
+			value:     `2`,
+			progress:  map[int64]Progress{1: {2, 2}, 2: {0, 2}},
+			qualified: []int64{1},
+		},
+		{
+			name:     "tournament is awarded by the tournament flow",
+			key:      utils.Tournament,
+			value:    `{"position": 1}`,
+			progress: map[int64]Progress{1: {}, 2: {}},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Lookup(tc.key)
+			if err != nil {
+				t.Fatalf("Lookup(%q): %v", tc.key, err)
+			}
+
+			for userId, want := range tc.progress {
+				got, err := e.Measure(ctx, members, userId, json.RawMessage(tc.value))
+				if err != nil {
+					t.Fatalf("Measure(user %d): %v", userId, err)
+				}
+				if got != want {
+					t.Errorf("Measure(user %d) = %+v, want %+v", userId, got, want)
+				}
+
+				wantSatisfied := false
+				for _, id := range tc.qualified {
+					wantSatisfied = wantSatisfied || id == userId
+				}
+				if got.Satisfied() != wantSatisfied {
+					t.Errorf("Measure(user %d).Satisfied() = %v, want %v", userId, got.Satisfied(), wantSatisfied)
+				}
+			}
+
+			qualified, err := e.Qualify(ctx, members, json.RawMessage(tc.value))
+			if err != nil {
+				t.Fatalf("Qualify: %v", err)
+			}
+			if !reflect.DeepEqual(qualified, tc.qualified) {
+				t.Errorf("Qualify = %v, want %v", qualified, tc.qualified)
+			}
+		})
+	}
+}
+
+func TestRuleEvaluatorsRejectInvalidValues(t *testing.T) {
+	cases := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"board game without games", utils.SpesificBoardGameCategory, `{"game_code": [], "total_played": 3}`},
+		{"board game with negative price", utils.SpesificBoardGameCategory, `{"game_code": ["GAME-A"], "total_played": 3, "booking_price": -1}`},
+		{"time limit without end date", utils.TimeLimit, `{"category": "time_limit", "start_date": "2026-01-01"}`},
+		{"life time without start date", utils.TimeLimit, `{"category": "life_time"}`},
+		{"time limit with unknown category", utils.TimeLimit, `{"category": "weekly", "start_date": "2026-01-01"}`},
+		{"total spend of zero", utils.TotalSpend, `0`},
+		{"total spend as text", utils.TotalSpend, `"a lot"`},
+		{"cafe visit without cafe", utils.CafeVisit, `{"total_visit": 3}`},
+		{"game diversity with unknown category", utils.GameDiversity, `{"category": "game_theme", "total": 3}`},
+		{"room rank finish without rank", utils.RoomRankFinish, `{"max_rank": 0, "total_finish": 2}`},
+		{"member host session of zero", utils.MemberHostSession, `0`},
+		{"tournament with negative position", utils.Tournament, `{"position": -1}`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Lookup(tc.key)
+			if err != nil {
+				t.Fatalf("Lookup(%q): %v", tc.key, err)
+			}
+
+			if _, err = e.Parse(json.RawMessage(tc.value)); err == nil {
+				t.Errorf("Parse(%s) succeeded, want an error", tc.value)
+			}
+			if _, err = e.Measure(context.Background(), members, 1, json.RawMessage(tc.value)); err == nil {
+				t.Errorf("Measure(%s) succeeded, want an error", tc.value)
+			}
+		})
+	}
+}
+
+func TestUnknownRule(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		rules = []Rule{{KeyCondition: "unknown_rule", Value: json.RawMessage(`1`)}}
+	)
+
+	if _, err := Lookup("unknown_rule"); err == nil {
+		t.Error("Lookup succeeded, want an error")
+	}
+	if _, err := Evaluate(ctx, members, 1, utils.BadgeRuleOperatorAll, rules); err == nil {
+		t.Error("Evaluate succeeded, want an error")
+	}
+	if _, _, err := MeasureAll(ctx, members, 1, utils.BadgeRuleOperatorAll, rules); err == nil {
+		t.Error("MeasureAll succeeded, want an error")
+	}
+	if _, err := EvaluateUsers(ctx, members, utils.BadgeRuleOperatorAll, rules); err == nil {
+		t.Error("EvaluateUsers succeeded, want an error")
+	}
+}
+
+func TestEvaluateOperators(t *testing.T) {
+	var (
+		spend = Rule{KeyCondition: utils.TotalSpend, Value: json.RawMessage(`250000`)}
+		cafe  = Rule{KeyCondition: utils.CafeVisit, Value: json.RawMessage(`{"cafe_code": "CAFE-B", "total_visit": 5}`)}
+		won   = Rule{KeyCondition: utils.TournamentWon, Value: json.RawMessage(`1`)}
+	)
+
+	cases := []struct {
+		name     string
+		operator string
+		rules    []Rule
+		earned   map[int64]bool
+		users    []int64
+	}{
+		{"no rules", utils.BadgeRuleOperatorAll, nil, map[int64]bool{1: false, 2: false}, nil},
+		{"all of one member's rules", utils.BadgeRuleOperatorAll, []Rule{spend, won}, map[int64]bool{1: true, 2: false}, []int64{1}},
+		{"all of rules split between members", utils.BadgeRuleOperatorAll, []Rule{spend, cafe}, map[int64]bool{1: false, 2: false}, nil},
+		{"any of rules split between members", utils.BadgeRuleOperatorAny, []Rule{spend, cafe}, map[int64]bool{1: true, 2: true}, []int64{1, 2}},
+		{"empty operator means all", "", []Rule{spend, cafe}, map[int64]bool{1: false, 2: false}, nil},
+	}
+
+	ctx := context.Background()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for userId, want := range tc.earned {
+				got, err := Evaluate(ctx, members, userId, tc.operator, tc.rules)
+				if err != nil {
+					t.Fatalf("Evaluate(user %d): %v", userId, err)
+				}
+				if got != want {
+					t.Errorf("Evaluate(user %d) = %v, want %v", userId, got, want)
+				}
+			}
+
+			users, err := EvaluateUsers(ctx, members, tc.operator, tc.rules)
+			if err != nil {
+				t.Fatalf("EvaluateUsers: %v", err)
+			}
+			if len(users) != len(tc.users) || (len(users) > 0 && !reflect.DeepEqual(users, tc.users)) {
+				t.Errorf("EvaluateUsers = %v, want %v", users, tc.users)
+			}
+		})
+	}
+
+	if _, err := Evaluate(ctx, members, 1, "most", []Rule{spend}); err == nil {
+		t.Error("Evaluate with an unknown operator succeeded, want an error")
+	}
+}
+
+func TestProgressPercentage(t *testing.T) {
+	cases := []struct {
+		progress Progress
+		want     int
+	}{
+		{Progress{Current: 0, Target: 0}, 0},
+		{Progress{Current: 1, Target: 4}, 25},
+		{Progress{Current: 4, Target: 4}, 100},
+		{Progress{Current: 9, Target: 4}, 100},
+	}
+
+	for _, tc := range cases {
+		if got := tc.progress.Percentage(); got != tc.want {
+			t.Errorf("%+v.Percentage() = %d, want %d", tc.progress, got, tc.want)
+		}
+	}
+}
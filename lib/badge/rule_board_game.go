@@ -0,0 +1,60 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.SpesificBoardGameCategory, boardGameRule{})
+}
+
+// SpesificBoardGameCategory is the value of a spesific_board_game_category rule.
+type SpesificBoardGameCategory struct {
+	GameCode     []string `json:"game_code"`
+	NeedGM       bool     `json:"need_gm"`
+	TotalPlayed  int64    `json:"total_played"`
+	BookingPrice float64  `json:"booking_price"`
+}
+
+// boardGameRule is earned after playing any of the listed games a number of
+// times, optionally only counting rooms hosted by a game master.
+type boardGameRule struct{}
+
+func (boardGameRule) Parse(value interface{}) (interface{}, error) {
+	var category SpesificBoardGameCategory
+	if err := decode(value, &category); err != nil {
+		return nil, err
+	}
+	if len(category.GameCode) == 0 || category.TotalPlayed <= 0 || category.BookingPrice < 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return category, nil
+}
+
+func (r boardGameRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+	category := parsed.(SpesificBoardGameCategory)
+
+	current, err := src.CountGamesPlayed(ctx, userId, category.GameCode, category.BookingPrice, category.NeedGM)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: category.TotalPlayed}, nil
+}
+
+func (r boardGameRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	category := parsed.(SpesificBoardGameCategory)
+
+	return src.UsersByGamesPlayed(ctx, category.GameCode, category.BookingPrice, category.NeedGM, category.TotalPlayed)
+}
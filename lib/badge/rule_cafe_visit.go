@@ -0,0 +1,58 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.CafeVisit, cafeVisitRule{})
+}
+
+// CafeVisitCategory is the value of a cafe_visit rule.
+type CafeVisitCategory struct {
+	CafeCode   string `json:"cafe_code"`
+	TotalVisit int64  `json:"total_visit"`
+}
+
+// cafeVisitRule is earned after visiting a cafe on a number of different days,
+// counting every room or tournament joined there.
+type cafeVisitRule struct{}
+
+func (cafeVisitRule) Parse(value interface{}) (interface{}, error) {
+	var category CafeVisitCategory
+	if err := decode(value, &category); err != nil {
+		return nil, err
+	}
+	if category.CafeCode == "" || category.TotalVisit <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return category, nil
+}
+
+func (r cafeVisitRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+	category := parsed.(CafeVisitCategory)
+
+	current, err := src.CountCafeVisits(ctx, userId, category.CafeCode)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: category.TotalVisit}, nil
+}
+
+func (r cafeVisitRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	category := parsed.(CafeVisitCategory)
+
+	return src.UsersByCafeVisits(ctx, category.CafeCode, category.TotalVisit)
+}
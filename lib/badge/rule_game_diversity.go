@@ -0,0 +1,62 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.GameDiversity, gameDiversityRule{})
+}
+
+// GameDiversityCategory is the value of a game_diversity rule. Category is
+// either game_mechanic or game_type.
+type GameDiversityCategory struct {
+	Category string `json:"category"`
+	Total    int64  `json:"total"`
+}
+
+// gameDiversityRule is earned after playing games covering a number of
+// different game mechanics or game types.
+type gameDiversityRule struct{}
+
+func (gameDiversityRule) Parse(value interface{}) (interface{}, error) {
+	var category GameDiversityCategory
+	if err := decode(value, &category); err != nil {
+		return nil, err
+	}
+	if category.Category != utils.GameMechanic && category.Category != utils.GameType {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+	if category.Total <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return category, nil
+}
+
+func (r gameDiversityRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+	category := parsed.(GameDiversityCategory)
+
+	current, err := src.CountGameCategories(ctx, userId, category.Category)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: category.Total}, nil
+}
+
+func (r gameDiversityRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	category := parsed.(GameDiversityCategory)
+
+	return src.UsersByGameCategories(ctx, category.Category, category.Total)
+}
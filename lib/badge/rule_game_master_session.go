@@ -0,0 +1,50 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.GameMasterSession, gameMasterSessionRule{})
+}
+
+// gameMasterSessionRule is earned after hosting the given number of rooms as
+// game master.
+type gameMasterSessionRule struct{}
+
+func (gameMasterSessionRule) Parse(value interface{}) (interface{}, error) {
+	var total int64
+	if err := decode(value, &total); err != nil {
+		return nil, err
+	}
+	if total <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return total, nil
+}
+
+func (r gameMasterSessionRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	current, err := src.CountGameMasterSessions(ctx, userId)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: target.(int64)}, nil
+}
+
+func (r gameMasterSessionRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return src.UsersByGameMasterSessions(ctx, target.(int64))
+}
@@ -0,0 +1,50 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.PlayingGames, playingGamesRule{})
+}
+
+// playingGamesRule is earned once the member's game collection holds the given
+// number of different games.
+type playingGamesRule struct{}
+
+func (playingGamesRule) Parse(value interface{}) (interface{}, error) {
+	var total int64
+	if err := decode(value, &total); err != nil {
+		return nil, err
+	}
+	if total <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return total, nil
+}
+
+func (r playingGamesRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	current, err := src.CountGameCollections(ctx, userId)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: target.(int64)}, nil
+}
+
+func (r playingGamesRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return src.UsersByGameCollections(ctx, target.(int64))
+}
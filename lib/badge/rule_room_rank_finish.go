@@ -0,0 +1,60 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.RoomRankFinish, roomRankFinishRule{})
+}
+
+// RoomRankFinish is the value of a room_rank_finish rule. An empty GameCode
+// counts the rooms of every game.
+type RoomRankFinish struct {
+	MaxRank     int64  `json:"max_rank"`
+	TotalFinish int64  `json:"total_finish"`
+	GameCode    string `json:"game_code"`
+}
+
+// roomRankFinishRule is earned after finishing a number of rooms at MaxRank or
+// better, e.g. winning 5 rooms with a max rank of 1.
+type roomRankFinishRule struct{}
+
+func (roomRankFinishRule) Parse(value interface{}) (interface{}, error) {
+	var finish RoomRankFinish
+	if err := decode(value, &finish); err != nil {
+		return nil, err
+	}
+	if finish.MaxRank <= 0 || finish.TotalFinish <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return finish, nil
+}
+
+func (r roomRankFinishRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+	finish := parsed.(RoomRankFinish)
+
+	current, err := src.CountRoomRankFinishes(ctx, userId, finish.MaxRank, finish.GameCode)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: finish.TotalFinish}, nil
+}
+
+func (r roomRankFinishRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	finish := parsed.(RoomRankFinish)
+
+	return src.UsersByRoomRankFinishes(ctx, finish.MaxRank, finish.GameCode, finish.TotalFinish)
+}
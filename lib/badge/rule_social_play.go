@@ -0,0 +1,50 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.SocialPlay, socialPlayRule{})
+}
+
+// socialPlayRule is earned after sharing a room or tournament with the given
+// number of distinct other members.
+type socialPlayRule struct{}
+
+func (socialPlayRule) Parse(value interface{}) (interface{}, error) {
+	var total int64
+	if err := decode(value, &total); err != nil {
+		return nil, err
+	}
+	if total <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return total, nil
+}
+
+func (r socialPlayRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	current, err := src.CountCoPlayers(ctx, userId)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: target.(int64)}, nil
+}
+
+func (r socialPlayRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return src.UsersByCoPlayers(ctx, target.(int64))
+}
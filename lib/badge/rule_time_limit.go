@@ -0,0 +1,76 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.TimeLimit, timeLimitRule{})
+}
+
+// TimeLimitCategory is the value of a time_limit rule. A life_time category
+// has no end date and counts everything played since the start date.
+type TimeLimitCategory struct {
+	Category    string `json:"category"`
+	Name        string `json:"name"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	TotalPlayed int64  `json:"total_played"`
+}
+
+// timeLimitRule is earned after joining rooms or tournaments inside a period.
+type timeLimitRule struct{}
+
+func (timeLimitRule) Parse(value interface{}) (interface{}, error) {
+	var category TimeLimitCategory
+	if err := decode(value, &category); err != nil {
+		return nil, err
+	}
+
+	switch category.Category {
+	case utils.TimeLimit:
+		if category.StartDate == "" || category.EndDate == "" {
+			return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+		}
+	case utils.LifeTime:
+		if category.StartDate == "" {
+			return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+		}
+		category.EndDate = ""
+	default:
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	if category.TotalPlayed <= 0 {
+		category.TotalPlayed = 1
+	}
+
+	return category, nil
+}
+
+func (r timeLimitRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+	category := parsed.(TimeLimitCategory)
+
+	current, err := src.CountParticipations(ctx, userId, category.StartDate, category.EndDate)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: category.TotalPlayed}, nil
+}
+
+func (r timeLimitRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	parsed, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	category := parsed.(TimeLimitCategory)
+
+	return src.UsersByParticipations(ctx, category.StartDate, category.EndDate, category.TotalPlayed)
+}
@@ -0,0 +1,49 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.TotalSpend, totalSpendRule{})
+}
+
+// totalSpendRule is earned once booking payments and claimed invoices reach the amount.
+type totalSpendRule struct{}
+
+func (totalSpendRule) Parse(value interface{}) (interface{}, error) {
+	var amount int64
+	if err := decode(value, &amount); err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return amount, nil
+}
+
+func (r totalSpendRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	current, err := src.TotalSpend(ctx, userId)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{Current: current, Target: target.(int64)}, nil
+}
+
+func (r totalSpendRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	target, err := r.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return src.UsersByTotalSpend(ctx, target.(int64))
+}
@@ -0,0 +1,48 @@
+package badge
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+)
+
+func init() {
+	Register(utils.Tournament, tournamentRule{})
+}
+
+// TournamentCategory is the value of a tournament placing rule.
+type TournamentCategory struct {
+	Position int `json:"position"`
+}
+
+// tournamentRule belongs to tournament badges, which are handed out when the
+// winners are set on a tournament. It is never earned by evaluation.
+type tournamentRule struct{}
+
+func (tournamentRule) Parse(value interface{}) (interface{}, error) {
+	var category TournamentCategory
+	if err := decode(value, &category); err != nil {
+		return nil, err
+	}
+	if category.Position < 0 {
+		return nil, errors.New(utils.ErrInvalidBadgeRuleValue)
+	}
+
+	return category, nil
+}
+
+func (r tournamentRule) Measure(ctx context.Context, src Source, userId int64, value interface{}) (Progress, error) {
+	if _, err := r.Parse(value); err != nil {
+		return Progress{}, err
+	}
+
+	return Progress{}, nil
+}
+
+func (r tournamentRule) Qualify(ctx context.Context, src SetSource, value interface{}) ([]int64, error) {
+	if _, err := r.Parse(value); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
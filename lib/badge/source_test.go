@@ -0,0 +1,178 @@
+package badge
+
+import (
+	"context"
+	"sort"
+)
+
+// memberStats are the statistics of one member in fakeSource.
+type memberStats struct {
+	gamesPlayed    map[string]int64
+	participations []string
+	spend          int64
+	tournamentsWon int64
+	collections    int64
+	cafeVisits     map[string]int64
+	coPlayers      int64
+	categories     map[string]int64
+	gmSessions     int64
+	rankFinishes   map[int64]int64
+	hostSessions   int64
+}
+
+// fakeSource serves the statistics of a fixed set of members. The UsersBy
+// methods reuse the Count methods so both sources always agree.
+type fakeSource map[int64]memberStats
+
+var (
+	_ Source    = fakeSource{}
+	_ SetSource = fakeSource{}
+)
+
+func (f fakeSource) CountGamesPlayed(ctx context.Context, userId int64, gameCodes []string, bookingPrice float64, needGM bool) (int64, error) {
+	var total int64
+	for _, code := range gameCodes {
+		total += f[userId].gamesPlayed[code]
+	}
+
+	return total, nil
+}
+
+// CountParticipations counts the participation dates of a member between
+// startDate and endDate, or from startDate on when endDate is empty.
+func (f fakeSource) CountParticipations(ctx context.Context, userId int64, startDate, endDate string) (int64, error) {
+	var total int64
+	for _, date := range f[userId].participations {
+		if date >= startDate && (endDate == "" || date <= endDate) {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+func (f fakeSource) TotalSpend(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].spend, nil
+}
+
+func (f fakeSource) CountTournamentWon(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].tournamentsWon, nil
+}
+
+func (f fakeSource) CountGameCollections(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].collections, nil
+}
+
+func (f fakeSource) CountCafeVisits(ctx context.Context, userId int64, cafeCode string) (int64, error) {
+	return f[userId].cafeVisits[cafeCode], nil
+}
+
+func (f fakeSource) CountCoPlayers(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].coPlayers, nil
+}
+
+func (f fakeSource) CountGameCategories(ctx context.Context, userId int64, category string) (int64, error) {
+	return f[userId].categories[category], nil
+}
+
+func (f fakeSource) CountGameMasterSessions(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].gmSessions, nil
+}
+
+func (f fakeSource) CountRoomRankFinishes(ctx context.Context, userId int64, maxRank int64, gameCode string) (int64, error) {
+	var total int64
+	for rank, finishes := range f[userId].rankFinishes {
+		if rank >= 1 && rank <= maxRank {
+			total += finishes
+		}
+	}
+
+	return total, nil
+}
+
+func (f fakeSource) CountMemberHostSessions(ctx context.Context, userId int64) (int64, error) {
+	return f[userId].hostSessions, nil
+}
+
+// usersBy returns the sorted IDs of the members whose count reaches min.
+func (f fakeSource) usersBy(ctx context.Context, min int64, count func(userId int64) (int64, error)) ([]int64, error) {
+	var list []int64
+	for userId := range f {
+		total, err := count(userId)
+		if err != nil {
+			return nil, err
+		}
+		if total >= min {
+			list = append(list, userId)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	return list, nil
+}
+
+func (f fakeSource) UsersByGamesPlayed(ctx context.Context, gameCodes []string, bookingPrice float64, needGM bool, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountGamesPlayed(ctx, userId, gameCodes, bookingPrice, needGM)
+	})
+}
+
+func (f fakeSource) UsersByParticipations(ctx context.Context, startDate, endDate string, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountParticipations(ctx, userId, startDate, endDate)
+	})
+}
+
+func (f fakeSource) UsersByTotalSpend(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.TotalSpend(ctx, userId)
+	})
+}
+
+func (f fakeSource) UsersByTournamentWon(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountTournamentWon(ctx, userId)
+	})
+}
+
+func (f fakeSource) UsersByGameCollections(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountGameCollections(ctx, userId)
+	})
+}
+
+func (f fakeSource) UsersByCafeVisits(ctx context.Context, cafeCode string, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountCafeVisits(ctx, userId, cafeCode)
+	})
+}
+
+func (f fakeSource) UsersByCoPlayers(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountCoPlayers(ctx, userId)
+	})
+}
+
+func (f fakeSource) UsersByGameCategories(ctx context.Context, category string, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountGameCategories(ctx, userId, category)
+	})
+}
+
+func (f fakeSource) UsersByGameMasterSessions(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountGameMasterSessions(ctx, userId)
+	})
+}
+
+func (f fakeSource) UsersByRoomRankFinishes(ctx context.Context, maxRank int64, gameCode string, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountRoomRankFinishes(ctx, userId, maxRank, gameCode)
+	})
+}
+
+func (f fakeSource) UsersByMemberHostSessions(ctx context.Context, min int64) ([]int64, error) {
+	return f.usersBy(ctx, min, func(userId int64) (int64, error) {
+		return f.CountMemberHostSessions(ctx, userId)
+	})
+}
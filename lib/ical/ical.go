@@ -0,0 +1,125 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps such as
+// Google Calendar and Apple Calendar can subscribe to.
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+
+	dateTimeFormat = "20060102T150405Z"
+	// lineLimit is the longest a content line can be, in octets, before it
+	// is folded onto the next line.
+	lineLimit = 75
+)
+
+// Event is a VEVENT of a calendar. Sequence must grow every time the event
+// changes, so subscribed calendars replace their copy.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	Status       string
+	Sequence     int64
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR with its events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Bytes returns the calendar in the iCalendar format.
+func (c Calendar) Bytes() []byte {
+	var (
+		sb  strings.Builder
+		now = time.Now()
+	)
+
+	writeLine(&sb, "BEGIN:VCALENDAR")
+	writeLine(&sb, "VERSION:2.0")
+	writeLine(&sb, "PRODID:-//Dots//Dots Calendar//EN")
+	writeLine(&sb, "CALSCALE:GREGORIAN")
+	writeLine(&sb, "METHOD:PUBLISH")
+	writeLine(&sb, "X-WR-CALNAME:"+escape(c.Name))
+	writeLine(&sb, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeLine(&sb, "X-PUBLISHED-TTL:PT1H")
+
+	for _, e := range c.Events {
+		status := e.Status
+		if status == "" {
+			status = StatusConfirmed
+		}
+
+		writeLine(&sb, "BEGIN:VEVENT")
+		writeLine(&sb, "UID:"+escape(e.UID))
+		writeLine(&sb, "DTSTAMP:"+formatTime(now))
+		writeLine(&sb, "DTSTART:"+formatTime(e.Start))
+		writeLine(&sb, "DTEND:"+formatTime(e.End))
+		writeLine(&sb, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			writeLine(&sb, "DESCRIPTION:"+escape(e.Description))
+		}
+		if e.Location != "" {
+			writeLine(&sb, "LOCATION:"+escape(e.Location))
+		}
+		writeLine(&sb, "STATUS:"+status)
+		writeLine(&sb, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		if !e.LastModified.IsZero() {
+			writeLine(&sb, "LAST-MODIFIED:"+formatTime(e.LastModified))
+		}
+		writeLine(&sb, "END:VEVENT")
+	}
+
+	writeLine(&sb, "END:VCALENDAR")
+
+	return []byte(sb.String())
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escape escapes the characters with a meaning in a text value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line, folded so no line is longer than the
+// limit. A fold never splits a UTF-8 character.
+func writeLine(sb *strings.Builder, line string) {
+	limit := lineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of a folded line counts towards its length
+		limit = lineLimit - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
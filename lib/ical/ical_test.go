@@ -0,0 +1,160 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "Catan night", "Catan night"},
+		{"backslash", `C:\games`, `C:\\games`},
+		{"semicolon", "Catan; Carcassonne", `Catan\; Carcassonne`},
+		{"comma", "Jakarta, Indonesia", `Jakarta\, Indonesia`},
+		{"crlf", "line one\r\nline two", `line one\nline two`},
+		{"lf", "line one\nline two", `line one\nline two`},
+		{"cr", "line one\rline two", `line one\nline two`},
+		{"backslash before comma", `a\,b`, `a\\\,b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escape(tt.in); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		lines int
+	}{
+		{"short", "SUMMARY:Catan night", 1},
+		{"at the limit", "SUMMARY:" + strings.Repeat("a", lineLimit-len("SUMMARY:")), 1},
+		{"one over the limit", "SUMMARY:" + strings.Repeat("a", lineLimit-len("SUMMARY:")+1), 2},
+		{"long", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20), 3},
+		{"multibyte", "SUMMARY:" + strings.Repeat("é", 100), 3},
+		{"emoji", "SUMMARY:" + strings.Repeat("🎲", 40), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			writeLine(&sb, tt.line)
+			out := sb.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("%q does not end with CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("folded onto %d lines, want %d: %q", len(lines), tt.lines, out)
+			}
+			for i, l := range lines {
+				if len(l) > lineLimit {
+					t.Errorf("line %d is %d octets, want at most %d", i, len(l), lineLimit)
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("folded line %d does not start with a space: %q", i, l)
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, l)
+				}
+			}
+
+			// Unfolding removes every CRLF followed by a space
+			if got := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); got != tt.line {
+				t.Errorf("unfolded to %q, want %q", got, tt.line)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name string
+		in   time.Time
+		want string
+	}{
+		{"utc", time.Date(2026, 3, 14, 12, 30, 0, 0, time.UTC), "20260314T123000Z"},
+		{"wib", time.Date(2026, 3, 14, 19, 30, 0, 0, wib), "20260314T123000Z"},
+		{"wib before midnight utc", time.Date(2026, 3, 15, 6, 0, 0, 0, wib), "20260314T230000Z"},
+		{"seconds", time.Date(2026, 12, 31, 23, 59, 59, 999, time.UTC), "20261231T235959Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTime(tt.in); got != tt.want {
+				t.Errorf("formatTime(%s) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarBytes(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	cal := Calendar{
+		Name: "Dots, Jakarta",
+		Events: []Event{
+			{
+				UID:      "room-RM1@dots",
+				Summary:  "Catan; beginners",
+				Location: "Dots Cafe, Jakarta",
+				Start:    time.Date(2026, 3, 14, 19, 0, 0, 0, wib),
+				End:      time.Date(2026, 3, 14, 21, 0, 0, 0, wib),
+				Sequence: 3,
+			},
+			{
+				UID:          "tournament-TR1@dots",
+				Summary:      "Finals",
+				Start:        time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC),
+				End:          time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC),
+				Status:       StatusCancelled,
+				LastModified: time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	out := string(cal.Bytes())
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Errorf("a line does not end with CRLF")
+	}
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Dots\\, Jakarta\r\n",
+		"UID:room-RM1@dots\r\n",
+		"DTSTART:20260314T120000Z\r\n",
+		"DTEND:20260314T140000Z\r\n",
+		"SUMMARY:Catan\\; beginners\r\n",
+		"LOCATION:Dots Cafe\\, Jakarta\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"SEQUENCE:3\r\n",
+		"DTSTART:20260315T100000Z\r\n",
+		"DTEND:20260315T120000Z\r\n",
+		"STATUS:CANCELLED\r\n",
+		"LAST-MODIFIED:20260310T080000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar has no line %q:\n%s", want, out)
+		}
+	}
+
+	if got := strings.Count(out, "BEGIN:VEVENT\r\n"); got != 2 {
+		t.Errorf("%d events, want 2", got)
+	}
+	if strings.Count(out, "DESCRIPTION:") != 0 {
+		t.Errorf("an event without description has a DESCRIPTION line")
+	}
+}
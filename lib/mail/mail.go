@@ -16,6 +16,8 @@ const (
 	UserUpdateEmail    = "user_update_email"
 	SuccessfulPayment  = "successful_payment"
 	FailedPayment      = "failed_payment"
+	EventCancelled     = "event_cancelled"
+	MinimumParticipant = "minimum_participant"
 )
 
 var MailSubj = map[string]string{
@@ -24,6 +26,8 @@ var MailSubj = map[string]string{
 	UserForgotPassword: "[DOTS] Forgot Password",
 	SuccessfulPayment:  "[DOTS] Successful Payment",
 	FailedPayment:      "[DOTS] Failed Payment",
+	EventCancelled:     "[DOTS] Event Cancelled",
+	MinimumParticipant: "[DOTS] Event Below Minimum Participants",
 }
 
 type EmailData struct {
@@ -44,6 +48,25 @@ type SuccessPaymentData struct {
 type FailedPaymentData struct {
 	Name string
 }
+
+type EventCancelledData struct {
+	Name         string
+	EventName    string
+	EventDate    string
+	Reason       string
+	RefundStatus string
+	Amount       int64
+}
+
+type MinimumParticipantData struct {
+	Name        string
+	Title       string
+	Description string
+	EventType   string
+	EventCode   string
+	Cancelled   bool
+}
+
 type Contract struct {
 	app *bootstrap.App
 }
@@ -0,0 +1,151 @@
+package queue
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"log"
+	"sync"
+	"time"
+)
+
+// memoryBufferSize is the number of messages a queue holds before Publish
+// waits for a consumer.
+const memoryBufferSize = 1000
+
+type memoryMessage struct {
+	body    []byte
+	attempt int
+}
+
+// memoryQueue is the in-process driver. Messages and dead letters are lost
+// when the process stops, so it is only meant for development and tests.
+type memoryQueue struct {
+	policy      rabbit.RetryPolicy
+	mu          sync.Mutex
+	queues      map[string]chan memoryMessage
+	deadLetters map[string][]rabbit.DeadLetter
+}
+
+// NewMemory returns a driver that keeps the messages in the process.
+func NewMemory(policy rabbit.RetryPolicy) Queue {
+	return &memoryQueue{
+		policy:      policy,
+		queues:      map[string]chan memoryMessage{},
+		deadLetters: map[string][]rabbit.DeadLetter{},
+	}
+}
+
+func (q *memoryQueue) Publish(ctx context.Context, name string, body []byte) error {
+	return q.push(ctx, name, memoryMessage{body: body, attempt: 1})
+}
+
+func (q *memoryQueue) Consume(ctx context.Context, name string, handler rabbit.Handler) error {
+	msgs := q.queue(name)
+
+	log.Printf("[%s] In-memory consumer ready", name)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[%s] Consumer stopped", name)
+			return nil
+		case msg := <-msgs:
+			// The message in progress is finished even when ctx is cancelled
+			err := handler(context.Background(), msg.body)
+			if err == nil {
+				continue
+			}
+
+			if rabbit.IsPermanent(err) || msg.attempt >= q.policy.MaxAttempts {
+				log.Printf("[%s] Dead-lettering message after %d attempts: %s", name, msg.attempt, err)
+				q.deadLetter(name, msg, err)
+				continue
+			}
+
+			delay := q.policy.Delay(msg.attempt)
+			log.Printf("[%s] Retrying message in %s (attempt %d/%d): %s", name, delay, msg.attempt, q.policy.MaxAttempts, err)
+
+			msg.attempt++
+			time.AfterFunc(delay, func() {
+				if err := q.push(ctx, name, msg); err != nil {
+					log.Printf("[%s] Requeue err: %s", name, err)
+				}
+			})
+		}
+	}
+}
+
+func (q *memoryQueue) DeadLetters(ctx context.Context, name string, limit int) ([]rabbit.DeadLetter, int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	list := q.deadLetters[name]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	return append([]rabbit.DeadLetter(nil), list...), len(q.deadLetters[name]), nil
+}
+
+func (q *memoryQueue) RequeueDeadLetters(ctx context.Context, name string, limit int) (int, error) {
+	var total int
+	for total < limit {
+		q.mu.Lock()
+		list := q.deadLetters[name]
+		if len(list) == 0 {
+			q.mu.Unlock()
+			break
+		}
+		d := list[0]
+		q.deadLetters[name] = list[1:]
+		q.mu.Unlock()
+
+		if err := q.push(ctx, name, memoryMessage{body: []byte(d.Body), attempt: 1}); err != nil {
+			// Keep the dead letter when it cannot be moved back
+			q.mu.Lock()
+			q.deadLetters[name] = append([]rabbit.DeadLetter{d}, q.deadLetters[name]...)
+			q.mu.Unlock()
+			return total, err
+		}
+		total++
+	}
+
+	return total, nil
+}
+
+func (q *memoryQueue) Close() {}
+
+// deadLetter keeps a message that failed every attempt, like the dead letter
+// queue of the broker.
+func (q *memoryQueue) deadLetter(name string, msg memoryMessage, cause error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.deadLetters[name] = append(q.deadLetters[name], rabbit.DeadLetter{
+		Attempt:   msg.attempt,
+		LastError: cause.Error(),
+		DeadDate:  time.Now().UTC(),
+		Body:      string(msg.body),
+	})
+}
+
+func (q *memoryQueue) push(ctx context.Context, name string, msg memoryMessage) error {
+	select {
+	case q.queue(name) <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *memoryQueue) queue(name string) chan memoryMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ch, ok := q.queues[name]
+	if !ok {
+		ch = make(chan memoryMessage, memoryBufferSize)
+		q.queues[name] = ch
+	}
+
+	return ch
+}
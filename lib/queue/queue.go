@@ -0,0 +1,51 @@
+package queue
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"fmt"
+	"time"
+)
+
+const (
+	// DriverRabbitMQ publishes and consumes through the RabbitMQ broker.
+	DriverRabbitMQ = "rabbitmq"
+	// DriverMemory keeps the messages in the process, for development and
+	// tests without a broker.
+	DriverMemory = "memory"
+)
+
+// Queue publishes and consumes the messages of the named queues.
+type Queue interface {
+	// Publish sends a message to a queue.
+	Publish(ctx context.Context, name string, body []byte) error
+	// Consume processes the messages of a queue until ctx is cancelled. A
+	// failed message is retried, unless the error is rabbit.Permanent.
+	Consume(ctx context.Context, name string, handler rabbit.Handler) error
+	// DeadLetters returns up to limit messages of a queue that failed every
+	// attempt without removing them, and how many there are.
+	DeadLetters(ctx context.Context, name string, limit int) ([]rabbit.DeadLetter, int, error)
+	// RequeueDeadLetters moves up to limit dead letters back to their queue
+	// with a fresh attempt count and returns how many were moved.
+	RequeueDeadLetters(ctx context.Context, name string, limit int) (int, error)
+	// Close releases the resources of the driver.
+	Close()
+}
+
+// New returns the queue driver selected by queue.driver in the config.
+func New(config utils.Config) (Queue, error) {
+	policy := rabbit.NewRetryPolicy(
+		config.GetInt("queue.rabbitmq.max_attempts"),
+		time.Duration(config.GetInt("queue.rabbitmq.retry_delay_seconds"))*time.Second,
+	)
+
+	switch driver := config.GetString("queue.driver"); driver {
+	case "", DriverRabbitMQ:
+		return NewRabbitMQ(config.GetString("queue.rabbitmq.host"), policy), nil
+	case DriverMemory:
+		return NewMemory(policy), nil
+	default:
+		return nil, fmt.Errorf("%s: %s", utils.ErrUnknownQueueDriver, driver)
+	}
+}
@@ -0,0 +1,51 @@
+package queue
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"sync"
+)
+
+// rabbitQueue is the RabbitMQ driver. Messages are published with confirms
+// over one shared connection and every consumer opens its own.
+type rabbitQueue struct {
+	host      string
+	policy    rabbit.RetryPolicy
+	mu        sync.Mutex
+	publisher *rabbit.Publisher
+}
+
+// NewRabbitMQ returns the driver for the broker at host.
+func NewRabbitMQ(host string, policy rabbit.RetryPolicy) Queue {
+	return &rabbitQueue{
+		host:      host,
+		policy:    policy,
+		publisher: rabbit.NewPublisher(host),
+	}
+}
+
+func (q *rabbitQueue) Publish(ctx context.Context, name string, body []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.publisher.Publish(ctx, name, body)
+}
+
+func (q *rabbitQueue) Consume(ctx context.Context, name string, handler rabbit.Handler) error {
+	return rabbit.Consume(ctx, q.host, name, q.policy, handler)
+}
+
+func (q *rabbitQueue) DeadLetters(ctx context.Context, name string, limit int) ([]rabbit.DeadLetter, int, error) {
+	return rabbit.InspectDeadLetters(q.host, name, limit)
+}
+
+func (q *rabbitQueue) RequeueDeadLetters(ctx context.Context, name string, limit int) (int, error) {
+	return rabbit.RequeueDeadLetters(ctx, q.host, name, limit)
+}
+
+func (q *rabbitQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.publisher.Close()
+}
@@ -2,29 +2,23 @@ package rabbit
 
 import (
 	"dots-api/lib/utils"
-	"log"
+	"fmt"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func handleError(err error, msg string) {
+// Connect opens a connection and a channel to the broker.
+func Connect(host string) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(host)
 	if err != nil {
-		log.Fatalf("%s: %s", msg, err)
+		return nil, nil, fmt.Errorf("%s: %v", utils.ErrConnectAMQP, err)
 	}
-}
-
-func Connect(host string) (*amqp.Connection, *amqp.Channel, error) {
-	var (
-		err         error
-		conn        *amqp.Connection
-		amqpChannel *amqp.Channel
-	)
 
-	conn, err = amqp.Dial(host)
-	handleError(err, utils.ErrConnectAMQP)
-
-	amqpChannel, err = conn.Channel()
-	handleError(err, utils.ErrCreateChannelAMQP)
+	amqpChannel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("%s: %v", utils.ErrCreateChannelAMQP, err)
+	}
 
-	return conn, amqpChannel, err
+	return conn, amqpChannel, nil
 }
@@ -0,0 +1,198 @@
+package rabbit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = 10 * time.Second
+)
+
+// RetryPolicy controls how often a failed message is retried before it is
+// moved to the dead letter queue.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+}
+
+// NewRetryPolicy returns a retry policy, falling back to the defaults for
+// values that are not set.
+func NewRetryPolicy(maxAttempts int, baseDelay time.Duration) RetryPolicy {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryDelay
+	}
+
+	return RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: baseDelay}
+}
+
+// Delay returns the wait before the next attempt, doubling after every attempt.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	return p.BaseDelay * time.Duration(1<<(attempt-1))
+}
+
+// Handler processes the body of one message. A returned error retries the
+// message, unless it is wrapped with Permanent.
+type Handler func(ctx context.Context, body []byte) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a malformed
+// message. The message is moved to the dead letter queue right away.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// Consume processes the messages of a queue one at a time until ctx is
+// cancelled or the connection is closed. Each message is acknowledged on its
+// own. A failed message is retried through a delay queue with exponential
+// backoff and moved to the dead letter queue once it has failed
+// policy.MaxAttempts times.
+func Consume(ctx context.Context, host, name string, policy RetryPolicy, handler Handler) error {
+	conn, ch, err := Connect(host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer ch.Close()
+
+	queue, err := DeclareQueue(ch, name)
+	if err != nil {
+		return err
+	}
+
+	// A failed declaration closes the channel, so the retry queues are all
+	// declared before the first message instead of while handling a failure
+	if err = declareRetryQueues(ch, name, policy); err != nil {
+		return err
+	}
+
+	err = ch.Qos(1, 0, false)
+	if err != nil {
+		return fmt.Errorf("failed to configure Qos: %s", err)
+	}
+
+	msgs, err := ch.Consume(
+		queue.Name, // queue
+		"",         // consumer
+		false,      // auto-ack
+		false,      // exclusive
+		false,      // no-local
+		false,      // no-wait
+		nil,        // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register a consumer: %s", err)
+	}
+
+	log.Printf("[%s] Consumer ready, PID: %d", name, os.Getpid())
+	record(name, func(*QueueStats) {})
+	for {
+		var (
+			d  amqp.Delivery
+			ok bool
+		)
+		select {
+		case <-ctx.Done():
+			log.Printf("[%s] Consumer stopped", name)
+			return nil
+		case d, ok = <-msgs:
+			if !ok {
+				return fmt.Errorf("[%s] consumer channel closed", name)
+			}
+		}
+
+		record(name, func(s *QueueStats) {
+			s.Consumed++
+			s.LastMessageDate = time.Now().UTC()
+		})
+
+		// The message in progress is finished even when ctx is cancelled
+		msgCtx := context.Background()
+		if err := handler(msgCtx, d.Body); err != nil {
+			record(name, func(s *QueueStats) { s.Failed++ })
+			handleFailure(msgCtx, ch, name, policy, d, err)
+			continue
+		}
+
+		if err := d.Ack(false); err != nil {
+			log.Printf("[%s] Ack err: %s", name, err)
+		}
+	}
+}
+
+// handleFailure schedules the retry of a failed message or moves it to the
+// dead letter queue. The retry queues are declared by Consume. When the
+// message cannot be published it is rejected without requeue and the broker
+// dead-letters it, so it is never lost nor redelivered in a loop.
+func handleFailure(ctx context.Context, ch *amqp.Channel, name string, policy RetryPolicy, d amqp.Delivery, cause error) {
+	var (
+		attempt = Attempt(d)
+		target  string
+		headers = amqp.Table{HeaderAttempt: int32(attempt + 1), HeaderLastError: cause.Error()}
+	)
+
+	if !IsPermanent(cause) && attempt < policy.MaxAttempts {
+		delay := policy.Delay(attempt)
+		log.Printf("[%s] Retrying message in %s (attempt %d/%d): %s", name, delay, attempt, policy.MaxAttempts, cause)
+
+		target = retryQueueName(name, delay)
+		record(name, func(s *QueueStats) { s.Retried++ })
+	}
+
+	if target == "" {
+		log.Printf("[%s] Dead-lettering message after %d attempts: %s", name, attempt, cause)
+		target = DeadLetterQueueName(name)
+		headers[HeaderAttempt] = int32(attempt)
+		record(name, func(s *QueueStats) { s.DeadLettered++ })
+	}
+
+	err := ch.PublishWithContext(ctx, "", target, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  d.ContentType,
+		Headers:      headers,
+		Timestamp:    time.Now().UTC(),
+		Body:         d.Body,
+	})
+	if err != nil {
+		log.Printf("[%s] Publishing to %s err: %s", name, target, err)
+		// The broker dead-letters a rejected message on its own
+		nack(name, d, false)
+		return
+	}
+
+	if err := d.Ack(false); err != nil {
+		log.Printf("[%s] Ack err: %s", name, err)
+	}
+}
+
+func nack(name string, d amqp.Delivery, requeue bool) {
+	if err := d.Nack(false, requeue); err != nil {
+		log.Printf("[%s] Nack err: %s", name, err)
+	}
+}
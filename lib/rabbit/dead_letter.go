@@ -0,0 +1,112 @@
+package rabbit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetter is a message that failed every attempt.
+type DeadLetter struct {
+	Attempt   int
+	LastError string
+	DeadDate  time.Time
+	Body      string
+}
+
+// InspectDeadLetters returns up to limit messages of the dead letter queue of
+// a queue without removing them.
+func InspectDeadLetters(host, name string, limit int) ([]DeadLetter, int, error) {
+	conn, ch, err := Connect(host)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+	defer ch.Close()
+
+	if _, err = DeclareQueue(ch, name); err != nil {
+		return nil, 0, err
+	}
+
+	queue, err := ch.QueueInspect(DeadLetterQueueName(name))
+	if err != nil {
+		return nil, 0, fmt.Errorf("[%s] %s: %v", name, "Could not inspect dead letter queue", err)
+	}
+
+	var (
+		list    []DeadLetter
+		lastTag uint64
+	)
+	for len(list) < limit {
+		d, ok, err := ch.Get(DeadLetterQueueName(name), false)
+		if err != nil {
+			return nil, 0, fmt.Errorf("[%s] %s: %v", name, "Could not read dead letter queue", err)
+		}
+		if !ok {
+			break
+		}
+
+		lastErr, _ := d.Headers[HeaderLastError].(string)
+		list = append(list, DeadLetter{
+			Attempt:   Attempt(d),
+			LastError: lastErr,
+			DeadDate:  d.Timestamp,
+			Body:      string(d.Body),
+		})
+		lastTag = d.DeliveryTag
+	}
+
+	// Put every read message back in the queue
+	if lastTag > 0 {
+		if err = ch.Nack(lastTag, true, true); err != nil {
+			return nil, 0, fmt.Errorf("[%s] %s: %v", name, "Could not release dead letters", err)
+		}
+	}
+
+	return list, queue.Messages, nil
+}
+
+// RequeueDeadLetters moves up to limit messages of the dead letter queue back
+// to the work queue with a fresh attempt count, and returns how many were moved.
+func RequeueDeadLetters(ctx context.Context, host, name string, limit int) (int, error) {
+	conn, ch, err := Connect(host)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	defer ch.Close()
+
+	if _, err = DeclareQueue(ch, name); err != nil {
+		return 0, err
+	}
+
+	var total int
+	for total < limit {
+		d, ok, err := ch.Get(DeadLetterQueueName(name), false)
+		if err != nil {
+			return total, fmt.Errorf("[%s] %s: %v", name, "Could not read dead letter queue", err)
+		}
+		if !ok {
+			break
+		}
+
+		err = ch.PublishWithContext(ctx, "", name, false, false, amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  d.ContentType,
+			Body:         d.Body,
+		})
+		if err != nil {
+			_ = d.Nack(false, true)
+			return total, fmt.Errorf("[%s] %s: %v", name, "Something error when publish the messages", err)
+		}
+
+		if err = d.Ack(false); err != nil {
+			return total, fmt.Errorf("[%s] %s: %v", name, "Could not remove dead letter", err)
+		}
+		total++
+	}
+
+	return total, nil
+}
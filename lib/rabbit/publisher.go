@@ -0,0 +1,100 @@
+package rabbit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const confirmTimeout = 10 * time.Second
+
+// Publisher publishes messages over a long-lived connection with publisher
+// confirms. It reconnects on the next publish after the connection drops.
+// A Publisher is not safe for concurrent use.
+type Publisher struct {
+	host     string
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	declared map[string]bool
+}
+
+// NewPublisher returns a publisher for the broker at host. The connection is
+// opened on the first publish.
+func NewPublisher(host string) *Publisher {
+	return &Publisher{host: host}
+}
+
+// Publish sends a message to a queue and waits until the broker confirms it.
+func (p *Publisher) Publish(ctx context.Context, queueName string, body []byte) error {
+	if err := p.connect(); err != nil {
+		return err
+	}
+
+	if !p.declared[queueName] {
+		if _, err := DeclareQueue(p.ch, queueName); err != nil {
+			p.Close()
+			return err
+		}
+		p.declared[queueName] = true
+	}
+
+	confirmation, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, "", queueName, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Timestamp:    time.Now().UTC(),
+		Body:         body,
+	})
+	if err != nil {
+		p.Close()
+		return fmt.Errorf("[%s] %s: %v", queueName, "Something error when publish the messages", err)
+	}
+
+	confirmCtx, cancel := context.WithTimeout(ctx, confirmTimeout)
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(confirmCtx)
+	if err != nil {
+		p.Close()
+		return fmt.Errorf("[%s] %s: %v", queueName, "Message was not confirmed", err)
+	}
+	if !acked {
+		return fmt.Errorf("[%s] %s", queueName, "Message was rejected by the broker")
+	}
+
+	return nil
+}
+
+// Close closes the connection. The next publish opens a new one.
+func (p *Publisher) Close() {
+	if p.ch != nil {
+		p.ch.Close()
+	}
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn, p.ch, p.declared = nil, nil, nil
+}
+
+func (p *Publisher) connect() error {
+	if p.conn != nil && !p.conn.IsClosed() {
+		return nil
+	}
+	p.Close()
+
+	conn, ch, err := Connect(p.host)
+	if err != nil {
+		return err
+	}
+
+	if err = ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return errors.New("failed to put the channel in confirm mode: " + err.Error())
+	}
+
+	p.conn, p.ch, p.declared = conn, ch, map[string]bool{}
+	return nil
+}
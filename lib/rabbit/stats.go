@@ -0,0 +1,46 @@
+package rabbit
+
+import (
+	"sync"
+	"time"
+)
+
+// QueueStats counts the messages handled by the consumers of a queue since
+// the process started.
+type QueueStats struct {
+	Consumed        int64     `json:"consumed"`
+	Failed          int64     `json:"failed"`
+	Retried         int64     `json:"retried"`
+	DeadLettered    int64     `json:"dead_lettered"`
+	LastMessageDate time.Time `json:"last_message_date"`
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = map[string]*QueueStats{}
+)
+
+// Stats returns a copy of the counters of every queue consumed by this process.
+func Stats() map[string]QueueStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	list := make(map[string]QueueStats, len(stats))
+	for name, s := range stats {
+		list[name] = *s
+	}
+
+	return list
+}
+
+func record(name string, update func(s *QueueStats)) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	s, ok := stats[name]
+	if !ok {
+		s = &QueueStats{}
+		stats[name] = s
+	}
+	update(s)
+}
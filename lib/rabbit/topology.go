@@ -0,0 +1,114 @@
+package rabbit
+
+import (
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// HeaderAttempt is the delivery attempt of a message, starting at 1.
+	HeaderAttempt = "x-attempt"
+	// HeaderLastError is the error of the last failed attempt of a message.
+	HeaderLastError = "x-last-error"
+)
+
+// DeadLetterQueueName returns the queue holding the messages of a queue that
+// failed every attempt.
+func DeadLetterQueueName(name string) string {
+	return name + ".dead"
+}
+
+// retryQueueName returns the delay queue of a queue for a retry delay. The
+// delay is part of the name, so changing the retry policy declares new queues
+// instead of conflicting with the existing ones.
+func retryQueueName(name string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", name, delay.Milliseconds())
+}
+
+// DeclareQueue declares a durable work queue together with its dead letter
+// queue. Messages rejected without requeue are routed to the dead letter queue
+// by the broker. Publishers and consumers must both declare queues through
+// here so the declarations always match.
+//
+// Queues declared before they were durable must be deleted once, as RabbitMQ
+// refuses to redeclare a queue with different flags.
+func DeclareQueue(ch *amqp.Channel, name string) (amqp.Queue, error) {
+	_, err := ch.QueueDeclare(
+		DeadLetterQueueName(name), // name
+		true,                      // durable
+		false,                     // delete when unused
+		false,                     // exclusive
+		false,                     // no-wait
+		nil,                       // arguments
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("[%s] %s: %v", name, "Could not declare dead letter queue", err)
+	}
+
+	queue, err := ch.QueueDeclare(
+		name,  // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": DeadLetterQueueName(name),
+		},
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("[%s] %s: %v", name, "Could not declare queue", err)
+	}
+
+	return queue, nil
+}
+
+// declareRetryQueue declares the delay queue of a queue. Messages expire after
+// the delay and are routed back to the work queue by the broker.
+func declareRetryQueue(ch *amqp.Channel, name string, delay time.Duration) (amqp.Queue, error) {
+	queue, err := ch.QueueDeclare(
+		retryQueueName(name, delay), // name
+		true,                        // durable
+		false,                       // delete when unused
+		false,                       // exclusive
+		false,                       // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": name,
+			"x-message-ttl":             delay.Milliseconds(),
+		},
+	)
+	if err != nil {
+		return amqp.Queue{}, fmt.Errorf("[%s] %s: %v", name, "Could not declare retry queue", err)
+	}
+
+	return queue, nil
+}
+
+// declareRetryQueues declares the delay queue of every retry of a queue under
+// policy.
+func declareRetryQueues(ch *amqp.Channel, name string, policy RetryPolicy) error {
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		if _, err := declareRetryQueue(ch, name, policy.Delay(attempt)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Attempt returns the delivery attempt of a message.
+func Attempt(d amqp.Delivery) int {
+	switch v := d.Headers[HeaderAttempt].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+
+	return 1
+}
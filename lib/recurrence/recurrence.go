@@ -0,0 +1,100 @@
+// Package recurrence expands the recurrence rule of an event series into the
+// dates it takes place on.
+package recurrence
+
+import (
+	"dots-api/lib/utils"
+	"errors"
+	"time"
+)
+
+const (
+	Weekly   = "weekly"
+	Biweekly = "biweekly"
+)
+
+// Rule repeats an event every week or every other week on the given weekdays,
+// from Start until Until or for Count occurrences, whichever comes first. A
+// zero Until or Count means no limit. Skipped dates, e.g. holidays, do not take
+// place and do not count towards Count. Every date is a day at midnight UTC.
+type Rule struct {
+	Frequency string
+	Weekdays  []time.Weekday
+	Start     time.Time
+	Until     time.Time
+	Count     int
+	Skip      []time.Time
+}
+
+// Validate checks that the rule describes a finite series.
+func (r Rule) Validate() error {
+	if r.Frequency != Weekly && r.Frequency != Biweekly {
+		return errors.New(utils.ErrInvalidRecurrenceFrequency)
+	}
+	if len(r.Weekdays) == 0 {
+		return errors.New(utils.ErrInvalidRecurrenceWeekdays)
+	}
+	for _, d := range r.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return errors.New(utils.ErrInvalidRecurrenceWeekdays)
+		}
+	}
+	if r.Count < 0 || (r.Until.IsZero() && r.Count == 0) || (!r.Until.IsZero() && r.Until.Before(r.Start)) {
+		return errors.New(utils.ErrInvalidRecurrenceEnd)
+	}
+
+	return nil
+}
+
+// Dates returns the dates of the series up to and including upTo.
+func (r Rule) Dates(upTo time.Time) []time.Time {
+	var (
+		dates    []time.Time
+		start    = day(r.Start)
+		end      = day(upTo)
+		weekdays = map[time.Weekday]bool{}
+		skip     = map[time.Time]bool{}
+	)
+	if !r.Until.IsZero() && day(r.Until).Before(end) {
+		end = day(r.Until)
+	}
+	for _, d := range r.Weekdays {
+		weekdays[d] = true
+	}
+	for _, d := range r.Skip {
+		skip[day(d)] = true
+	}
+
+	firstWeek := weekStart(start)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if !weekdays[d.Weekday()] || skip[d] {
+			continue
+		}
+
+		// Every other week counts from the week the series starts in
+		if r.Frequency == Biweekly && int(weekStart(d).Sub(firstWeek).Hours()/24/7)%2 != 0 {
+			continue
+		}
+
+		if r.Count > 0 && len(dates) >= r.Count {
+			break
+		}
+		dates = append(dates, d)
+	}
+
+	return dates
+}
+
+// Today returns the current date in WIB, the time zone events are scheduled in.
+func Today() time.Time {
+	return day(time.Now().In(utils.GetTimeLocationWIB()))
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the monday of the week of t.
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
@@ -47,3 +47,24 @@ func (s *contract) UploadFileS3(paramName, fileMime string, fileSize int64, file
 	filePath := s.app.Config.GetString("aws.s3.public_url") + filename
 	return filePath, nil
 }
+
+// UploadObjectS3 uploads data under a fixed name inside the configured file
+// path, overwriting any previous object, and returns its public URL.
+func (s *contract) UploadObjectS3(name, fileMime string, data []byte) (string, error) {
+	filename := s.app.Config.GetString("aws.s3.filepath") + "/" + name
+	s3Info := new(upload.S3Info)
+	s3Info.Key = s.app.Config.GetString("aws.s3.key")
+	s3Info.Secret = s.app.Config.GetString("aws.s3.secret")
+	s3Info.Region = s.app.Config.GetString("aws.s3.region")
+	s3Info.Bucket = s.app.Config.GetString("aws.s3.bucket")
+	s3Info.Filename = filename
+	s3Info.Filemime = fileMime
+	s3Info.Filesize = int64(len(data))
+
+	err := upload.PushS3Buffer(bytes.NewReader(data), *s3Info)
+	if err != nil {
+		return "", err
+	}
+
+	return s.app.Config.GetString("aws.s3.public_url") + filename, nil
+}
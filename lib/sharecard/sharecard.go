@@ -0,0 +1,205 @@
+package sharecard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // decode jpeg badge images
+	_ "image/png"  // decode png badge images
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	_ "golang.org/x/image/webp" // decode webp badge images
+)
+
+const (
+	// Width and Height follow the Instagram portrait ratio (4:5)
+	Width  = 1080
+	Height = 1350
+
+	imageSize     = 560
+	fetchTimeout  = 10 * time.Second
+	maxImageBytes = 5 << 20
+)
+
+var (
+	colorBackgroundTop    = color.RGBA{R: 0x1b, G: 0x1f, B: 0x3b, A: 0xff}
+	colorBackgroundBottom = color.RGBA{R: 0x0d, G: 0x0f, B: 0x1e, A: 0xff}
+	colorAccent           = color.RGBA{R: 0xff, G: 0xc1, B: 0x07, A: 0xff}
+	colorText             = color.White
+	colorMuted            = color.RGBA{R: 0xb8, G: 0xbc, B: 0xd6, A: 0xff}
+)
+
+// Card is the content of a shareable card.
+type Card struct {
+	Brand      string
+	Heading    string
+	Title      string
+	MemberName string
+	Date       string
+	CafeName   string
+	ImageURL   string
+}
+
+// Render draws the card and returns it encoded as PNG. When the image cannot
+// be fetched an emblem with the initial of the title is drawn instead and the
+// card is reported as degraded, so it is not kept as the final card. A card
+// without an image always shows the emblem and is not degraded.
+func Render(card Card) (data []byte, degraded bool, err error) {
+	dc := gg.NewContext(Width, Height)
+
+	gradient := gg.NewLinearGradient(0, 0, 0, Height)
+	gradient.AddColorStop(0, colorBackgroundTop)
+	gradient.AddColorStop(1, colorBackgroundBottom)
+	dc.SetFillStyle(gradient)
+	dc.DrawRectangle(0, 0, Width, Height)
+	dc.Fill()
+
+	bold, err := loadFace(gobold.TTF, 44)
+	if err != nil {
+		return nil, false, err
+	}
+	headline, err := loadFace(gobold.TTF, 64)
+	if err != nil {
+		return nil, false, err
+	}
+	regular, err := loadFace(goregular.TTF, 40)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Brand and heading
+	dc.SetFontFace(bold)
+	dc.SetColor(colorAccent)
+	dc.DrawStringAnchored(strings.ToUpper(card.Brand), Width/2, 110, 0.5, 0.5)
+	dc.SetColor(colorMuted)
+	dc.DrawStringAnchored(strings.ToUpper(card.Heading), Width/2, 190, 0.5, 0.5)
+
+	// Badge image inside a ring
+	centerX, centerY := float64(Width/2), float64(260+imageSize/2)
+	dc.SetColor(colorAccent)
+	dc.DrawCircle(centerX, centerY, imageSize/2+16)
+	dc.Fill()
+
+	img, fetchErr := fetchImage(card.ImageURL)
+	if fetchErr == nil {
+		dc.DrawCircle(centerX, centerY, imageSize/2)
+		dc.Clip()
+		dc.DrawImageAnchored(resize(img, imageSize), int(centerX), int(centerY), 0.5, 0.5)
+		dc.ResetClip()
+	} else {
+		degraded = card.ImageURL != ""
+
+		dc.SetColor(colorBackgroundTop)
+		dc.DrawCircle(centerX, centerY, imageSize/2)
+		dc.Fill()
+
+		emblem, err := loadFace(gobold.TTF, 280)
+		if err != nil {
+			return nil, false, err
+		}
+		dc.SetFontFace(emblem)
+		dc.SetColor(colorAccent)
+		dc.DrawStringAnchored(initial(card.Title), centerX, centerY, 0.5, 0.35)
+	}
+
+	// Title and member
+	dc.SetFontFace(headline)
+	dc.SetColor(colorText)
+	dc.DrawStringWrapped(card.Title, Width/2, 900, 0.5, 0, Width-160, 1.2, gg.AlignCenter)
+
+	dc.SetFontFace(bold)
+	dc.SetColor(colorAccent)
+	dc.DrawStringAnchored(card.MemberName, Width/2, 1110, 0.5, 0.5)
+
+	// Date and cafe
+	footer := card.Date
+	if card.CafeName != "" {
+		footer = fmt.Sprintf("%s  •  %s", card.Date, card.CafeName)
+	}
+	dc.SetFontFace(regular)
+	dc.SetColor(colorMuted)
+	dc.DrawStringAnchored(footer, Width/2, 1210, 0.5, 0.5)
+
+	var buf bytes.Buffer
+	if err = dc.EncodePNG(&buf); err != nil {
+		return nil, false, err
+	}
+
+	return buf.Bytes(), degraded, nil
+}
+
+func loadFace(ttf []byte, size float64) (font.Face, error) {
+	f, err := truetype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+
+	return truetype.NewFace(f, &truetype.Options{Size: size}), nil
+}
+
+func fetchImage(url string) (image.Image, error) {
+	if url == "" {
+		return nil, errors.New("image url is empty")
+	}
+
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching image: %s", resp.Status)
+	}
+
+	if resp.ContentLength > maxImageBytes {
+		return nil, fmt.Errorf("fetching image: %d bytes is over the limit", resp.ContentLength)
+	}
+
+	img, _, err := image.Decode(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+// resize scales an image to cover a size x size square, cropping the longer side.
+func resize(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	crop := image.Rect(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+		bounds.Min.X+(bounds.Dx()+side)/2,
+		bounds.Min.Y+(bounds.Dy()+side)/2,
+	)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, xdraw.Over, nil)
+
+	return dst
+}
+
+func initial(s string) string {
+	for _, r := range strings.ToUpper(strings.TrimSpace(s)) {
+		return string(r)
+	}
+
+	return "?"
+}
@@ -11,6 +11,7 @@ type Config interface {
 	GetString(key string) string
 	GetInt(key string) int
 	GetBool(key string) bool
+	GetStringMapString(key string) map[string]string
 	initialize(basepath, configPath string)
 }
 
@@ -46,6 +47,11 @@ func (v *viperConfig) GetBool(key string) bool {
 	return viper.GetBool(key)
 }
 
+// GetStringMapString get map of string values from config file.
+func (v *viperConfig) GetStringMapString(key string) map[string]string {
+	return viper.GetStringMapString(key)
+}
+
 // NewViperConfig new instance of configuration
 func NewViperConfig(basepath, configPath string) Config {
 	v := &viperConfig{}
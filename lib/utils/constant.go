@@ -52,6 +52,7 @@ var (
 	ForgotPasswordRoute   = "forgot-password?token="
 	TypeRoute             = "&type="
 	UserCodeRoute         = "&usercode="
+	RoomInviteRoute       = "rooms/join?invite_code="
 
 	VerificationType           = []string{VerifyRegistration, ForgotPassword}
 	StatusBanner               = []string{"publish", "unpublish"}
@@ -65,15 +66,22 @@ var (
 	StatusBadges               = []string{"active", "inactive"}
 	StatusRole                 = []string{"active", "inactive"}
 	StatusPermission           = []string{"active", "inactive"}
+	StatusJobRun               = []string{JobRunRunning, JobRunSuccess, JobRunFailed}
 	StatusRoom                 = []string{"active", "inactive"}
 	StatusRoomParticipant      = []string{"active", "pending", "cancel"}
 	RoomType                   = []string{"normal", "special_event"}
+	StatusRoomSeries           = []string{"active", "inactive"}
+	RoomVisibility             = []string{RoomVisibilityPublic, RoomVisibilityPrivate}
+	RoomJoinReviewStatus       = []string{RoomJoinApproved, RoomJoinRejected}
+	StatusRoomProposal         = []string{RoomProposalPending, RoomProposalApproved, RoomProposalRejected, RoomProposalCancelled}
+	StatusCafeTable            = []string{"active", "inactive"}
+	CafeTableType              = []string{"standard", "large", "rpg"}
 	RoomDifficulty             = []string{"easy", "medium", "hard"}
 	RewardType                 = []string{"fnb", "game", "tournament"}
 	RewardUsed                 = []string{"1", "0"}
 	MonthlyTopAchieverCategory = []string{"vp", "unique_game"}
 	HTTPMethodList             = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	XenditTransactionStatus    = []string{"PENDING", "PAID", "SETTLED", "EXPIRED"}
+	XenditTransactionStatus    = []string{"PENDING", "PAID", "SETTLED", "EXPIRED", "REFUNDED"}
 
 	// Notification title
 	UpcomingTournament  = "upcoming_tournament"
@@ -97,12 +105,199 @@ var (
 	SpesificBoardGameCategory = "spesific_board_game_category"
 	TournamentWon             = "tournament_won"
 	PlayingGames              = "playing_games"
+	CafeVisit                 = "cafe_visit"
+	SocialPlay                = "social_play"
+	GameDiversity             = "game_diversity"
+	GameMasterSession         = "game_master_session"
+	RoomRankFinish            = "room_rank_finish"
+	MemberHostSession         = "member_host_session"
+	GameMechanic              = "game_mechanic"
+	GameType                  = "game_type"
 	Quantity                  = "quantity"
 	Tournament                = "tournament"
 	Room                      = "room"
 	Transaction               = "transaction"
 	Badge                     = "badge"
 
+	// Badge Rule Operator
+	BadgeRuleOperatorAll = "all"
+	BadgeRuleOperatorAny = "any"
+	BadgeRuleOperator    = []string{BadgeRuleOperatorAll, BadgeRuleOperatorAny}
+
+	// UserBadgeBatchSize is the number of user badges inserted per statement
+	// when a badge is awarded to many members at once.
+	UserBadgeBatchSize = 500
+
+	// BadgePreviewSampleSize is the number of qualifying members listed by a badge preview.
+	BadgePreviewSampleSize = 10
+
+	// OutboxBatchSize is the number of outbox events published per relay round.
+	OutboxBatchSize = 100
+	// OutboxRetentionDays is how long published outbox events are kept.
+	OutboxRetentionDays = 7
+
+	// Worker job run status
+	JobRunRunning = "running"
+	JobRunSuccess = "success"
+	JobRunFailed  = "failed"
+	// JobRunTimeoutMinutes is how long a job run may stay running before it
+	// is taken for abandoned by a worker that stopped mid-run.
+	JobRunTimeoutMinutes = 60
+	// JobRunAbandoned is the error of a job run abandoned by its worker.
+	JobRunAbandoned = "the worker stopped before the job finished"
+	// JobRunRetentionDays is how long the finished job runs are kept.
+	JobRunRetentionDays = 30
+
+	// Scheduled jobs
+	JobRoomReminder                 = "room-reminder"
+	JobTournamentReminder           = "tournament-reminder"
+	JobSetInactiveRoomAndTournament = "set-inactive-room-and-tournament"
+	JobPublishScheduledBadges       = "publish-scheduled-badges"
+	JobProcessWaitlists             = "process-waitlists"
+	JobReleaseSeatHolds             = "release-seat-holds"
+	JobGenerateRoomSeries           = "generate-room-series"
+	JobMarkNoShows                  = "mark-no-shows"
+	JobCheckMinimumParticipants     = "check-minimum-participants"
+	JobSettleEventCancellations     = "settle-event-cancellations"
+	JobPurgeJobRuns                 = "purge-job-runs"
+
+	// SeatHoldGraceMinutes keeps the seat of an unpaid booking a little longer
+	// than its invoice, so a payment made just before the invoice expires still
+	// finds its seat.
+	SeatHoldGraceMinutes = 5
+
+	// Waitlist
+	WaitlistRoom       = "room"
+	WaitlistTournament = "tournament"
+	WaitlistWaiting    = "waiting"
+	WaitlistOffered    = "offered"
+	WaitlistBooked     = "booked"
+	WaitlistExpired    = "expired"
+	WaitlistLeft       = "left"
+	// WaitlistOfferMinutes is how long a member has to book a seat offered
+	// from the waitlist before it rolls to the next member.
+	WaitlistOfferMinutes = 30
+
+	WaitlistOfferType        = "waitlist_offer"
+	WaitlistOfferTitle       = "Kursi Tersedia!"
+	WaitlistOfferDescription = "Kursi untuk %s sudah tersedia untuk Anda. Segera booking sebelum %s, setelah itu kursi akan ditawarkan ke anggota berikutnya."
+
+	// Room series
+	// RoomSeriesGenerateDays is how far ahead the rooms of a series are created.
+	RoomSeriesGenerateDays = 28
+
+	RoomSeriesOccurrenceType        = "room_series_occurrence"
+	RoomSeriesOccurrenceTitle       = "Jadwal Baru Dibuka!"
+	RoomSeriesOccurrenceDescription = "%s pada %s sudah dibuka, segera booking kursimu."
+
+	// Attendance
+	AttendanceExpected  = "expected"
+	AttendanceAttended  = "attended"
+	AttendanceNoShow    = "no_show"
+	CheckInMethodQR     = "qr"
+	CheckInMethodManual = "manual"
+	// CheckInQRPrefix starts the content of the check-in QR code of an event,
+	// followed by the event type, event code and check-in token, separated by
+	// colons.
+	CheckInQRPrefix = "dots-check-in"
+	// CheckInOpenMinutes is how long before the start of an event members can
+	// check in by scanning its QR code.
+	CheckInOpenMinutes = 60
+	// NoShowGraceMinutes is how long after the start of an event a paid
+	// booking that did not check in is flagged as a no-show.
+	NoShowGraceMinutes = 30
+
+	// Private room
+	RoomVisibilityPublic  = "public"
+	RoomVisibilityPrivate = "private"
+	RoomJoinPending       = "pending"
+	RoomJoinApproved      = "approved"
+	RoomJoinRejected      = "rejected"
+
+	RoomJoinApprovedType        = "room_join_approved"
+	RoomJoinApprovedTitle       = "Permintaan Bergabung Diterima!"
+	RoomJoinApprovedDescription = "Permintaan Anda untuk bergabung ke %s sudah diterima host. Segera booking kursimu."
+	RoomJoinRejectedType        = "room_join_rejected"
+	RoomJoinRejectedTitle       = "Permintaan Bergabung Ditolak"
+	RoomJoinRejectedDescription = "Maaf, permintaan Anda untuk bergabung ke %s ditolak oleh host."
+
+	// Room proposal
+	RoomProposalPending   = "pending"
+	RoomProposalApproved  = "approved"
+	RoomProposalRejected  = "rejected"
+	RoomProposalCancelled = "cancelled"
+
+	RoomProposalApprovedType        = "room_proposal_approved"
+	RoomProposalApprovedTitle       = "Sesi Anda Disetujui!"
+	RoomProposalApprovedDescription = "Usulan sesi %s sudah disetujui. Room Anda kini terbuka untuk pemain lain."
+	RoomProposalRejectedType        = "room_proposal_rejected"
+	RoomProposalRejectedTitle       = "Usulan Sesi Ditolak"
+	RoomProposalRejectedDescription = "Maaf, usulan sesi %s ditolak. %s"
+
+	// Event cancellation
+	// RefundPending is the refund status of a booking of a cancelled event
+	// until the settle-event-cancellations job settles it.
+	RefundPending        = "pending"
+	RefundRefunded       = "refunded"
+	RefundFailed         = "refund_failed"
+	RefundInvoiceExpired = "invoice_expired"
+	RefundExpireFailed   = "expire_failed"
+	RefundNoPayment      = "no_payment"
+	// RefundReason is sent to the payment gateway with the refunds of a
+	// cancelled event.
+	RefundReason = "CANCELLATION"
+	// RefundMaxAttempts is how many times a failed refund or invoice expiry of
+	// a cancelled event is tried before it is left to the admins.
+	RefundMaxAttempts = 5
+
+	CanceledRoomTitle             = "Event Dibatalkan"
+	CanceledRoomDescription       = "Maaf, %s pada %s dibatalkan oleh penyelenggara. %s"
+	CanceledRoomRefundDescription = "Pembayaran Anda sebesar Rp%d sedang dikembalikan."
+	CanceledRoomRefundFailed      = "Tim kami akan menghubungi Anda untuk pengembalian pembayaran."
+	CanceledRoomInvoiceExpired    = "Tagihan booking Anda sudah dibatalkan."
+
+	// Minimum participants
+	// MinimumCutoffHours is how long before its start an event without
+	// enough participants is cancelled, MinimumWarningHours is how long
+	// before the cutoff its game master and the admins are warned.
+	MinimumCutoffHours  = 24
+	MinimumWarningHours = 6
+	// MinimumCancelledBy is recorded as the canceller of the events cancelled
+	// by the minimum participant check.
+	MinimumCancelledBy  = "system"
+	MinimumCancelReason = "Jumlah peserta minimal tidak terpenuhi."
+
+	MinimumWarningType          = "minimum_participant_warning"
+	MinimumWarningTitle         = "Peserta Belum Cukup"
+	MinimumWarningDescription   = "%s pada %s baru memiliki %d dari minimal %d peserta. Event akan dibatalkan otomatis pada %s jika belum cukup."
+	MinimumCancelledType        = "minimum_participant_cancelled"
+	MinimumCancelledTitle       = "Event Dibatalkan Otomatis"
+	MinimumCancelledDescription = "%s pada %s dibatalkan karena hanya memiliki %d dari minimal %d peserta."
+
+	// Calendar feed
+	CalendarName = "Dots"
+	// CalendarPastDays is how long past bookings stay in the calendar feed of
+	// a member.
+	CalendarPastDays = 30
+
+	// Game master schedule
+	// GameMasterScheduleDays is the default range of a schedule or workload view.
+	GameMasterScheduleDays = 28
+	// GameMasterScheduleMaxDays is the longest range of a schedule or workload view.
+	GameMasterScheduleMaxDays = 92
+
+	// Leaderboard
+	LeaderboardLimit    = 10
+	LeaderboardMaxLimit = 100
+
+	// Share Card
+	ShareCardBrand          = "Dots"
+	ShareCardTypeBadge      = "badge"
+	ShareCardTypeTier       = "tier"
+	ShareCardTypeTournament = "tournament"
+	ShareCardTypes          = []string{ShareCardTypeBadge, ShareCardTypeTier, ShareCardTypeTournament}
+	ShareCardPath           = "share-cards"
+
 	// Notification Title
 	FailPaymentType        = "payment_failed"
 	FailPaymentTitle       = "Pembayaran Gagal!"
@@ -142,20 +337,24 @@ var (
 	}
 
 	PaymentStatus = map[string]string{
-		"PENDING": "PENDING",
-		"PAID":    "PAID",
-		"EXPIRED": "EXPIRED",
+		"PENDING":  "PENDING",
+		"PAID":     "PAID",
+		"SETTLED":  "SETTLED",
+		"EXPIRED":  "EXPIRED",
+		"REFUNDED": "REFUNDED",
 	}
 
 	RoomStatus = map[string]string{
-		"ACTIVE":   "active",
-		"INACTIVE": "inactive",
-		"CLOSED":   "closed",
+		"ACTIVE":    "active",
+		"INACTIVE":  "inactive",
+		"CLOSED":    "closed",
+		"CANCELLED": "cancelled",
 	}
 
 	TournamentStatus = map[string]string{
-		"ACTIVE":   "active",
-		"INACTIVE": "inactive",
-		"CLOSED":   "closed",
+		"ACTIVE":    "active",
+		"INACTIVE":  "inactive",
+		"CLOSED":    "closed",
+		"CANCELLED": "cancelled",
 	}
 )
@@ -346,4 +346,148 @@ var (
 	ErrAddingUserBadge                = "error adding user badge"
 	ErrGettingTotalInvoiceAmount      = "error getting total invoice amount"
 	ErrGettingTotalBookingAmount      = "error getting total booking amount"
+	ErrInvalidBadgeRuleKey            = "invalid badge rule key condition"
+	ErrInvalidBadgeRuleValue          = "invalid badge rule value"
+	ErrInvalidBadgeRuleOperator       = "invalid badge rule operator"
+	ErrGettingQualifiedUsers          = "error getting users qualified for badge"
+	ErrAddingBadgeSeries              = "error adding badge series"
+	ErrGettingBadgeSeries             = "error getting badge series"
+	ErrScanningBadgeSeries            = "error scanning badge series"
+	ErrDeletingBadgeSeries            = "error deleting badge series"
+	ErrUpdatingBadgeSeries            = "error updating badge series"
+	ErrBadgeSeriesLevelNotFound       = "badge is not a level of this badge series"
+	ErrBadgeSeriesLevelSuperseded     = "a higher level of this badge series is already owned"
+	ErrGettingHighestSeriesLevel      = "error getting highest owned badge series level"
+	ErrGettingClaimedSeriesPoint      = "error getting claimed badge series point"
+	ErrInvalidBadgeSeriesLevels       = "badge series needs at least two levels"
+	ErrCountingBadgeMetric            = "error counting badge rule progress"
+	ErrGettingUserByAdminCode         = "error getting member account of admin"
+	ErrGettingShareCard               = "error getting share card"
+	ErrSavingShareCard                = "error saving share card"
+	ErrRenderingShareCard             = "error rendering share card"
+	ErrUploadingShareCard             = "error uploading share card"
+	ErrInvalidShareCardType           = "invalid share card type (badge|tier|tournament)"
+	ErrShareCardNotEarned             = "share card is only available for earned achievements"
+	ErrAddingOutboxEvent              = "error adding outbox event"
+	ErrGettingOutboxEvents            = "error getting outbox events"
+	ErrUpdatingOutboxEvent            = "error updating outbox event"
+	ErrDeletingOutboxEvents           = "error deleting outbox events"
+	ErrAcquiringJobRun                = "error acquiring worker job run"
+	ErrFinishingJobRun                = "error finishing worker job run"
+	ErrDeletingJobRuns                = "error deleting worker job runs"
+	ErrUnknownWorkerJob               = "unknown worker job"
+	ErrGettingJobRuns                 = "error getting job runs"
+	ErrCountingJobRuns                = "error counting job runs"
+	ErrScanningJobRuns                = "error scanning job runs"
+	ErrWorkerNotReady                 = "worker is not ready"
+	ErrUnknownQueueDriver             = "unknown queue driver (rabbitmq|memory)"
+	ErrGettingWaitlist                = "error getting waitlist"
+	ErrScanningWaitlist               = "error scanning waitlist"
+	ErrJoiningWaitlist                = "error joining waitlist"
+	ErrUpdatingWaitlist               = "error updating waitlist"
+	ErrOfferingWaitlistSeats          = "error offering waitlist seats"
+	ErrAlreadyOnWaitlist              = "You are already on the waitlist"
+	ErrNotOnWaitlist                  = "You are not on the waitlist"
+	ErrWaitlistSeatAvailable          = "A seat is available, please book it directly"
+	ErrWaitlistAlreadyBooked          = "You have already booked this event"
+	ErrCancellingBooking              = "error cancelling booking"
+	ErrNoPendingBooking               = "Only unpaid bookings can be cancelled, please contact an admin for paid bookings"
+	ErrReservingSeat                  = "error reserving seat"
+	ErrSeatHoldExpired                = "Sorry, your seat was released, please book again"
+	ErrReleasingSeatHolds             = "error releasing seat holds"
+	ErrRoomFullyBooked                = "Sorry, this room is fully booked, you can join the waitlist instead"
+	ErrTournamentFullyBooked          = "Sorry, this tournament is fully booked, you can join the waitlist instead"
+	ErrInvalidRecurrenceFrequency     = "wrong frequency value for room series(weekly|biweekly)"
+	ErrInvalidRecurrenceWeekdays      = "weekdays must list at least one day between 0 (sunday) and 6 (saturday)"
+	ErrInvalidRecurrenceEnd           = "room series needs an until date on or after the start date, or a positive occurrence count"
+	ErrInvalidSkipDateFormat          = "invalid skip date format, use YYYY-MM-DD"
+	ErrGettingRoomSeries              = "error getting room series"
+	ErrCountingRoomSeries             = "error counting room series"
+	ErrScanningRoomSeries             = "error scanning room series"
+	ErrAddingRoomSeries               = "error adding room series"
+	ErrUpdatingRoomSeries             = "error updating room series"
+	ErrGeneratingRoomSeries           = "error generating room series occurrences"
+	ErrSubscribingRoomSeries          = "error subscribing to room series"
+	ErrRoomSeriesOccurrenceBooked     = "a skipped date already has bookings, cancel them before skipping it"
+	ErrGettingCheckInToken            = "error getting check-in token"
+	ErrGettingCheckInQrCode           = "error getting check-in qr code"
+	ErrCheckingIn                     = "error checking in"
+	ErrGettingAttendance              = "error getting attendance"
+	ErrScanningAttendance             = "error scanning attendance"
+	ErrMarkingNoShows                 = "error marking no-shows"
+	ErrInvalidCheckInQrCode           = "Invalid check-in QR code"
+	ErrCheckInNotBooked               = "Only members with a paid booking can check in"
+	ErrAlreadyCheckedIn               = "You have already checked in"
+	ErrCheckInClosed                  = "Check-in is only open from 60 minutes before the event starts until it ends"
+	ErrSettingRoomResult              = "error setting room result"
+	ErrGettingLeaderboard             = "error getting leaderboard"
+	ErrScanningLeaderboard            = "error scanning leaderboard"
+	ErrCheckingGameMasterSchedule     = "error checking game master schedule"
+	ErrGettingGameMasterAvailability  = "error getting game master availability"
+	ErrUpdatingGameMasterAvailability = "error updating game master availability"
+	ErrGettingGameMasterDaysOff       = "error getting game master days off"
+	ErrAddingGameMasterDayOff         = "error adding game master day off"
+	ErrDeletingGameMasterDayOff       = "error deleting game master day off"
+	ErrGettingGameMasterSchedule      = "error getting game master schedule"
+	ErrGettingGameMasterWorkload      = "error getting game master workload"
+	ErrGameMasterRoomConflict         = "game master is already hosting room %s (%s) from %s to %s"
+	ErrGameMasterDayOff               = "game master is off on %s"
+	ErrGameMasterUnavailable          = "game master is not available on %s from %s to %s"
+	ErrGameMasterDayOffNotFound       = "game master has no day off on this date"
+	ErrInvalidAvailability            = "availability needs a weekday between 0 (sunday) and 6 (saturday) and a start time before its end time, as HH:mm:ss"
+	ErrInvalidScheduleRange           = "invalid date range, use start_date and end_date as YYYY-MM-DD, at most 92 days apart"
+	ErrInvalidDayOffDateFormat        = "invalid day off date format, use YYYY-MM-DD"
+	ErrGettingCafeTables              = "error getting cafe tables"
+	ErrGettingCafeTableByCode         = "error getting cafe table by code"
+	ErrAddingCafeTable                = "error adding cafe table"
+	ErrUpdatingCafeTable              = "error updating cafe table"
+	ErrDeletingCafeTable              = "error deleting cafe table"
+	ErrAssigningCafeTable             = "error assigning cafe table"
+	ErrCheckingCafeTable              = "error checking cafe table bookings"
+	ErrGettingFloorPlan               = "error getting floor plan"
+	ErrCafeTableConflict              = "table %s is already booked for %s %s (%s) from %s to %s"
+	ErrCafeTableTooSmall              = "table %s has %d seats but the event has %d"
+	ErrCafeTableOtherCity             = "table %s is in %s but the event is in %s"
+	ErrCafeTableInactive              = "table %s is inactive"
+	ErrCafeTableInUse                 = "table is assigned to upcoming rooms or tournaments"
+	ErrInvalidFloorPlanDateFormat     = "invalid floor plan date format, use YYYY-MM-DD"
+	ErrGettingRoomAccess              = "error getting room access"
+	ErrGettingRoomInvite              = "error getting room invite"
+	ErrResettingRoomInvite            = "error resetting room invite"
+	ErrJoiningRoom                    = "error joining room"
+	ErrGettingRoomJoinRequests        = "error getting room join requests"
+	ErrReviewingRoomJoinRequest       = "error reviewing room join request"
+	ErrInvalidInviteCode              = "invalid invite code"
+	ErrRoomPrivate                    = "this room is private, join it with its invite code"
+	ErrRoomJoinPending                = "your request to join this room is waiting for the host's approval"
+	ErrRoomJoinRejected               = "your request to join this room was declined by the host"
+	ErrRoomJoinRequestNotFound        = "this member has no pending request to join the room"
+	ErrGettingRoomProposals           = "error getting room proposals"
+	ErrCountingRoomProposals          = "error counting room proposals"
+	ErrGettingRoomProposal            = "error getting room proposal"
+	ErrAddingRoomProposal             = "error adding room proposal"
+	ErrReviewingRoomProposal          = "error reviewing room proposal"
+	ErrCancellingRoomProposal         = "error cancelling room proposal"
+	ErrRoomProposalNotPending         = "this room proposal was already reviewed or cancelled"
+	ErrRoomProposalPastDate           = "a room can only be proposed for a future date"
+	ErrRoomProposalTime               = "the end time of a room proposal must be after its start time"
+	ErrRoomProposalGameInactive       = "this game is not available for room proposals"
+	ErrCancellingEvent                = "error cancelling event"
+	ErrReversingEventPoint            = "error reversing event points"
+	ErrRefundingTransaction           = "error refunding transaction"
+	ErrAddingEventCancellation        = "error saving event cancellation report"
+	ErrSettlingEventCancellation      = "error settling event cancellation"
+	ErrGettingEventCancellation       = "error getting event cancellation report"
+	ErrEventCancelled                 = "this event was cancelled"
+	ErrEventNotCancellable            = "closed or cancelled events cannot be cancelled"
+	ErrLockingBooking                 = "error locking booking"
+	ErrPaidBookingNotRemovable        = "paid bookings cannot be removed, cancel the event to refund them"
+	ErrGettingCalendarToken           = "error getting calendar token"
+	ErrResettingCalendarToken         = "error resetting calendar token"
+	ErrGettingCalendarEvents          = "error getting calendar events"
+	ErrInvalidCalendarToken           = "invalid calendar token"
+	ErrInvalidMinimalParticipant      = "minimal participant must be between 0 and the maximum participant"
+	ErrGettingMinimumEvents           = "error getting events below their minimum participants"
+	ErrMarkingMinimumWarned           = "error marking event as warned"
+	ErrInvalidBadgeSchedule           = "invalid badge schedule, use format YYYY-MM-DD HH:mm:ss with start before end and expiry after end"
 )
@@ -7,25 +7,29 @@ import (
 )
 
 const (
-	CafePrefix        = "CAFE-"
-	GamePrefix        = "GAME-"
-	BannerPrefix      = "BANNER-"
-	SettingPrefix     = "SET-"
-	UserPrefix        = "USR-"
-	AdminPrefix       = "ADM-"
-	RoomPrefix        = "ROOM-"
-	TournamentPrefix  = "TOUR-"
-	TierPrefix        = "TIER-"
-	BadgePrefix       = "BDG-"
-	ParentBadgePrefix = "PAR-"
-	BadgeRulePrefix   = "BDGRULE-"
-	RewardPrefix      = "RWRD-"
-	RedeemPrefix      = "REEDEM-"
-	TransactionPrefix = "TRX-"
-	RolePrefix        = "ROLE-"
-	PermissionPrefix  = "PRMS-"
-	NotifPrefix       = "NOTIF-"
-	SeasonPrefix      = "SEA-"
+	CafePrefix         = "CAFE-"
+	CafeTablePrefix    = "CTBL-"
+	GamePrefix         = "GAME-"
+	BannerPrefix       = "BANNER-"
+	SettingPrefix      = "SET-"
+	UserPrefix         = "USR-"
+	AdminPrefix        = "ADM-"
+	RoomPrefix         = "ROOM-"
+	RoomSeriesPrefix   = "RSRS-"
+	RoomProposalPrefix = "RPRP-"
+	TournamentPrefix   = "TOUR-"
+	TierPrefix         = "TIER-"
+	BadgePrefix        = "BDG-"
+	ParentBadgePrefix  = "PAR-"
+	BadgeSeriesPrefix  = "SRS-"
+	BadgeRulePrefix    = "BDGRULE-"
+	RewardPrefix       = "RWRD-"
+	RedeemPrefix       = "REEDEM-"
+	TransactionPrefix  = "TRX-"
+	RolePrefix         = "ROLE-"
+	PermissionPrefix   = "PRMS-"
+	NotifPrefix        = "NOTIF-"
+	SeasonPrefix       = "SEA-"
 )
 
 // TODO: Make increment generated prefix based on database data
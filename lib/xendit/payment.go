@@ -11,6 +11,7 @@ import (
 
 	"github.com/xendit/xendit-go/v5/common"
 	"github.com/xendit/xendit-go/v5/invoice"
+	"github.com/xendit/xendit-go/v5/refund"
 )
 
 type XenditClient struct {
@@ -60,6 +61,50 @@ func (x XenditClient) CreateInvoice(amount int64, exCode, payerEmail, desc strin
 	return resp, err
 }
 
+// ExpireInvoice closes an unpaid invoice, so it can no longer be paid.
+func (x XenditClient) ExpireInvoice(invoiceId string) (*invoice.Invoice, *common.XenditSdkError) {
+	client := xendit.NewClient(x.Key)
+
+	resp, httpResponse, err := client.InvoiceApi.ExpireInvoice(context.Background(), invoiceId).Execute()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error when calling `InvoiceApi.ExpireInvoice``: %v\n", err.Error())
+
+		b, _ := json.Marshal(err.FullError())
+		fmt.Fprintf(os.Stderr, "Full Error Struct: %v\n", string(b))
+
+		fmt.Fprintf(os.Stderr, "Full HTTP response: %v\n", httpResponse)
+	}
+
+	return resp, err
+}
+
+// CreateRefund refunds a paid invoice. The reference id keeps a refund of
+// the same transaction from being made twice.
+func (x XenditClient) CreateRefund(invoiceId, referenceId string, amount float64, reason string) (*refund.Refund, *common.XenditSdkError) {
+	client := xendit.NewClient(x.Key)
+
+	createRefund := *refund.NewCreateRefund()
+	createRefund.SetInvoiceId(invoiceId)
+	createRefund.SetReferenceId(referenceId)
+	createRefund.SetAmount(amount)
+	createRefund.SetReason(reason)
+
+	resp, httpResponse, err := client.RefundApi.CreateRefund(context.Background()).
+		IdempotencyKey(referenceId).
+		CreateRefund(createRefund).
+		Execute()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error when calling `RefundApi.CreateRefund``: %v\n", err.Error())
+
+		b, _ := json.Marshal(err.FullError())
+		fmt.Fprintf(os.Stderr, "Full Error Struct: %v\n", string(b))
+
+		fmt.Fprintf(os.Stderr, "Full HTTP response: %v\n", httpResponse)
+	}
+
+	return resp, err
+}
+
 func IsCallbackTokenVerified(token string) bool {
 	callbackToken := viper.GetString("xendit.callback_token")
 
@@ -3,8 +3,10 @@ package main
 import (
 	"dots-api/bootstrap"
 	"dots-api/lib/psql"
+	"dots-api/lib/queue"
 	"dots-api/lib/utils"
 	"dots-api/services/api"
+	"dots-api/services/worker"
 	"dots-api/services/worker/command"
 	"fmt"
 	"log"
@@ -70,6 +72,12 @@ func setup() {
 		panic(err)
 	}
 
+	// queue driver
+	mq, err := queue.New(config)
+	if err != nil {
+		panic(err)
+	}
+
 	app = &bootstrap.App{
 		Debug:     debug,
 		Config:    config,
@@ -78,6 +86,7 @@ func setup() {
 		DB:        db,
 		Redis:     rdCache,
 		NewRelic:  monitoring,
+		Queue:     mq,
 	}
 }
 
@@ -120,6 +129,63 @@ func main() {
 				Usage:  "Scheduler service, Run on crontab",
 				Action: command.Contract{App: app}.UpdateStatusRoomAndTournament,
 			},
+			{
+				Name:   "publish-scheduled-badges",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.PublishScheduledBadges,
+			},
+			{
+				Name:   "process-waitlists",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.ProcessWaitlists,
+			},
+			{
+				Name:   "release-seat-holds",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.ReleaseSeatHolds,
+			},
+			{
+				Name:   "generate-room-series",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.GenerateRoomSeries,
+			},
+			{
+				Name:   "mark-no-shows",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.MarkNoShows,
+			},
+			{
+				Name:   "check-minimum-participants",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.CheckMinimumParticipants,
+			},
+			{
+				Name:   "settle-event-cancellations",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.SettleEventCancellations,
+			},
+			{
+				Name:   "purge-job-runs",
+				Usage:  "Scheduler service, Run on crontab",
+				Action: command.Contract{App: app}.PurgeJobRuns,
+			},
+			{
+				Name:   "outbox-relay",
+				Usage:  "Publish the queue events written to the outbox, Run as a long-running service",
+				Action: command.Contract{App: app}.OutboxRelay,
+			},
+			{
+				Name:   "worker",
+				Usage:  "Run every consumer, the outbox relay and the scheduled jobs of the worker.jobs config in one process",
+				Flags:  worker.Flags,
+				Action: command.Contract{App: app}.Worker,
+			},
+			{
+				Name:   "dead-letters",
+				Usage:  "Inspect or requeue the messages of a queue that failed every attempt",
+				Flags:  command.DeadLetterFlags,
+				Action: command.Contract{App: app}.DeadLetters,
+			},
 		},
 		Action: func(cli *cli.Context) error {
 			fmt.Printf("%s version@%s\n", cli.App.Name, "2.1")
@@ -0,0 +1 @@
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS rule_operator varchar(10) NOT NULL DEFAULT 'all'; --all|any
@@ -0,0 +1 @@
+DELETE FROM permissions WHERE permission_code = 'PRMS-20241019KQWZTRBMPA';
@@ -0,0 +1,4 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019KQWZTRBMPA','member-get-badges-progress','/v1/users/*/badges/progress','GET','member-get-badges-progress','active');
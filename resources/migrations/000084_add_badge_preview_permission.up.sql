@@ -0,0 +1,4 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019NVXHDLQOEC','badge-preview','/v1/badges/preview','POST','badge-preview','active');
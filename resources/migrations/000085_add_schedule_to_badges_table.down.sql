@@ -0,0 +1,4 @@
+ALTER TABLE badges DROP COLUMN IF EXISTS published_date;
+ALTER TABLE badges DROP COLUMN IF EXISTS expired_date;
+ALTER TABLE badges DROP COLUMN IF EXISTS available_end_date;
+ALTER TABLE badges DROP COLUMN IF EXISTS available_start_date;
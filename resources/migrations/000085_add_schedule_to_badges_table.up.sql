@@ -0,0 +1,4 @@
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS available_start_date timestamp NULL;
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS available_end_date timestamp NULL;
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS expired_date timestamp NULL;
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS published_date timestamp NULL;
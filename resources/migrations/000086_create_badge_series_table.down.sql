@@ -0,0 +1,2 @@
+ALTER TABLE badges DROP COLUMN IF EXISTS series_level;
+DROP TABLE IF EXISTS badge_series;
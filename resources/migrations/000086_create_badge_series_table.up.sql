@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS badge_series (
+	id serial PRIMARY KEY,
+	series_code varchar(50) NOT NULL UNIQUE,
+	"name" varchar(100) NOT NULL DEFAULT '',
+	description text NULL,
+	image_url text NULL DEFAULT '',
+	"status" varchar(50) NOT NULL, --active|inactive
+	created_date timestamp NOT NULL DEFAULT NOW(),
+	updated_date timestamp NULL,
+	deleted_date timestamp NULL
+);
+
+-- Levels of a series are badges whose parent_code is the series_code
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS series_level int NULL;
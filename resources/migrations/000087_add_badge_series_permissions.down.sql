@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019HQPLWMZRTA',
+	'PRMS-20241019BXNVKJYEUO',
+	'PRMS-20241019ZCTRQMWNFS',
+	'PRMS-20241019LGDKPAVYEI',
+	'PRMS-20241019OWUFXSBJHC'
+);
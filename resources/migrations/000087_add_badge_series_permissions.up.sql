@@ -0,0 +1,8 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019HQPLWMZRTA','badge-series-get-list','/v1/badge-series','GET','badge-series-get-list','active'),
+('PRMS-20241019BXNVKJYEUO','badge-series-get-detail','/v1/badge-series/*','GET','badge-series-get-detail','active'),
+('PRMS-20241019ZCTRQMWNFS','badge-series-add','/v1/badge-series','POST','badge-series-add','active'),
+('PRMS-20241019LGDKPAVYEI','badge-series-delete','/v1/badge-series/*','DELETE','badge-series-delete','active'),
+('PRMS-20241019OWUFXSBJHC','member-get-badge-series','/v1/users/*/badge-series','GET','member-get-badge-series','active');
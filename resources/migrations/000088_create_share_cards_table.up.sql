@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS share_cards (
+	id bigserial PRIMARY KEY,
+	user_id bigint REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	card_type varchar(50) NOT NULL, --badge|tier|tournament
+	reference_code varchar(50) NOT NULL,
+	content_hash varchar(64) NOT NULL,
+	image_url text NOT NULL,
+	created_date timestamp NOT NULL DEFAULT NOW(),
+	updated_date timestamp NULL,
+	CONSTRAINT user_id_and_card_type_and_reference_code_in_share_cards UNIQUE(user_id, card_type, reference_code)
+);
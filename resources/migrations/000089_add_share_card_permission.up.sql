@@ -0,0 +1,4 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019SHRCRDPNGA','member-create-share-card','/v1/users/*/share-cards','POST','member-create-share-card','active');
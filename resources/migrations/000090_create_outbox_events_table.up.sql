@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+	id bigserial PRIMARY KEY,
+	queue_name varchar(100) NOT NULL,
+	payload jsonb NOT NULL,
+	attempts int NOT NULL DEFAULT 0,
+	last_error text NULL,
+	created_date timestamp NOT NULL DEFAULT NOW(),
+	published_date timestamp NULL
+);
+
+-- The relay only reads the events waiting to be published
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events (id) WHERE published_date IS NULL;
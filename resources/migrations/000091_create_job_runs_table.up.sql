@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+	job_name varchar(100) NOT NULL,
+	scheduled_date timestamp NOT NULL,
+	status varchar(20) NOT NULL DEFAULT 'running',
+	error_message text NULL,
+	item_count int NOT NULL DEFAULT 0,
+	started_date timestamp NOT NULL DEFAULT NOW(),
+	finished_date timestamp NULL,
+	PRIMARY KEY (job_name, scheduled_date)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS job_runs_started_date_idx;
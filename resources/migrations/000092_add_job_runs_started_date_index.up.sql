@@ -0,0 +1,2 @@
+-- The CMS lists the latest runs first
+CREATE INDEX IF NOT EXISTS job_runs_started_date_idx ON job_runs (started_date DESC);
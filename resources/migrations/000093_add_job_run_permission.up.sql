@@ -0,0 +1,4 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019JBRNLSTGTA','job-run-get-list','/v1/job-runs','GET','job-run-get-list','active');
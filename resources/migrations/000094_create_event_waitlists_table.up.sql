@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_waitlists (
+	id bigserial PRIMARY KEY,
+	event_type varchar(20) NOT NULL,
+	event_id bigint NOT NULL,
+	user_id bigint NOT NULL REFERENCES users(id),
+	status varchar(20) NOT NULL DEFAULT 'waiting',
+	offered_date timestamp NULL,
+	offer_expired_date timestamp NULL,
+	created_date timestamp NOT NULL DEFAULT NOW(),
+	updated_date timestamp NULL
+);
+
+-- A member is on the waitlist of an event at most once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS event_waitlists_active_idx ON event_waitlists (event_type, event_id, user_id) WHERE status IN ('waiting', 'offered');
+CREATE INDEX IF NOT EXISTS event_waitlists_queue_idx ON event_waitlists (event_type, event_id, id) WHERE status IN ('waiting', 'offered');
@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019WTLRMJOINA', 'PRMS-20241019WTLRMLEAVA', 'PRMS-20241019WTLRMLISTA', 'PRMS-20241019RMBKCNCLXA', 'PRMS-20241019RMPRTRMVXA',
+	'PRMS-20241019WTLTRJOINA', 'PRMS-20241019WTLTRLEAVA', 'PRMS-20241019WTLTRLISTA', 'PRMS-20241019TRBKCNCLXA', 'PRMS-20241019TRPRTRMVXA',
+	'PRMS-20241019MBRWTLSTXA'
+);
@@ -0,0 +1,14 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019WTLRMJOINA','room-join-waitlist','/v1/rooms/*/waitlist','POST','room-join-waitlist','active'),
+('PRMS-20241019WTLRMLEAVA','room-leave-waitlist','/v1/rooms/*/waitlist','DELETE','room-leave-waitlist','active'),
+('PRMS-20241019WTLRMLISTA','room-get-waitlist','/v1/rooms/*/waitlist','GET','room-get-waitlist','active'),
+('PRMS-20241019RMBKCNCLXA','room-cancel-booking','/v1/rooms/*/cancel','POST','room-cancel-booking','active'),
+('PRMS-20241019RMPRTRMVXA','room-remove-participant','/v1/rooms/*/participants/*','DELETE','room-remove-participant','active'),
+('PRMS-20241019WTLTRJOINA','tournament-join-waitlist','/v1/tournaments/*/waitlist','POST','tournament-join-waitlist','active'),
+('PRMS-20241019WTLTRLEAVA','tournament-leave-waitlist','/v1/tournaments/*/waitlist','DELETE','tournament-leave-waitlist','active'),
+('PRMS-20241019WTLTRLISTA','tournament-get-waitlist','/v1/tournaments/*/waitlist','GET','tournament-get-waitlist','active'),
+('PRMS-20241019TRBKCNCLXA','tournament-cancel-booking','/v1/tournaments/*/cancel','POST','tournament-cancel-booking','active'),
+('PRMS-20241019TRPRTRMVXA','tournament-remove-participant','/v1/tournaments/*/participants/*','DELETE','tournament-remove-participant','active'),
+('PRMS-20241019MBRWTLSTXA','member-get-waitlists','/v1/users/*/waitlists','GET','member-get-waitlists','active');
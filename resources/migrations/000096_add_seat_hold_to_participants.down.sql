@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tournament_participants_hold_idx;
+DROP INDEX IF EXISTS rooms_participants_hold_idx;
+ALTER TABLE tournament_participants DROP COLUMN IF EXISTS hold_expired_date;
+ALTER TABLE rooms_participants DROP COLUMN IF EXISTS hold_expired_date;
@@ -0,0 +1,6 @@
+ALTER TABLE rooms_participants ADD COLUMN IF NOT EXISTS hold_expired_date timestamp NULL;
+ALTER TABLE tournament_participants ADD COLUMN IF NOT EXISTS hold_expired_date timestamp NULL;
+
+-- The worker releases the unpaid bookings whose hold has expired
+CREATE INDEX IF NOT EXISTS rooms_participants_hold_idx ON rooms_participants (hold_expired_date) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS tournament_participants_hold_idx ON tournament_participants (hold_expired_date) WHERE status = 'pending';
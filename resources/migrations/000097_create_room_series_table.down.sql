@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS room_series_subscriptions;
+DROP INDEX IF EXISTS rooms_series_occurrence_idx;
+ALTER TABLE rooms
+DROP COLUMN IF EXISTS is_detached,
+DROP COLUMN IF EXISTS occurrence_date,
+DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS room_series;
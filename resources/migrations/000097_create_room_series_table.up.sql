@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS room_series (
+	id bigserial PRIMARY KEY,
+	series_code varchar(50) NOT NULL UNIQUE,
+	game_master_id bigint REFERENCES admins(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	game_id bigint REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	room_type varchar(50) NOT NULL, --normal|special_event
+	"name" varchar(100) NOT NULL DEFAULT '',
+	"description" text NULL DEFAULT '',
+	instruction text NULL,
+	difficulty varchar(50) NULL DEFAULT '',
+	start_time time NOT NULL,
+	end_time time NOT NULL,
+	maximum_participant int NOT NULL DEFAULT 0,
+	booking_price int NOT NULL DEFAULT 0,
+	reward_point int NOT NULL DEFAULT 0,
+	instagram_link varchar(500) NULL DEFAULT '',
+	image_url varchar(500) NULL DEFAULT '',
+	location_city varchar(50) NULL,
+	frequency varchar(20) NOT NULL, --weekly|biweekly
+	weekdays int[] NOT NULL, --0 (sunday) to 6 (saturday)
+	"start_date" date NOT NULL,
+	until_date date NULL,
+	occurrence_count int NOT NULL DEFAULT 0, --0 means until until_date
+	skip_dates date[] NOT NULL DEFAULT '{}',
+	generated_until date NULL,
+	"status" varchar(50) NOT NULL, --active|inactive
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL
+);
+
+-- Every room of a series is one occurrence. A room edited on its own is
+-- detached and keeps its changes when the whole series is edited.
+ALTER TABLE rooms
+ADD COLUMN IF NOT EXISTS series_id bigint NULL REFERENCES room_series(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS occurrence_date date NULL,
+ADD COLUMN IF NOT EXISTS is_detached boolean NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS rooms_series_occurrence_idx ON rooms (series_id, occurrence_date) WHERE series_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS room_series_subscriptions (
+	id bigserial PRIMARY KEY,
+	series_id bigint NOT NULL REFERENCES room_series(id) ON DELETE CASCADE,
+	user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	UNIQUE (series_id, user_id)
+);
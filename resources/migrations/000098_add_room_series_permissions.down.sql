@@ -0,0 +1,8 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019RSRSLSTGTA',
+	'PRMS-20241019RSRSDTLGTA',
+	'PRMS-20241019RSRSADDPST',
+	'PRMS-20241019RSRSUPDPUT',
+	'PRMS-20241019RSRSSBSCPS',
+	'PRMS-20241019RSRSUNSBDL'
+);
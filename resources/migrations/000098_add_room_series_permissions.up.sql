@@ -0,0 +1,9 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019RSRSLSTGTA','room-series-get-list','/v1/room-series','GET','room-series-get-list','active'),
+('PRMS-20241019RSRSDTLGTA','room-series-get-detail','/v1/room-series/*','GET','room-series-get-detail','active'),
+('PRMS-20241019RSRSADDPST','room-series-add','/v1/room-series','POST','room-series-add','active'),
+('PRMS-20241019RSRSUPDPUT','room-series-update','/v1/room-series/*','PUT','room-series-update','active'),
+('PRMS-20241019RSRSSBSCPS','room-series-subscribe','/v1/room-series/*/subscription','POST','room-series-subscribe','active'),
+('PRMS-20241019RSRSUNSBDL','room-series-unsubscribe','/v1/room-series/*/subscription','DELETE','room-series-unsubscribe','active');
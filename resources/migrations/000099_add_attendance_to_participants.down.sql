@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS tournament_participants_attendance_idx;
+DROP INDEX IF EXISTS rooms_participants_attendance_idx;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS check_in_token;
+ALTER TABLE rooms DROP COLUMN IF EXISTS check_in_token;
+ALTER TABLE tournament_participants
+DROP COLUMN IF EXISTS checked_in_by,
+DROP COLUMN IF EXISTS check_in_method,
+DROP COLUMN IF EXISTS checked_in_date,
+DROP COLUMN IF EXISTS attendance_status;
+ALTER TABLE rooms_participants
+DROP COLUMN IF EXISTS checked_in_by,
+DROP COLUMN IF EXISTS check_in_method,
+DROP COLUMN IF EXISTS checked_in_date,
+DROP COLUMN IF EXISTS attendance_status;
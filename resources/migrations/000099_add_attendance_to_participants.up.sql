@@ -0,0 +1,21 @@
+-- Attendance of a paid booking: expected until the member checks in (attended)
+-- or the event starts without them (no_show)
+ALTER TABLE rooms_participants
+ADD COLUMN IF NOT EXISTS attendance_status varchar(20) NOT NULL DEFAULT 'expected',
+ADD COLUMN IF NOT EXISTS checked_in_date timestamp NULL,
+ADD COLUMN IF NOT EXISTS check_in_method varchar(20) NULL, -- qr|manual
+ADD COLUMN IF NOT EXISTS checked_in_by varchar(50) NULL;
+
+ALTER TABLE tournament_participants
+ADD COLUMN IF NOT EXISTS attendance_status varchar(20) NOT NULL DEFAULT 'expected',
+ADD COLUMN IF NOT EXISTS checked_in_date timestamp NULL,
+ADD COLUMN IF NOT EXISTS check_in_method varchar(20) NULL, -- qr|manual
+ADD COLUMN IF NOT EXISTS checked_in_by varchar(50) NULL;
+
+-- Secret of the check-in QR code shown at the event
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS check_in_token varchar(64) NULL;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS check_in_token varchar(64) NULL;
+
+-- The worker flags the paid bookings still expected after the event started
+CREATE INDEX IF NOT EXISTS rooms_participants_attendance_idx ON rooms_participants (room_id) WHERE status = 'active' AND attendance_status = 'expected';
+CREATE INDEX IF NOT EXISTS tournament_participants_attendance_idx ON tournament_participants (tournament_id) WHERE status = 'active' AND attendance_status = 'expected';
@@ -0,0 +1,10 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019ROOMCHKQRG',
+	'PRMS-20241019TRNMCHKQRG',
+	'PRMS-20241019CHKINSCNPS',
+	'PRMS-20241019ROOMCHKINP',
+	'PRMS-20241019TRNMCHKINP',
+	'PRMS-20241019ROOMATTNDG',
+	'PRMS-20241019TRNMATTNDG',
+	'PRMS-20241019USERATTNDG'
+);
@@ -0,0 +1,11 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019ROOMCHKQRG','room-get-check-in-qr','/v1/rooms/*/check-in-qr','GET','room-get-check-in-qr','active'),
+('PRMS-20241019TRNMCHKQRG','tournament-get-check-in-qr','/v1/tournaments/*/check-in-qr','GET','tournament-get-check-in-qr','active'),
+('PRMS-20241019CHKINSCNPS','check-in','/v1/check-in','POST','check-in','active'),
+('PRMS-20241019ROOMCHKINP','room-participant-check-in','/v1/rooms/*/participants/*/check-in','POST','room-participant-check-in','active'),
+('PRMS-20241019TRNMCHKINP','tournament-participant-check-in','/v1/tournaments/*/participants/*/check-in','POST','tournament-participant-check-in','active'),
+('PRMS-20241019ROOMATTNDG','room-get-attendance','/v1/rooms/*/attendance','GET','room-get-attendance','active'),
+('PRMS-20241019TRNMATTNDG','tournament-get-attendance','/v1/tournaments/*/attendance','GET','tournament-get-attendance','active'),
+('PRMS-20241019USERATTNDG','user-get-attendance','/v1/users/*/attendance','GET','user-get-attendance','active');
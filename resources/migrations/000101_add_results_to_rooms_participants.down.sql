@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS rooms_participants_rank_idx;
+ALTER TABLE rooms_participants
+DROP COLUMN IF EXISTS stats,
+DROP COLUMN IF EXISTS is_tied,
+DROP COLUMN IF EXISTS "rank",
+DROP COLUMN IF EXISTS score;
@@ -0,0 +1,11 @@
+-- Final result of a participant: score, rank (1 is first), whether they share
+-- their rank with another player and game-specific stats such as the faction
+-- or character played
+ALTER TABLE rooms_participants
+ADD COLUMN IF NOT EXISTS score int NULL,
+ADD COLUMN IF NOT EXISTS "rank" int NULL,
+ADD COLUMN IF NOT EXISTS is_tied boolean NOT NULL DEFAULT false,
+ADD COLUMN IF NOT EXISTS stats jsonb NULL;
+
+-- Leaderboards and badge rules read the ranked results
+CREATE INDEX IF NOT EXISTS rooms_participants_rank_idx ON rooms_participants (user_id, "rank") WHERE "rank" IS NOT NULL;
@@ -0,0 +1,4 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019GAMELDBRDG','game-get-leaderboard','/v1/games/*/leaderboard','GET','game-get-leaderboard','active');
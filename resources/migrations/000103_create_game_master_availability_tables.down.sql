@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS rooms_game_master_date_idx;
+DROP TABLE IF EXISTS game_master_days_off;
+DROP TABLE IF EXISTS game_master_availabilities;
@@ -0,0 +1,27 @@
+-- Weekly hours a game master can host rooms. A game master without any row is
+-- available every day.
+CREATE TABLE IF NOT EXISTS game_master_availabilities (
+	id bigserial PRIMARY KEY,
+	admin_id bigint NOT NULL REFERENCES admins(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	weekday smallint NOT NULL, --0 (sunday) to 6 (saturday)
+	start_time time NOT NULL,
+	end_time time NOT NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	CONSTRAINT game_master_availabilities_weekday_check CHECK (weekday BETWEEN 0 AND 6),
+	CONSTRAINT game_master_availabilities_time_check CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS game_master_availabilities_admin_idx ON game_master_availabilities (admin_id, weekday);
+
+-- Days a game master does not host rooms, e.g. leave
+CREATE TABLE IF NOT EXISTS game_master_days_off (
+	id bigserial PRIMARY KEY,
+	admin_id bigint NOT NULL REFERENCES admins(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	off_date date NOT NULL,
+	reason varchar(255) NOT NULL DEFAULT '',
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	CONSTRAINT game_master_days_off_admin_date UNIQUE (admin_id, off_date)
+);
+
+-- Conflict checks look up the rooms of a game master by date
+CREATE INDEX IF NOT EXISTS rooms_game_master_date_idx ON rooms (game_master_id, start_date) WHERE deleted_date IS NULL;
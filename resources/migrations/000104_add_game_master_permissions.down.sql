@@ -0,0 +1,9 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019GMSTWKLGTA',
+	'PRMS-20241019GMSTMESCHG',
+	'PRMS-20241019GMSTSCHGTA',
+	'PRMS-20241019GMSTAVLGTA',
+	'PRMS-20241019GMSTAVLPUT',
+	'PRMS-20241019GMSTDOFPST',
+	'PRMS-20241019GMSTDOFDEL'
+);
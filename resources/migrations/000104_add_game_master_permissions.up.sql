@@ -0,0 +1,10 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019GMSTWKLGTA','game-master-get-workload','/v1/game-masters/workload','GET','game-master-get-workload','active'),
+('PRMS-20241019GMSTMESCHG','game-master-get-own-schedule','/v1/game-masters/me/schedule','GET','game-master-get-own-schedule','active'),
+('PRMS-20241019GMSTSCHGTA','game-master-get-schedule','/v1/game-masters/*/schedule','GET','game-master-get-schedule','active'),
+('PRMS-20241019GMSTAVLGTA','game-master-get-availability','/v1/game-masters/*/availability','GET','game-master-get-availability','active'),
+('PRMS-20241019GMSTAVLPUT','game-master-update-availability','/v1/game-masters/*/availability','PUT','game-master-update-availability','active'),
+('PRMS-20241019GMSTDOFPST','game-master-add-day-off','/v1/game-masters/*/days-off','POST','game-master-add-day-off','active'),
+('PRMS-20241019GMSTDOFDEL','game-master-delete-day-off','/v1/game-masters/*/days-off/*','DELETE','game-master-delete-day-off','active');
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tournaments_table_date_idx;
+DROP INDEX IF EXISTS rooms_table_date_idx;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS table_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS table_id;
+DROP TABLE IF EXISTS cafe_tables;
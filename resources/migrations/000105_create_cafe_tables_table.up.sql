@@ -0,0 +1,23 @@
+-- Physical tables of a cafe rooms and tournaments are played at
+CREATE TABLE IF NOT EXISTS cafe_tables (
+	id bigserial PRIMARY KEY,
+	cafe_id bigint NOT NULL REFERENCES cafes(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	table_code varchar(50) NOT NULL UNIQUE,
+	"name" varchar(100) NOT NULL DEFAULT '',
+	table_type varchar(50) NOT NULL, --standard|large|rpg
+	seats int NOT NULL,
+	"status" varchar(50) NOT NULL, --active|inactive
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	deleted_date timestamptz(0) NULL,
+	CONSTRAINT cafe_tables_seats_check CHECK (seats > 0)
+);
+
+CREATE INDEX IF NOT EXISTS cafe_tables_cafe_idx ON cafe_tables (cafe_id) WHERE deleted_date IS NULL;
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS table_id bigint NULL REFERENCES cafe_tables(id) ON DELETE SET NULL;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS table_id bigint NULL REFERENCES cafe_tables(id) ON DELETE SET NULL;
+
+-- Double booking checks and floor plans look up the events of a table by date
+CREATE INDEX IF NOT EXISTS rooms_table_date_idx ON rooms (table_id, start_date) WHERE table_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS tournaments_table_date_idx ON tournaments (table_id, start_date) WHERE table_id IS NOT NULL;
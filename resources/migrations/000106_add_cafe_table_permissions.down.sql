@@ -0,0 +1,9 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019CFTBLSGTAL',
+	'PRMS-20241019CFTBLSPOST',
+	'PRMS-20241019CFTBLSUPDT',
+	'PRMS-20241019CFTBLSDELT',
+	'PRMS-20241019CFFLRPLNGT',
+	'PRMS-20241019ROOMTBLPUT',
+	'PRMS-20241019TOURTBLPUT'
+);
@@ -0,0 +1,10 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019CFTBLSGTAL','cafe-get-tables','/v1/cafes/*/tables','GET','cafe-get-tables','active'),
+('PRMS-20241019CFTBLSPOST','cafe-add-table','/v1/cafes/*/tables','POST','cafe-add-table','active'),
+('PRMS-20241019CFTBLSUPDT','cafe-update-table','/v1/cafes/*/tables/*','PUT','cafe-update-table','active'),
+('PRMS-20241019CFTBLSDELT','cafe-delete-table','/v1/cafes/*/tables/*','DELETE','cafe-delete-table','active'),
+('PRMS-20241019CFFLRPLNGT','cafe-get-floor-plan','/v1/cafes/*/floor-plan','GET','cafe-get-floor-plan','active'),
+('PRMS-20241019ROOMTBLPUT','room-assign-table','/v1/rooms/*/table','PUT','room-assign-table','active'),
+('PRMS-20241019TOURTBLPUT','tournament-assign-table','/v1/tournaments/*/table','PUT','tournament-assign-table','active');
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS room_join_requests;
+
+ALTER TABLE rooms
+DROP COLUMN IF EXISTS requires_approval,
+DROP COLUMN IF EXISTS invite_code,
+DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,20 @@
+-- Private rooms are hidden from public listings and joined with their invite
+-- code, after approval by the host when the room asks for it.
+ALTER TABLE rooms
+ADD COLUMN IF NOT EXISTS visibility varchar(20) NOT NULL DEFAULT 'public', --public|private
+ADD COLUMN IF NOT EXISTS invite_code varchar(50) NULL UNIQUE,
+ADD COLUMN IF NOT EXISTS requires_approval boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS room_join_requests (
+	id bigserial PRIMARY KEY,
+	room_id bigint NOT NULL REFERENCES rooms(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	"status" varchar(20) NOT NULL, --pending|approved|rejected
+	reviewed_by varchar(50) NULL,
+	reviewed_date timestamptz(0) NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	CONSTRAINT room_join_requests_room_user UNIQUE (room_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS room_join_requests_room_status_idx ON room_join_requests (room_id, status);
@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019ROOMINVGTA',
+	'PRMS-20241019ROOMINVRST',
+	'PRMS-20241019ROOMJOINPS',
+	'PRMS-20241019ROOMJREQGT',
+	'PRMS-20241019ROOMJREQPT'
+);
@@ -0,0 +1,8 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019ROOMINVGTA','room-get-invite','/v1/rooms/*/invite','GET','room-get-invite','active'),
+('PRMS-20241019ROOMINVRST','room-reset-invite','/v1/rooms/*/invite/reset','POST','room-reset-invite','active'),
+('PRMS-20241019ROOMJOINPS','room-join-by-invite','/v1/rooms/join','POST','room-join-by-invite','active'),
+('PRMS-20241019ROOMJREQGT','room-get-join-requests','/v1/rooms/*/join-requests','GET','room-get-join-requests','active'),
+('PRMS-20241019ROOMJREQPT','room-review-join-request','/v1/rooms/*/join-requests/*','PUT','room-review-join-request','active');
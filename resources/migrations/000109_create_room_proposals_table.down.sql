@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS rooms_host_user_idx;
+ALTER TABLE rooms DROP COLUMN IF EXISTS host_user_id;
+DROP TABLE IF EXISTS room_proposals;
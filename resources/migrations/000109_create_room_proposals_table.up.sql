@@ -0,0 +1,29 @@
+-- Sessions proposed by members. A cafe admin approves a proposal into a room
+-- hosted by the proposing member, or rejects it.
+CREATE TABLE IF NOT EXISTS room_proposals (
+	id bigserial PRIMARY KEY,
+	proposal_code varchar(50) NOT NULL UNIQUE,
+	user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	game_id bigint NOT NULL REFERENCES games(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	"name" varchar(100) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	start_date date NOT NULL,
+	start_time time NOT NULL,
+	end_time time NOT NULL,
+	maximum_participant int NOT NULL,
+	"status" varchar(20) NOT NULL DEFAULT 'pending', --pending|approved|rejected|cancelled
+	room_id bigint NULL REFERENCES rooms(id) ON DELETE SET NULL,
+	reject_reason varchar(500) NULL,
+	reviewed_by varchar(50) NULL,
+	reviewed_date timestamptz(0) NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	CONSTRAINT room_proposals_maximum_participant_check CHECK (maximum_participant > 0)
+);
+
+CREATE INDEX IF NOT EXISTS room_proposals_status_idx ON room_proposals (status, start_date);
+CREATE INDEX IF NOT EXISTS room_proposals_user_idx ON room_proposals (user_id);
+
+-- Member hosting a room made from their proposal
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS host_user_id bigint NULL REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS rooms_host_user_idx ON rooms (host_user_id) WHERE host_user_id IS NOT NULL;
@@ -0,0 +1,8 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019RMPROPGTAL',
+	'PRMS-20241019RMPROPPOST',
+	'PRMS-20241019RMPROPGTDT',
+	'PRMS-20241019RMPROPDELT',
+	'PRMS-20241019RMPROPAPRV',
+	'PRMS-20241019RMPROPRJCT'
+);
@@ -0,0 +1,9 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019RMPROPGTAL','room-proposal-get-all','/v1/room-proposals','GET','room-proposal-get-all','active'),
+('PRMS-20241019RMPROPPOST','room-proposal-add','/v1/room-proposals','POST','room-proposal-add','active'),
+('PRMS-20241019RMPROPGTDT','room-proposal-get-detail','/v1/room-proposals/*','GET','room-proposal-get-detail','active'),
+('PRMS-20241019RMPROPDELT','room-proposal-cancel','/v1/room-proposals/*','DELETE','room-proposal-cancel','active'),
+('PRMS-20241019RMPROPAPRV','room-proposal-approve','/v1/room-proposals/*/approve','PUT','room-proposal-approve','active'),
+('PRMS-20241019RMPROPRJCT','room-proposal-reject','/v1/room-proposals/*/reject','PUT','room-proposal-reject','active');
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS event_cancellations (
+    id bigserial PRIMARY KEY,
+    event_type varchar(20) NOT NULL, -- room|tournament
+    event_id bigint NOT NULL,
+    reason varchar(500) NOT NULL DEFAULT '',
+    cancelled_by varchar(50) NOT NULL DEFAULT '', -- admin code
+    created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+    CONSTRAINT event_type_and_event_id_in_event_cancellations UNIQUE (event_type, event_id)
+);
+
+-- The outcome of the cancellation for every member booked on the event
+CREATE TABLE IF NOT EXISTS event_cancellation_participants (
+    id bigserial PRIMARY KEY,
+    cancellation_id bigint NOT NULL REFERENCES event_cancellations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    participant_status varchar(50) NOT NULL, -- active|pending
+    transaction_code varchar(50) NOT NULL DEFAULT '',
+    amount bigint NOT NULL DEFAULT 0,
+    refund_status varchar(30) NOT NULL, -- refunded|refund_failed|invoice_expired|expire_failed|no_payment
+    refund_id varchar(100) NOT NULL DEFAULT '',
+    refund_error text NOT NULL DEFAULT '',
+    reversed_point int NOT NULL DEFAULT 0,
+    push_sent boolean NOT NULL DEFAULT FALSE,
+    email_sent boolean NOT NULL DEFAULT FALSE,
+    created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+    CONSTRAINT cancellation_id_and_user_id_in_event_cancellation_participants UNIQUE (cancellation_id, user_id)
+);
@@ -0,0 +1,6 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019ROOMCNCLEV',
+	'PRMS-20241019ROOMCNCLGT',
+	'PRMS-20241019TRNMCNCLEV',
+	'PRMS-20241019TRNMCNCLGT'
+);
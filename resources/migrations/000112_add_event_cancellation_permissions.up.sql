@@ -0,0 +1,7 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019ROOMCNCLEV','room-cancel-event','/v1/rooms/*/cancel-event','POST','room-cancel-event','active'),
+('PRMS-20241019ROOMCNCLGT','room-cancellation-get','/v1/rooms/*/cancellation','GET','room-cancellation-get','active'),
+('PRMS-20241019TRNMCNCLEV','tournament-cancel-event','/v1/tournaments/*/cancel-event','POST','tournament-cancel-event','active'),
+('PRMS-20241019TRNMCNCLGT','tournament-cancellation-get','/v1/tournaments/*/cancellation','GET','tournament-cancellation-get','active');
@@ -0,0 +1,3 @@
+-- Secret of the calendar feed of a member, created the first time they ask for it
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_token varchar(64) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_calendar_token_idx ON users (calendar_token);
@@ -0,0 +1,4 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019CALFEEDGET',
+	'PRMS-20241019CALFEEDRST'
+);
@@ -0,0 +1,5 @@
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019CALFEEDGET','calendar-feed-get','/v1/calendar/me','GET','calendar-feed-get','active'),
+('PRMS-20241019CALFEEDRST','calendar-feed-reset','/v1/calendar/me/reset','POST','calendar-feed-reset','active');
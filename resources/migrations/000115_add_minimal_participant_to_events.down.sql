@@ -0,0 +1,4 @@
+ALTER TABLE tournaments DROP COLUMN IF EXISTS minimum_warned_date;
+ALTER TABLE tournaments DROP COLUMN IF EXISTS minimal_participant;
+ALTER TABLE rooms DROP COLUMN IF EXISTS minimum_warned_date;
+ALTER TABLE rooms DROP COLUMN IF EXISTS minimal_participant;
@@ -0,0 +1,6 @@
+-- The minimum of a room was dropped in 000043 before anything enforced it. It
+-- is back for rooms and tournaments, 0 means the event has no minimum.
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS minimal_participant int NOT NULL DEFAULT 0;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS minimum_warned_date timestamptz NULL;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS minimal_participant int NOT NULL DEFAULT 0;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS minimum_warned_date timestamptz NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS admins_user_id_unique;
+ALTER TABLE admins DROP COLUMN IF EXISTS user_id;
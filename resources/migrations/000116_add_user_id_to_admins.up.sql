@@ -0,0 +1,9 @@
+-- Links a game master to their member account. Existing admins are linked by
+-- email once, later links are set from the CMS.
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS user_id bigint NULL REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE admins a SET user_id = u.id
+FROM users u
+WHERE lower(u.email) = lower(a.email) AND u.deleted_date IS NULL AND a.user_id IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS admins_user_id_unique ON admins (user_id) WHERE user_id IS NOT NULL AND deleted_date IS NULL;
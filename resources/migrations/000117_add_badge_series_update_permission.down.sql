@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019BSRSUPDATE'
+);
@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE permission_code IN (
+	'PRMS-20241019BSRSUPDATE'
+);
+INSERT INTO permissions
+(permission_code, "name", route_pattern, route_method, description, status)
+VALUES
+('PRMS-20241019BSRSUPDATE','badge-series-update','/v1/badge-series/*','PUT','badge-series-update','active');
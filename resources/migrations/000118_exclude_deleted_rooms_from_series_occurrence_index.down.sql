@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS rooms_series_occurrence_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS rooms_series_occurrence_idx ON rooms (series_id, occurrence_date) WHERE series_id IS NOT NULL;
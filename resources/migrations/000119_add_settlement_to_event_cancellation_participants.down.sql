@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS event_cancellation_participants_unsettled_idx;
+
+ALTER TABLE event_cancellation_participants
+    DROP COLUMN IF EXISTS settle_attempts,
+    DROP COLUMN IF EXISTS notified_date,
+    DROP COLUMN IF EXISTS settled_date;
@@ -0,0 +1,13 @@
+-- Bookings of cancelled events are settled by the settle-event-cancellations
+-- job, which retries failed refunds up to a number of attempts
+ALTER TABLE event_cancellation_participants
+    ADD COLUMN IF NOT EXISTS settle_attempts int NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS notified_date timestamptz(0) NULL,
+    ADD COLUMN IF NOT EXISTS settled_date timestamptz(0) NULL;
+
+-- The bookings cancelled so far were settled and notified when they were cancelled
+UPDATE event_cancellation_participants
+SET settle_attempts = 1, notified_date = created_date,
+    settled_date = CASE WHEN refund_status IN ('refund_failed', 'expire_failed') THEN NULL ELSE created_date END;
+
+CREATE INDEX IF NOT EXISTS event_cancellation_participants_unsettled_idx ON event_cancellation_participants (id) WHERE settled_date IS NULL;
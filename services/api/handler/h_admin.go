@@ -93,9 +93,16 @@ func (h *Contract) AddAdminAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Link the member account of a game master
+	userId, err := h.getAdminUserId(ctx, req.UserCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
 	// Generate Random Code
 	code := utils.GeneratePrefixCode(utils.AdminPrefix)
-	err = m.AddAdmin(h.DB, ctx, code, req.Email, req.Name, req.UserName, req.Password, req.Status, req.Role, req.PhoneNumber, req.ImageUrl)
+	err = m.AddAdmin(h.DB, ctx, code, req.Email, req.Name, req.UserName, req.Password, req.Status, req.Role, req.PhoneNumber, req.ImageUrl, userId)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -129,6 +136,7 @@ func (h *Contract) GetAdminDetailAct(w http.ResponseWriter, r *http.Request) {
 		ImageURL:    data.ImageURL,
 		PhoneNumber: data.PhoneNumber,
 		Role:        data.Role,
+		UserCode:    data.UserCode,
 	}, nil)
 }
 
@@ -163,7 +171,13 @@ func (h *Contract) UpdateAdminAct(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err = m.UpdateAdminByCode(h.DB, ctx, code, req.Email, req.Name, req.UserName, req.Password, req.Status, req.Role, req.PhoneNumber, req.ImageUrl)
+	userId, err := h.getAdminUserId(ctx, req.UserCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.UpdateAdminByCode(h.DB, ctx, code, req.Email, req.Name, req.UserName, req.Password, req.Status, req.Role, req.PhoneNumber, req.ImageUrl, userId)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -238,3 +252,19 @@ func ToResponseAdminList(data []model.AdminEnt) []response.AdminRes {
 
 	return res
 }
+
+// getAdminUserId returns the member account to link to an admin, or no account
+// when userCode is empty.
+func (h *Contract) getAdminUserId(ctx context.Context, userCode string) (sql.NullInt64, error) {
+	if userCode == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	m := model.Contract{App: h.App}
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	return sql.NullInt64{Int64: userId, Valid: true}, nil
+}
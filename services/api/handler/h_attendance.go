@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"dots-api/bootstrap"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Contract) GetRoomCheckInQRAct(w http.ResponseWriter, r *http.Request) {
+	h.getCheckInQR(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) GetTournamentCheckInQRAct(w http.ResponseWriter, r *http.Request) {
+	h.getCheckInQR(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) CheckInRoomParticipantAct(w http.ResponseWriter, r *http.Request) {
+	h.checkInParticipant(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) CheckInTournamentParticipantAct(w http.ResponseWriter, r *http.Request) {
+	h.checkInParticipant(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) GetRoomAttendanceAct(w http.ResponseWriter, r *http.Request) {
+	h.getEventAttendance(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) GetTournamentAttendanceAct(w http.ResponseWriter, r *http.Request) {
+	h.getEventAttendance(w, r, utils.WaitlistTournament)
+}
+
+// CheckInAct checks the member in to the event of the QR code they scanned.
+// Check-in opens utils.CheckInOpenMinutes before the event starts and closes
+// when it ends.
+func (h *Contract) CheckInAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		req      = request.CheckInReq{}
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+		now      = time.Now()
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	eventType, eventCode, token, err := req.Parse()
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	event, err := h.getBookingEvent(ctx, m, eventType, eventCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	eventToken, err := m.GetCheckInToken(h.DB, ctx, eventType, event.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(eventToken)) != 1 {
+		h.SendBadRequest(w, utils.ErrInvalidCheckInQrCode)
+		return
+	}
+
+	if now.Before(event.StartDate.Add(-time.Duration(utils.CheckInOpenMinutes)*time.Minute)) || now.After(event.EndDate) {
+		h.SendBadRequest(w, utils.ErrCheckInClosed)
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = h.checkIn(ctx, m, eventType, event, userId, utils.CheckInMethodQR, "")
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// GetUserAttendanceAct lists the attendance of a member for the events they
+// paid for.
+func (h *Contract) GetUserAttendanceAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetUserAttendance(h.DB, ctx, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	summary, events := attendanceRes(list)
+	h.SendSuccess(w, response.UserAttendanceRes{Summary: summary, Events: events}, nil)
+}
+
+// getCheckInQR returns the check-in QR code of an event for the staff to show
+// at the venue.
+func (h *Contract) getCheckInQR(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	token, err := m.GetCheckInToken(h.DB, ctx, eventType, event.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	qr, err := m.GetCheckInQRCode(ctx, eventType, event.Code, token)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, qr, nil)
+}
+
+// checkInParticipant checks a member in from the CMS, e.g. when they cannot
+// scan the QR code. Staff can check members in at any time.
+func (h *Contract) checkInParticipant(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		code      = chi.URLParam(r, "code")
+		userCode  = chi.URLParam(r, "user_code")
+		adminCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = h.checkIn(ctx, m, eventType, event, userId, utils.CheckInMethodManual, adminCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// checkIn records the attendance of a member with a paid booking. When
+// attendance.award_point_on_check_in is set, the participation VP of a room is
+// awarded here instead of on payment.
+func (h *Contract) checkIn(ctx context.Context, m model.Contract, eventType string, event bookingEvent, userId int64, method, checkedInBy string) error {
+	participant, err := m.GetEventParticipant(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		return err
+	}
+	if participant.Status != "active" {
+		return errors.New(utils.ErrCheckInNotBooked)
+	}
+	if participant.CheckedInDate.Valid {
+		return errors.New(utils.ErrAlreadyCheckedIn)
+	}
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	checkedIn, err := m.CheckInEventParticipantTrx(tx, ctx, eventType, event.Id, userId, method, checkedInBy)
+	if err != nil {
+		return err
+	}
+	if !checkedIn {
+		return errors.New(utils.ErrAlreadyCheckedIn)
+	}
+
+	if eventType == utils.WaitlistRoom && h.Config.GetBool("attendance.award_point_on_check_in") {
+		err = m.AddUserPoint(tx, ctx, userId, utils.UserPointType["ROOM_TYPE"], event.Code, event.RewardPoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// getEventAttendance returns the attendance report of an event for the CMS.
+func (h *Contract) getEventAttendance(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetEventAttendance(h.DB, ctx, eventType, event.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	summary, participants := attendanceRes(list)
+	h.SendSuccess(w, response.EventAttendanceRes{Summary: summary, Participants: participants}, nil)
+}
+
+// attendanceRes maps attendance records and counts them. The attendance rate
+// is the percentage of the settled bookings, attended or no-show, that
+// attended.
+func attendanceRes(list []model.AttendanceEnt) (response.AttendanceSummaryRes, []response.AttendanceRes) {
+	var (
+		summary = response.AttendanceSummaryRes{Booked: len(list)}
+		res     = make([]response.AttendanceRes, 0)
+	)
+
+	for _, v := range list {
+		switch v.AttendanceStatus {
+		case utils.AttendanceAttended:
+			summary.Attended++
+		case utils.AttendanceNoShow:
+			summary.NoShow++
+		default:
+			summary.Expected++
+		}
+
+		var eventStartDate, checkedInDate string
+		if v.EventStartDate.Valid {
+			eventStartDate = v.EventStartDate.Time.Format(utils.DATE_FORMAT)
+		}
+		if v.CheckedInDate.Valid {
+			checkedInDate = v.CheckedInDate.Time.Format(utils.DATE_TIME_FORMAT)
+		}
+
+		res = append(res, response.AttendanceRes{
+			EventType:        v.EventType,
+			EventCode:        v.EventCode,
+			EventName:        v.EventName,
+			EventStartDate:   eventStartDate,
+			UserCode:         v.UserCode,
+			UserName:         v.UserName,
+			AttendanceStatus: v.AttendanceStatus,
+			CheckedInDate:    checkedInDate,
+			CheckInMethod:    v.CheckInMethod.String,
+			CheckedInBy:      v.CheckedInBy.String,
+		})
+	}
+
+	if settled := summary.Attended + summary.NoShow; settled > 0 {
+		summary.AttendanceRate = summary.Attended * 100 / settled
+	}
+
+	return summary, res
+}
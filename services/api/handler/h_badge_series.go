@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetBadgeSeriesListAct ...
+func (h *Contract) GetBadgeSeriesListAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		ctx = context.TODO()
+		m   = model.Contract{App: h.App}
+		res = make([]response.BadgeSeriesRes, 0)
+	)
+
+	series, err := m.GetBadgeSeriesList(h.DB, ctx, false)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range series {
+		levels, err := h.getBadgeSeriesLevelRes(ctx, v.SeriesCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		res = append(res, response.BadgeSeriesRes{
+			SeriesCode:  v.SeriesCode,
+			Name:        v.Name,
+			Description: v.Description.String,
+			ImageURL:    v.ImageURL,
+			Status:      v.Status,
+			Levels:      levels,
+			CreatedDate: v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+		})
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// GetBadgeSeriesDetailAct ...
+func (h *Contract) GetBadgeSeriesDetailAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		code = chi.URLParam(r, "code")
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+	)
+
+	series, err := m.GetBadgeSeriesByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	levels, err := h.getBadgeSeriesLevelRes(ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, response.BadgeSeriesRes{
+		SeriesCode:  series.SeriesCode,
+		Name:        series.Name,
+		Description: series.Description.String,
+		ImageURL:    series.ImageURL,
+		Status:      series.Status,
+		Levels:      levels,
+		CreatedDate: series.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+	}, nil)
+}
+
+// AddBadgeSeriesAct creates a badge series with one badge per level. The
+// levels are ordered as given, lowest first.
+func (h *Contract) AddBadgeSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err        error
+		req        = request.BadgeSeriesReq{}
+		ctx        = context.TODO()
+		m          = model.Contract{App: h.App}
+		seriesCode = utils.GeneratePrefixCode(utils.BadgeSeriesPrefix)
+		badgeCodes = make([]string, 0)
+	)
+
+	// Binding and Validate
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if !utils.Contains(utils.StatusBadges, req.Status) {
+		h.SendBadRequest(w, "wrong status value for badge(active|inactive")
+		return
+	}
+
+	if len(req.Levels) < 2 {
+		h.SendBadRequest(w, utils.ErrInvalidBadgeSeriesLevels)
+		return
+	}
+
+	for i := range req.Levels {
+		for j, rule := range req.Levels[i].BadgeRule {
+			req.Levels[i].BadgeRule[j].Value, err = parseBadgeRuleValue(rule.KeyCondition, rule.Value)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+	}
+
+	// Db tx start
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.AddBadgeSeries(tx, ctx, seriesCode, req.Name, req.Description, req.ImageURL, req.Status)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for i, level := range req.Levels {
+		badgeCode := utils.GeneratePrefixCode(utils.BadgePrefix)
+
+		ruleOperator := level.RuleOperator
+		if ruleOperator == "" {
+			ruleOperator = utils.BadgeRuleOperatorAll
+		}
+
+		badgeID, err := m.AddBadge(tx, ctx, badgeCode, req.BadgeCategory, level.Name, level.ImageURL, level.VPPoint, req.Status, level.Description, seriesCode, ruleOperator)
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		err = m.SetBadgeSeriesLevel(tx, ctx, badgeCode, i+1)
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		for _, rule := range level.BadgeRule {
+			badgeRuleCode := utils.GeneratePrefixCode(utils.BadgeRulePrefix)
+			err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, rule.Value)
+			if err != nil {
+				tx.Rollback(ctx)
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+
+		badgeCodes = append(badgeCodes, badgeCode)
+	}
+
+	// The badge worker checks a series as a whole from any of its levels
+	if req.Status == "active" {
+		err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
+			rabbit.QueueBadges,
+			rabbit.QueueBadgeReq(
+				badgeCodes[0],
+			),
+		))
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	// Db tx commit
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// UpdateBadgeSeriesAct updates a badge series and its levels. Levels given with
+// the code of an existing level are updated, the others are added, and existing
+// levels left out are deleted. The levels are reordered as given, lowest first.
+func (h *Contract) UpdateBadgeSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err        error
+		req        = request.BadgeSeriesReq{}
+		code       = chi.URLParam(r, "code")
+		ctx        = context.TODO()
+		m          = model.Contract{App: h.App}
+		badgeCodes = make([]string, 0)
+	)
+
+	// Binding and Validate
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if !utils.Contains(utils.StatusBadges, req.Status) {
+		h.SendBadRequest(w, "wrong status value for badge(active|inactive")
+		return
+	}
+
+	if len(req.Levels) < 2 {
+		h.SendBadRequest(w, utils.ErrInvalidBadgeSeriesLevels)
+		return
+	}
+
+	if _, err = m.GetBadgeSeriesByCode(h.DB, ctx, code); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	levels, err := m.GetBadgeSeriesLevels(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	existing := make(map[string]int64, len(levels))
+	for _, level := range levels {
+		existing[level.BadgeCode] = level.Id
+	}
+
+	kept := make(map[string]bool, len(req.Levels))
+	for i := range req.Levels {
+		if badgeCode := req.Levels[i].BadgeCode; badgeCode != "" {
+			if _, ok := existing[badgeCode]; !ok {
+				h.SendBadRequest(w, utils.ErrBadgeSeriesLevelNotFound)
+				return
+			}
+			kept[badgeCode] = true
+		}
+
+		for j, rule := range req.Levels[i].BadgeRule {
+			req.Levels[i].BadgeRule[j].Value, err = parseBadgeRuleValue(rule.KeyCondition, rule.Value)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+	}
+
+	// Db tx start
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.UpdateBadgeSeries(tx, ctx, code, req.Name, req.Description, req.ImageURL, req.Status)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for badgeCode, badgeID := range existing {
+		if kept[badgeCode] {
+			continue
+		}
+
+		err = m.DeleteBadge(tx, ctx, badgeID)
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	for i, level := range req.Levels {
+		ruleOperator := level.RuleOperator
+		if ruleOperator == "" {
+			ruleOperator = utils.BadgeRuleOperatorAll
+		}
+
+		badgeCode := level.BadgeCode
+		badgeID, ok := existing[badgeCode]
+		if ok {
+			err = m.UpdateBadgeByCode(tx, ctx, req.BadgeCategory, level.Name, level.Description, level.ImageURL, req.Status, level.VPPoint, ruleOperator, badgeCode)
+			if err == nil {
+				err = m.DeleteBadgeRule(tx, ctx, badgeID)
+			}
+		} else {
+			badgeCode = utils.GeneratePrefixCode(utils.BadgePrefix)
+			badgeID, err = m.AddBadge(tx, ctx, badgeCode, req.BadgeCategory, level.Name, level.ImageURL, level.VPPoint, req.Status, level.Description, code, ruleOperator)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		err = m.SetBadgeSeriesLevel(tx, ctx, badgeCode, i+1)
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		for _, rule := range level.BadgeRule {
+			badgeRuleCode := utils.GeneratePrefixCode(utils.BadgeRulePrefix)
+			err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, rule.Value)
+			if err != nil {
+				tx.Rollback(ctx)
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+
+		badgeCodes = append(badgeCodes, badgeCode)
+	}
+
+	// The badge worker checks a series as a whole from any of its levels
+	if req.Status == "active" {
+		err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
+			rabbit.QueueBadges,
+			rabbit.QueueBadgeReq(
+				badgeCodes[0],
+			),
+		))
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	// Db tx commit
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// DeleteBadgeSeriesAct deletes a badge series together with its levels.
+func (h *Contract) DeleteBadgeSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		code = chi.URLParam(r, "code")
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+	)
+
+	if _, err = m.GetBadgeSeriesByCode(h.DB, ctx, code); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// Db tx start
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.DeleteBadgeSeries(tx, ctx, code)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// Db tx commit
+	err = tx.Commit(ctx)
+	if err != nil {
+		tx.Rollback(ctx)
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// getBadgeSeriesLevelRes builds the badge response of every level of a series.
+func (h *Contract) getBadgeSeriesLevelRes(ctx context.Context, seriesCode string) ([]response.BadgeRes, error) {
+	var (
+		m   = model.Contract{App: h.App}
+		res = make([]response.BadgeRes, 0)
+	)
+
+	levels, err := m.GetBadgeSeriesLevels(h.DB, ctx, seriesCode)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range levels {
+		badgeRules, err := m.GetBadgeRuleList(h.DB, ctx, v.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		var badgeRuleResSlice []response.BadgeRuleRes
+		for _, rule := range badgeRules {
+			badgeRuleResSlice = append(badgeRuleResSlice, response.BadgeRuleRes{
+				BadgeRuleCode: rule.BadgeRuleCode,
+				BadgeId:       rule.BadgeId,
+				KeyCondition:  rule.KeyCondition,
+				ValueType:     rule.ValueType,
+				Value:         rule.Value,
+			})
+		}
+
+		res = append(res, response.BadgeRes{
+			BadgeCode:          v.BadgeCode,
+			BadgeCategory:      v.BadgeCategory,
+			Name:               v.Name,
+			ImageURL:           v.ImageURL,
+			BadgeRules:         badgeRuleResSlice,
+			Description:        v.Description.String,
+			VPPoint:            v.VPPoint,
+			Status:             v.Status,
+			ParentCode:         v.ParentCode.String,
+			RuleOperator:       v.RuleOperator,
+			SeriesLevel:        v.SeriesLevel.Int64,
+			AvailableStartDate: formatNullTime(v.AvailableStartDate),
+			AvailableEndDate:   formatNullTime(v.AvailableEndDate),
+			ExpiredDate:        formatNullTime(v.ExpiredDate),
+			CreatedDate:        v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+			UpdatedDate:        v.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
+			DeletedDate:        v.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
+		})
+	}
+
+	return res, nil
+}
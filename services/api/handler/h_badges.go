@@ -2,17 +2,20 @@ package handler
 
 import (
 	"context"
+	"database/sql"
 	"dots-api/bootstrap"
+	"dots-api/lib/badge"
 	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
 	"dots-api/services/api/request"
 	"dots-api/services/api/response"
-	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
 )
 
 // GetBadgeListAct ...
@@ -63,18 +66,23 @@ func (h *Contract) GetBadgeListAct(w http.ResponseWriter, r *http.Request) {
 		}
 
 		res = append(res, response.BadgeRes{
-			BadgeCode:     v.BadgeCode,
-			BadgeCategory: v.BadgeCategory,
-			Name:          v.Name,
-			ImageURL:      v.ImageURL,
-			BadgeRules:    badgeRuleResSlice,
-			Description:   v.Description.String,
-			VPPoint:       v.VPPoint,
-			Status:        v.Status,
-			ParentCode:    v.ParentCode.String,
-			CreatedDate:   v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
-			UpdatedDate:   v.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
-			DeletedDate:   v.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
+			BadgeCode:          v.BadgeCode,
+			BadgeCategory:      v.BadgeCategory,
+			Name:               v.Name,
+			ImageURL:           v.ImageURL,
+			BadgeRules:         badgeRuleResSlice,
+			Description:        v.Description.String,
+			VPPoint:            v.VPPoint,
+			Status:             v.Status,
+			ParentCode:         v.ParentCode.String,
+			RuleOperator:       v.RuleOperator,
+			SeriesLevel:        v.SeriesLevel.Int64,
+			AvailableStartDate: formatNullTime(v.AvailableStartDate),
+			AvailableEndDate:   formatNullTime(v.AvailableEndDate),
+			ExpiredDate:        formatNullTime(v.ExpiredDate),
+			CreatedDate:        v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+			UpdatedDate:        v.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
+			DeletedDate:        v.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
 		})
 	}
 
@@ -130,18 +138,23 @@ func (h *Contract) GetUnownedBadgeUserListAct(w http.ResponseWriter, r *http.Req
 		}
 
 		res = append(res, response.BadgeRes{
-			BadgeCode:     v.BadgeCode,
-			BadgeCategory: v.BadgeCategory,
-			Name:          v.Name,
-			ImageURL:      v.ImageURL,
-			BadgeRules:    badgeRuleResSlice,
-			Description:   v.Description.String,
-			VPPoint:       v.VPPoint,
-			Status:        v.Status,
-			ParentCode:    v.ParentCode.String,
-			CreatedDate:   v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
-			UpdatedDate:   v.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
-			DeletedDate:   v.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
+			BadgeCode:          v.BadgeCode,
+			BadgeCategory:      v.BadgeCategory,
+			Name:               v.Name,
+			ImageURL:           v.ImageURL,
+			BadgeRules:         badgeRuleResSlice,
+			Description:        v.Description.String,
+			VPPoint:            v.VPPoint,
+			Status:             v.Status,
+			ParentCode:         v.ParentCode.String,
+			RuleOperator:       v.RuleOperator,
+			SeriesLevel:        v.SeriesLevel.Int64,
+			AvailableStartDate: formatNullTime(v.AvailableStartDate),
+			AvailableEndDate:   formatNullTime(v.AvailableEndDate),
+			ExpiredDate:        formatNullTime(v.ExpiredDate),
+			CreatedDate:        v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+			UpdatedDate:        v.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
+			DeletedDate:        v.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
 		})
 	}
 
@@ -183,17 +196,22 @@ func (h *Contract) GetBadgeDetailAct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.SendSuccess(w, response.BadgeRes{
-		BadgeCode:     badges.BadgeCode,
-		BadgeCategory: badges.BadgeCategory,
-		Name:          badges.Name,
-		ImageURL:      badges.ImageURL,
-		BadgeRules:    badgeRuleResSlice,
-		Description:   badges.Description.String,
-		VPPoint:       badges.VPPoint,
-		Status:        badges.Status,
-		CreatedDate:   badges.CreatedDate.Format(utils.DATE_TIME_FORMAT),
-		UpdatedDate:   badges.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
-		DeletedDate:   badges.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
+		BadgeCode:          badges.BadgeCode,
+		BadgeCategory:      badges.BadgeCategory,
+		Name:               badges.Name,
+		ImageURL:           badges.ImageURL,
+		BadgeRules:         badgeRuleResSlice,
+		Description:        badges.Description.String,
+		VPPoint:            badges.VPPoint,
+		Status:             badges.Status,
+		RuleOperator:       badges.RuleOperator,
+		SeriesLevel:        badges.SeriesLevel.Int64,
+		AvailableStartDate: formatNullTime(badges.AvailableStartDate),
+		AvailableEndDate:   formatNullTime(badges.AvailableEndDate),
+		ExpiredDate:        formatNullTime(badges.ExpiredDate),
+		CreatedDate:        badges.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+		UpdatedDate:        badges.UpdatedDate.Time.Format(utils.DATE_TIME_FORMAT),
+		DeletedDate:        badges.DeletedDate.Time.Format(utils.DATE_TIME_FORMAT),
 	}, nil)
 }
 
@@ -219,6 +237,28 @@ func (h *Contract) AddBadgeAct(w http.ResponseWriter, r *http.Request) {
 		h.SendBadRequest(w, "wrong status value for badge(active|inactive")
 		return
 	}
+
+	ruleOperator := req.RuleOperator
+	if ruleOperator == "" {
+		ruleOperator = utils.BadgeRuleOperatorAll
+	}
+
+	rules := make([]request.BadgeRuleReq, 0, len(req.BadgeRule))
+	for _, rule := range req.BadgeRule {
+		rule.Value, err = parseBadgeRuleValue(rule.KeyCondition, rule.Value)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	availableStartDate, availableEndDate, expiredDate, err := parseBadgeSchedule(req.AvailableStartDate, req.AvailableEndDate, req.ExpiredDate)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
 	// Start a transaction
 	tx, err := h.DB.Begin(ctx)
 	if err != nil {
@@ -233,7 +273,13 @@ func (h *Contract) AddBadgeAct(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	// Add Badge
-	badgeID, err = m.AddBadge(tx, ctx, badgeCode, req.BadgeCategory, req.Name, req.ImageURL, req.VPPoint, req.Status, req.Description, "")
+	badgeID, err = m.AddBadge(tx, ctx, badgeCode, req.BadgeCategory, req.Name, req.ImageURL, req.VPPoint, req.Status, req.Description, "", ruleOperator)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.UpdateBadgeSchedule(tx, ctx, badgeCode, availableStartDate, availableEndDate, expiredDate)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -242,126 +288,28 @@ func (h *Contract) AddBadgeAct(w http.ResponseWriter, r *http.Request) {
 	isGift = req.BadgeCategory == utils.BadgeCategoryGift.String()
 	// Add Badge Rules
 	if !isGift {
-		for _, rule := range req.BadgeRule {
+		for _, rule := range rules {
 			badgeRuleCode := utils.GeneratePrefixCode(utils.BadgeRulePrefix)
-			if rule.KeyCondition == utils.SpesificBoardGameCategory {
-				var spesificBoardGameCategory request.SpesificBoardGameCategory
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &spesificBoardGameCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, spesificBoardGameCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-			} else if rule.KeyCondition == utils.TimeLimit {
-				var timeLimitCategory request.TimeLimitCategory
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &timeLimitCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, timeLimitCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-			} else if rule.KeyCondition == utils.TotalSpend {
-				var totalSpendCategory int
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalSpendCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, totalSpendCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-			} else if rule.KeyCondition == utils.Tournament {
-				var tournamentCategory request.TournamentCategory
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &tournamentCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, tournamentCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-			} else if rule.KeyCondition == utils.TournamentWon {
-				var totalTournamentWon int
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalTournamentWon)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, totalTournamentWon)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-			} else if rule.KeyCondition == utils.PlayingGames {
-				var totalPlayingDifferentGames int
-				valueJSON, err := json.Marshal(rule.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalPlayingDifferentGames)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, totalPlayingDifferentGames)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
+			err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, rule.KeyCondition, rule.ValueType, rule.Value)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
 			}
 		}
 
-		// check if status active will send publish check badge available
-		if req.Status == "active" && !isGift {
+		// check if status active will send publish check badge available, scheduled
+		// badges are published by the scheduler once their window opens
+		if req.Status == "active" && !isGift && isBadgeWindowOpen(availableStartDate, availableEndDate, time.Now().UTC()) {
 			// Publisher badge
-			queueData := rabbit.QueueDataPayload(
+			err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
 				rabbit.QueueBadges,
 				rabbit.QueueBadgeReq(
 					badgeCode,
 				),
-			)
-			queueHost := m.Config.GetString("queue.rabbitmq.host")
-			err = rabbit.PublishQueue(ctx, queueHost, queueData)
+			))
 			if err != nil {
-				log.Printf("Error : %s", err)
+				h.SendBadRequest(w, err.Error())
+				return
 			}
 		}
 	}
@@ -392,6 +340,27 @@ func (h *Contract) UpdateBadgeAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ruleOperator := req.RuleOperator
+	if ruleOperator == "" {
+		ruleOperator = utils.BadgeRuleOperatorAll
+	}
+
+	rules := make([]request.UpdateBadgeRuleReq, 0, len(req.BadgeRule))
+	for _, rule := range req.BadgeRule {
+		rule.Value, err = parseBadgeRuleValue(rule.KeyCondition, rule.Value)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	availableStartDate, availableEndDate, expiredDate, err := parseBadgeSchedule(req.AvailableStartDate, req.AvailableEndDate, req.ExpiredDate)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
 	// Start a transaction
 	tx, err := h.DB.Begin(ctx)
 	if err != nil {
@@ -414,7 +383,13 @@ func (h *Contract) UpdateBadgeAct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update Badge
-	err = m.UpdateBadgeByCode(tx, ctx, req.BadgeCategory, req.Name, req.Description, req.ImageURL, req.Status, req.VPPoint, badgeCode)
+	err = m.UpdateBadgeByCode(tx, ctx, req.BadgeCategory, req.Name, req.Description, req.ImageURL, req.Status, req.VPPoint, ruleOperator, badgeCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	err = m.UpdateBadgeSchedule(tx, ctx, badgeCode, availableStartDate, availableEndDate, expiredDate)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -429,119 +404,29 @@ func (h *Contract) UpdateBadgeAct(w http.ResponseWriter, r *http.Request) {
 
 	isGift = req.BadgeCategory == utils.BadgeCategoryGift.String()
 	if !isGift {
-		for _, v := range req.BadgeRule {
+		for _, v := range rules {
 			// Add Badge Rule
 			badgeRuleCode := utils.GeneratePrefixCode(utils.BadgeRulePrefix)
-			switch v.KeyCondition {
-			case utils.SpesificBoardGameCategory:
-				var spesificBoardGameCategory request.SpesificBoardGameCategory
-				valueJSON, err := json.Marshal(v.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &spesificBoardGameCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, spesificBoardGameCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-
-			case utils.TimeLimit:
-				var timeLimitCategory request.TimeLimitCategory
-				valueJSON, err := json.Marshal(v.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &timeLimitCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, timeLimitCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-
-			case utils.TotalSpend:
-				var totalSpendCategory int
-				valueJSON, err := json.Marshal(v.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalSpendCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, totalSpendCategory)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-
-			case utils.TournamentWon:
-				var totalTournamentWon int
-				valueJSON, err := json.Marshal(v.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalTournamentWon)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, totalTournamentWon)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-
-			case utils.PlayingGames:
-				var totalPlayingDifferentGames int
-				valueJSON, err := json.Marshal(v.Value)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = json.Unmarshal(valueJSON, &totalPlayingDifferentGames)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-				err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, totalPlayingDifferentGames)
-				if err != nil {
-					h.SendBadRequest(w, err.Error())
-					return
-				}
-
-			default:
-				h.SendBadRequest(w, "Invalid KeyCondition")
+			err = m.AddBadgeRule(tx, ctx, badgeRuleCode, badgeID, v.KeyCondition, v.ValueType, v.Value)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
 				return
 			}
 		}
 
-		// check if status active will send publish check badge available
-		if req.Status == "active" {
+		// check if status active will send publish check badge available, scheduled
+		// badges are published by the scheduler once their window opens
+		if req.Status == "active" && isBadgeWindowOpen(availableStartDate, availableEndDate, time.Now().UTC()) {
 			// Publisher badge
-			queueData := rabbit.QueueDataPayload(
+			err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
 				rabbit.QueueBadges,
 				rabbit.QueueBadgeReq(
 					badgeCode,
 				),
-			)
-			queueHost := m.Config.GetString("queue.rabbitmq.host")
-			err = rabbit.PublishQueue(ctx, queueHost, queueData)
+			))
 			if err != nil {
-				log.Printf("Error : %s", err)
+				h.SendBadRequest(w, err.Error())
+				return
 			}
 		}
 	}
@@ -550,6 +435,103 @@ func (h *Contract) UpdateBadgeAct(w http.ResponseWriter, r *http.Request) {
 	h.SendSuccess(w, nil, nil)
 }
 
+// PreviewBadgeAct returns how many members would earn a draft badge without
+// saving it, optionally compared against the rules of an existing badge.
+func (h *Contract) PreviewBadgeAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		req = request.BadgePreviewReq{}
+		ctx = context.TODO()
+		m   = model.Contract{App: h.App}
+		src = m.NewBadgeSource(h.DB)
+		res = response.BadgePreviewRes{SampleUsers: make([]response.BadgePreviewUserRes, 0)}
+	)
+
+	// Binding and Validate
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	rules := make([]badge.Rule, 0, len(req.BadgeRule))
+	for _, rule := range req.BadgeRule {
+		value, err := parseBadgeRuleValue(rule.KeyCondition, rule.Value)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		rules = append(rules, badge.Rule{KeyCondition: rule.KeyCondition, Value: value})
+	}
+
+	userIds, err := badge.EvaluateUsers(ctx, src, req.RuleOperator, rules)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	res.QualifiedCount = len(userIds)
+
+	sampleIds := userIds
+	if len(sampleIds) > utils.BadgePreviewSampleSize {
+		sampleIds = sampleIds[:utils.BadgePreviewSampleSize]
+	}
+	if len(sampleIds) > 0 {
+		users, err := m.GetUsersByIds(h.DB, ctx, sampleIds)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		for _, v := range users {
+			res.SampleUsers = append(res.SampleUsers, response.BadgePreviewUserRes{
+				UserCode: v.UserCode,
+				UserName: v.UserName.String,
+				FullName: v.FullName,
+				ImageURL: v.ImageURL.String,
+			})
+		}
+	}
+
+	if req.CompareBadgeCode != "" {
+		compareBadge, err := m.GetBadgeDetailByCode(h.DB, ctx, req.CompareBadgeCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		compareRules, err := m.GetBadgeRuleByBadgeCode(h.DB, ctx, req.CompareBadgeCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		compareIds, err := badge.EvaluateUsers(ctx, src, compareBadge.RuleOperator, model.BadgeRules(compareRules))
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		draft := make(map[int64]bool, len(userIds))
+		for _, id := range userIds {
+			draft[id] = true
+		}
+
+		compare := response.BadgePreviewCompareRes{
+			BadgeCode:      req.CompareBadgeCode,
+			QualifiedCount: len(compareIds),
+		}
+		for _, id := range compareIds {
+			if draft[id] {
+				compare.BothCount++
+			} else {
+				compare.OnlyExistingCount++
+			}
+		}
+		compare.OnlyDraftCount = len(userIds) - compare.BothCount
+		res.Compare = &compare
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
 // DeleteBadgeAct ...
 func (h *Contract) DeleteBadgeAct(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -636,3 +618,90 @@ func (h *Contract) GiftBadgeToUserAct(w http.ResponseWriter, r *http.Request) {
 
 	h.SendSuccess(w, nil, nil)
 }
+
+// parseBadgeRuleValue validates a rule value with the evaluator registered
+// for its key condition.
+func parseBadgeRuleValue(keyCondition string, value interface{}) (interface{}, error) {
+	e, err := badge.Lookup(keyCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Parse(value)
+}
+
+// parseBadgeSchedule parses the optional earning window and expiry of a badge,
+// given in WIB with the utils.DATE_TIME_FORMAT layout.
+func parseBadgeSchedule(availableStartDate, availableEndDate, expiredDate string) (start, end, expired sql.NullTime, err error) {
+	parse := func(value string) (sql.NullTime, error) {
+		if value == "" {
+			return sql.NullTime{}, nil
+		}
+
+		date, err := utils.ToUTCfromGMT7(value)
+		if err != nil {
+			return sql.NullTime{}, errors.New(utils.ErrInvalidBadgeSchedule)
+		}
+
+		return sql.NullTime{Time: date, Valid: true}, nil
+	}
+
+	if start, err = parse(availableStartDate); err != nil {
+		return
+	}
+	if end, err = parse(availableEndDate); err != nil {
+		return
+	}
+	if expired, err = parse(expiredDate); err != nil {
+		return
+	}
+
+	if start.Valid && end.Valid && !end.Time.After(start.Time) {
+		err = errors.New(utils.ErrInvalidBadgeSchedule)
+		return
+	}
+	if expired.Valid && end.Valid && expired.Time.Before(end.Time) {
+		err = errors.New(utils.ErrInvalidBadgeSchedule)
+		return
+	}
+	if expired.Valid && start.Valid && !expired.Time.After(start.Time) {
+		err = errors.New(utils.ErrInvalidBadgeSchedule)
+		return
+	}
+
+	return
+}
+
+// isBadgeWindowOpen reports whether a badge can be earned at the given time.
+func isBadgeWindowOpen(start, end sql.NullTime, now time.Time) bool {
+	return model.BadgeEnt{AvailableStartDate: start, AvailableEndDate: end}.IsAvailable(now)
+}
+
+// formatNullTime formats an optional date in WIB, or returns an empty string.
+func formatNullTime(date sql.NullTime) string {
+	if !date.Valid {
+		return ""
+	}
+
+	return date.Time.In(utils.GetTimeLocationWIB()).Format(utils.DATE_TIME_FORMAT)
+}
+
+// publishUserBadges asks the badge worker to check the given rule types for a
+// member once the transaction is committed.
+func (h *Contract) publishUserBadges(tx pgx.Tx, ctx context.Context, userId int64, badgeTypes ...string) error {
+	m := model.Contract{App: h.App}
+	for _, badgeType := range badgeTypes {
+		err := m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
+			rabbit.QueueUserBadge,
+			rabbit.QueueUserBadgeReq(
+				badgeType,
+				userId,
+			),
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
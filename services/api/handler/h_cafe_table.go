@@ -0,0 +1,403 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
+)
+
+func (h *Contract) AssignRoomTableAct(w http.ResponseWriter, r *http.Request) {
+	h.assignEventTable(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) AssignTournamentTableAct(w http.ResponseWriter, r *http.Request) {
+	h.assignEventTable(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) GetCafeTablesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+		res  = make([]response.CafeTableRes, 0)
+	)
+
+	cafeId, err := m.GetCafeIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetCafeTables(h.DB, ctx, cafeId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		res = append(res, cafeTableRes(v))
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+func (h *Contract) AddCafeTableAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		req  = request.CafeTableReq{}
+		code = chi.URLParam(r, "code")
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if err = validateCafeTable(req); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	cafeId, err := m.GetCafeIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data := model.CafeTableEnt{
+		TableCode: utils.GeneratePrefixCode(utils.CafeTablePrefix),
+		Name:      req.Name,
+		TableType: req.TableType,
+		Seats:     req.Seats,
+		Status:    req.Status,
+	}
+	if err = m.AddCafeTable(h.DB, ctx, cafeId, data); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, cafeTableRes(data), nil)
+}
+
+// UpdateCafeTableAct edits a table. Rooms and tournaments already assigned
+// keep it.
+func (h *Contract) UpdateCafeTableAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		req       = request.CafeTableReq{}
+		code      = chi.URLParam(r, "code")
+		tableCode = chi.URLParam(r, "table_code")
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if err = validateCafeTable(req); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data, err := h.getCafeTable(ctx, m, code, tableCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data.Name = req.Name
+	data.TableType = req.TableType
+	data.Seats = req.Seats
+	data.Status = req.Status
+	if err = m.UpdateCafeTable(h.DB, ctx, data); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, cafeTableRes(data), nil)
+}
+
+func (h *Contract) DeleteCafeTableAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		code      = chi.URLParam(r, "code")
+		tableCode = chi.URLParam(r, "table_code")
+	)
+
+	data, err := h.getCafeTable(ctx, m, code, tableCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.DeleteCafeTable(h.DB, ctx, data.Id, recurrence.Today()); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// GetCafeFloorPlanAct returns the tables of a cafe with the rooms and
+// tournaments played at them on a day, today in WIB by default.
+func (h *Contract) GetCafeFloorPlanAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+		date = recurrence.Today()
+	)
+
+	if v := r.URL.Query().Get("date"); v != "" {
+		date, err = time.Parse(time.DateOnly, v)
+		if err != nil {
+			h.SendBadRequest(w, utils.ErrInvalidFloorPlanDateFormat)
+			return
+		}
+	}
+
+	cafe, err := m.GetCafeByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	cafeId, err := m.GetCafeIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	tables, err := m.GetCafeTables(h.DB, ctx, cafeId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	bookings, err := m.GetCafeTableBookings(h.DB, ctx, cafeId, date)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	tableBookings := make(map[int64][]response.CafeTableBookingRes)
+	for _, v := range bookings {
+		tableBookings[v.TableId] = append(tableBookings[v.TableId], response.CafeTableBookingRes{
+			EventType:    v.EventType,
+			EventCode:    v.EventCode,
+			EventName:    v.EventName,
+			StartDate:    v.Start.Format(utils.DATE_TIME_FORMAT),
+			EndDate:      v.End.Format(utils.DATE_TIME_FORMAT),
+			Seats:        v.Seats,
+			Participants: v.Participants,
+			Status:       v.Status,
+		})
+	}
+
+	res := response.FloorPlanRes{
+		CafeCode: cafe.CafeCode,
+		CafeName: cafe.Name,
+		Date:     date.Format(utils.DATE_FORMAT),
+		Tables:   make([]response.FloorPlanTableRes, 0),
+	}
+	for _, v := range tables {
+		table := response.FloorPlanTableRes{
+			TableCode: v.TableCode,
+			Name:      v.Name,
+			TableType: v.TableType,
+			Seats:     v.Seats,
+			Status:    v.Status,
+			Bookings:  make([]response.CafeTableBookingRes, 0),
+		}
+		if list, ok := tableBookings[v.Id]; ok {
+			table.Bookings = list
+			res.TablesInUse++
+			for _, booking := range list {
+				res.BookedSeats += booking.Participants
+			}
+		}
+
+		res.TotalTables++
+		res.TotalSeats += v.Seats
+		res.Tables = append(res.Tables, table)
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// assignEventTable assigns a room or tournament to a table of the cafe it is
+// played at, or unassigns it with an empty table code.
+func (h *Contract) assignEventTable(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		req  = request.EventTableReq{}
+		code = chi.URLParam(r, "code")
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	eventId, err := h.getEventId(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	tableId := sql.NullInt64{}
+	if req.TableCode != "" {
+		table, err := m.GetCafeTableByCode(h.DB, ctx, req.TableCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		// Hold the lock of the table until the event is assigned to it
+		tx, err := h.DB.Begin(ctx)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		defer tx.Rollback(ctx)
+
+		if err = m.LockCafeTableTrx(tx, ctx, table.Id); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		event, err := m.GetCafeTableEvent(h.DB, ctx, eventType, eventId)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		if err = m.CheckCafeTableBooking(h.DB, ctx, table, event); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		tableId = sql.NullInt64{Int64: table.Id, Valid: true}
+	}
+
+	if err = m.SetEventTable(h.DB, ctx, eventType, eventId, tableId); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// checkEventTable checks that a room or tournament assigned to a table can
+// still be played there with its new seats, city and schedule. The table
+// stays locked by tx, which must last until the event is saved.
+func (h *Contract) checkEventTable(ctx context.Context, m model.Contract, tx pgx.Tx, event model.CafeTableEventEnt) error {
+	current, err := m.GetCafeTableEvent(h.DB, ctx, event.EventType, event.EventId)
+	if err != nil {
+		return err
+	}
+	if !current.TableId.Valid {
+		return nil
+	}
+
+	if err = m.LockCafeTableTrx(tx, ctx, current.TableId.Int64); err != nil {
+		return err
+	}
+
+	table, err := m.GetCafeTableById(h.DB, ctx, current.TableId.Int64)
+	if err != nil {
+		return err
+	}
+
+	return m.CheckCafeTableBooking(h.DB, ctx, table, event)
+}
+
+// getCafeTable returns a table of a cafe.
+func (h *Contract) getCafeTable(ctx context.Context, m model.Contract, cafeCode, tableCode string) (model.CafeTableEnt, error) {
+	data, err := m.GetCafeTableByCode(h.DB, ctx, tableCode)
+	if err != nil {
+		return data, err
+	}
+	if data.CafeCode != cafeCode {
+		return data, errors.New(utils.ErrGettingCafeTableByCode)
+	}
+
+	return data, nil
+}
+
+func (h *Contract) getEventId(ctx context.Context, m model.Contract, eventType, code string) (int64, error) {
+	if eventType == utils.WaitlistTournament {
+		return m.GetTournamentIdByCode(h.DB, ctx, code)
+	}
+
+	return m.GetRoomIdByCode(h.DB, ctx, code)
+}
+
+func validateCafeTable(req request.CafeTableReq) error {
+	if !utils.Contains(utils.StatusCafeTable, req.Status) {
+		return errors.New("wrong status value for cafe table(active|inactive)")
+	}
+
+	if !utils.Contains(utils.CafeTableType, req.TableType) {
+		return fmt.Errorf("wrong type value for cafe table(%v)", utils.CafeTableType)
+	}
+
+	return nil
+}
+
+// eventPeriod combines the dates and times of a room or tournament request
+// into its WIB wall clock, in UTC like every event date. An event without an
+// end date ends on its start date and one without times lasts the whole day.
+func eventPeriod(startDate, endDate, startTime, endTime string) (time.Time, time.Time, error) {
+	if endDate == "" {
+		endDate = startDate
+	}
+	if startTime == "" {
+		startTime = "00:00:00"
+	}
+	if endTime == "" {
+		endTime = "23:59:59"
+	}
+
+	start, err := time.Parse(time.DateTime, startDate+" "+startTime)
+	if err != nil {
+		return start, start, errors.New(utils.ErrInvalidStartDateFormat)
+	}
+
+	end, err := time.Parse(time.DateTime, endDate+" "+endTime)
+	if err != nil {
+		return start, end, errors.New(utils.ErrInvalidEndDateFormat)
+	}
+
+	return start, end, nil
+}
+
+func cafeTableRes(v model.CafeTableEnt) response.CafeTableRes {
+	return response.CafeTableRes{
+		TableCode: v.TableCode,
+		Name:      v.Name,
+		TableType: v.TableType,
+		Seats:     v.Seats,
+		Status:    v.Status,
+	}
+}
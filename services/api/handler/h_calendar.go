@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"dots-api/bootstrap"
+	"dots-api/lib/ical"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/response"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetCalendarFeedAct returns the link of the calendar feed of the member,
+// to subscribe to from Google Calendar or Apple Calendar.
+func (h *Contract) GetCalendarFeedAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	token, err := m.GetCalendarToken(h.DB, ctx, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, h.calendarFeedRes(token), nil)
+}
+
+// ResetCalendarFeedAct gives the member a new calendar feed link, the old one
+// stops working.
+func (h *Contract) ResetCalendarFeedAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	token, err := m.ResetCalendarToken(h.DB, ctx, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, h.calendarFeedRes(token), nil)
+}
+
+// GetUserCalendarAct serves the calendar feed of a member. Calendar apps
+// cannot send a JWT, so the feed is authenticated by the token in its link.
+func (h *Contract) GetUserCalendarAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		token = chi.URLParam(r, "token")
+	)
+
+	userId, err := m.GetUserIdByCalendarToken(h.DB, ctx, token)
+	if err != nil {
+		if err.Error() == utils.ErrInvalidCalendarToken {
+			h.SendNotfound(w, err.Error())
+			return
+		}
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetUserCalendarEvents(h.DB, ctx, userId, recurrence.Today().AddDate(0, 0, -utils.CalendarPastDays))
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	writeCalendar(w, calendarOf(utils.CalendarName, list))
+}
+
+// GetCafeCalendarAct serves the public calendar feed of the upcoming events
+// of a cafe.
+func (h *Contract) GetCafeCalendarAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	cafe, err := m.GetCafeByCode(h.DB, ctx, code)
+	if err == nil && cafe.Status != "active" {
+		err = errors.New(utils.ErrGettingCafeByCode)
+	}
+	if err != nil {
+		h.SendNotfound(w, err.Error())
+		return
+	}
+
+	list, err := m.GetCafeCalendarEvents(h.DB, ctx, cafe.CafeCode, recurrence.Today())
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	writeCalendar(w, calendarOf(fmt.Sprintf("%s - %s", utils.CalendarName, cafe.Name), list))
+}
+
+func (h *Contract) calendarFeedRes(token string) response.CalendarFeedRes {
+	feedUrl := fmt.Sprintf("%s/users/%s.ics", h.Config.GetString("calendar.feed_url"), token)
+
+	webcalUrl := feedUrl
+	for _, scheme := range []string{"https://", "http://"} {
+		webcalUrl = strings.Replace(webcalUrl, scheme, "webcal://", 1)
+	}
+
+	return response.CalendarFeedRes{
+		FeedUrl:   feedUrl,
+		WebcalUrl: webcalUrl,
+	}
+}
+
+// calendarOf turns events into a calendar. Every change of an event moves its
+// updated date, which is used as the sequence so calendar apps pick it up.
+func calendarOf(name string, list []model.CalendarEventEnt) ical.Calendar {
+	cal := ical.Calendar{Name: name, Events: make([]ical.Event, 0, len(list))}
+
+	for _, v := range list {
+		status := ical.StatusConfirmed
+		if v.Status == utils.RoomStatus["CANCELLED"] {
+			status = ical.StatusCancelled
+		}
+
+		description := v.GameName
+		if v.Description != "" {
+			description += "\n\n" + v.Description
+		}
+
+		location := v.CafeName
+		if v.CafeAddress != "" {
+			location += ", " + v.CafeAddress
+		}
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID:          fmt.Sprintf("%s-%s@dots", v.EventType, v.EventCode),
+			Summary:      v.Name,
+			Description:  description,
+			Location:     location,
+			Start:        eventTime(v.StartDate, v.StartTime),
+			End:          eventTime(v.EndDate, v.EndTime),
+			Status:       status,
+			Sequence:     v.UpdatedDate.Unix(),
+			LastModified: v.UpdatedDate,
+		})
+	}
+
+	return cal
+}
+
+func writeCalendar(w http.ResponseWriter, cal ical.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(cal.Bytes())
+}
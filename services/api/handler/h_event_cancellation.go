@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"dots-api/bootstrap"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Contract) CancelRoomEventAct(w http.ResponseWriter, r *http.Request) {
+	h.cancelEvent(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) CancelTournamentEventAct(w http.ResponseWriter, r *http.Request) {
+	h.cancelEvent(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) GetRoomCancellationAct(w http.ResponseWriter, r *http.Request) {
+	h.getEventCancellation(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) GetTournamentCancellationAct(w http.ResponseWriter, r *http.Request) {
+	h.getEventCancellation(w, r, utils.WaitlistTournament)
+}
+
+// cancelEvent cancels a room or tournament from the CMS. Its members are
+// refunded and notified by the settle-event-cancellations job.
+func (h *Contract) cancelEvent(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		req       = request.CancelEventReq{}
+		code      = chi.URLParam(r, "code")
+		adminCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if event.Status == utils.RoomStatus["CLOSED"] || event.Status == utils.RoomStatus["CANCELLED"] {
+		h.SendBadRequest(w, utils.ErrEventNotCancellable)
+		return
+	}
+
+	cancellation, participants, err := m.CancelEvent(h.DB, ctx, eventType, event.Id, event.Code, req.Reason, adminCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, eventCancellationRes(event.Code, cancellation, participants), nil)
+}
+
+// getEventCancellation returns the outcome report of a cancelled event.
+func (h *Contract) getEventCancellation(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	cancellation, err := m.GetEventCancellation(h.DB, ctx, eventType, event.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	participants, err := m.GetEventCancellationParticipants(h.DB, ctx, cancellation.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, eventCancellationRes(event.Code, cancellation, participants), nil)
+}
+
+func eventCancellationRes(eventCode string, cancellation model.EventCancellationEnt, participants []model.EventCancellationParticipantEnt) response.EventCancellationRes {
+	res := response.EventCancellationRes{
+		EventType:    cancellation.EventType,
+		EventCode:    eventCode,
+		Reason:       cancellation.Reason,
+		CancelledBy:  cancellation.CancelledBy,
+		CreatedDate:  cancellation.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+		Total:        len(participants),
+		Participants: make([]response.EventCancellationParticipantRes, 0),
+	}
+
+	for _, v := range participants {
+		switch v.RefundStatus {
+		case utils.RefundPending:
+			res.Pending++
+		case utils.RefundRefunded:
+			res.Refunded++
+		case utils.RefundFailed, utils.RefundExpireFailed:
+			res.Failed++
+		}
+
+		res.Participants = append(res.Participants, response.EventCancellationParticipantRes{
+			UserCode:          v.UserCode,
+			UserName:          v.UserName,
+			ParticipantStatus: v.ParticipantStatus,
+			TransactionCode:   v.TransactionCode,
+			Amount:            v.Amount,
+			RefundStatus:      v.RefundStatus,
+			RefundId:          v.RefundId,
+			RefundError:       v.RefundError,
+			ReversedPoint:     v.ReversedPoint,
+			PushSent:          v.PushSent,
+			EmailSent:         v.EmailSent,
+		})
+	}
+
+	return res
+}
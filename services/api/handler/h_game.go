@@ -8,6 +8,7 @@ import (
 	"dots-api/services/api/response"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -462,3 +463,48 @@ func (h *Contract) GetGameQRCodeAct(w http.ResponseWriter, r *http.Request) {
 
 	h.SendSuccess(w, qr, nil)
 }
+
+// GetGameLeaderboardAct ranks the members by their room results for a game.
+func (h *Contract) GetGameLeaderboardAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		code  = chi.URLParam(r, "code")
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		param = request.LeaderboardParam{}
+		res   = make([]response.LeaderboardRes, 0)
+	)
+
+	if err = param.ParseLeaderboard(r.URL.Query()); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	gameId, err := m.GetGameIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetGameLeaderboard(h.DB, ctx, gameId, param.Limit)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		res = append(res, response.LeaderboardRes{
+			Rank:         v.Rank,
+			UserCode:     v.UserCode,
+			UserName:     v.UserName,
+			UserImageUrl: v.UserImageUrl.String,
+			Played:       v.Played,
+			Wins:         v.Wins,
+			Podiums:      v.Podiums,
+			BestScore:    v.BestScore.Int64,
+			AverageScore: math.Round(v.AverageScore*100) / 100,
+		})
+	}
+
+	h.SendSuccess(w, res, nil)
+}
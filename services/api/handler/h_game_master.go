@@ -0,0 +1,366 @@
+package handler
+
+import (
+	"context"
+	"dots-api/bootstrap"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
+)
+
+// GetGameMasterAvailabilityAct returns the weekly availability of a game
+// master and their upcoming days off.
+func (h *Contract) GetGameMasterAvailabilityAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+		from = recurrence.Today()
+	)
+
+	adminId, err := m.GetAdminIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	availabilities, err := m.GetGameMasterAvailabilities(h.DB, ctx, adminId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	daysOff, err := m.GetGameMasterDaysOff(h.DB, ctx, adminId, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, response.GameMasterAvailabilityDetailRes{
+		Availabilities: gameMasterAvailabilityRes(availabilities),
+		DaysOff:        gameMasterDayOffRes(daysOff),
+	}, nil)
+}
+
+// UpdateGameMasterAvailabilityAct replaces the weekly availability of a game
+// master. Rooms already assigned are not checked again.
+func (h *Contract) UpdateGameMasterAvailabilityAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		req  = request.GameMasterAvailabilityReq{}
+		code = chi.URLParam(r, "code")
+		list = make([]model.GameMasterAvailabilityEnt, 0)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	for _, v := range req.Availabilities {
+		startTime, err := time.Parse(time.TimeOnly, v.StartTime)
+		if err != nil {
+			h.SendBadRequest(w, utils.ErrInvalidAvailability)
+			return
+		}
+		endTime, err := time.Parse(time.TimeOnly, v.EndTime)
+		if err != nil || !startTime.Before(endTime) {
+			h.SendBadRequest(w, utils.ErrInvalidAvailability)
+			return
+		}
+		list = append(list, model.GameMasterAvailabilityEnt{Weekday: v.Weekday, StartTime: startTime, EndTime: endTime})
+	}
+
+	adminId, err := m.GetAdminIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	if err = m.ReplaceGameMasterAvailabilitiesTrx(tx, ctx, adminId, list); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// AddGameMasterDayOffAct marks a day a game master cannot host rooms.
+func (h *Contract) AddGameMasterDayOffAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		req  = request.GameMasterDayOffReq{}
+		code = chi.URLParam(r, "code")
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		h.SendBadRequest(w, utils.ErrInvalidDayOffDateFormat)
+		return
+	}
+
+	adminId, err := m.GetAdminIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.AddGameMasterDayOff(h.DB, ctx, adminId, date, req.Reason); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+func (h *Contract) DeleteGameMasterDayOffAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	date, err := time.Parse(time.DateOnly, chi.URLParam(r, "date"))
+	if err != nil {
+		h.SendBadRequest(w, utils.ErrInvalidDayOffDateFormat)
+		return
+	}
+
+	adminId, err := m.GetAdminIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.DeleteGameMasterDayOff(h.DB, ctx, adminId, date); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// GetGameMasterWorkloadAct returns the rooms hosted per game master and week,
+// for one game master with admin_code.
+func (h *Contract) GetGameMasterWorkloadAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		param = request.GameMasterScheduleParam{}
+		res   = make([]response.GameMasterWorkloadRes, 0)
+	)
+
+	if err = param.ParseGameMasterSchedule(r.URL.Query()); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetGameMasterWorkload(h.DB, ctx, param.StartDate, param.EndDate, param.AdminCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		res = append(res, response.GameMasterWorkloadRes{
+			GameMasterCode: v.AdminCode,
+			GameMasterName: v.AdminName,
+			WeekStart:      v.WeekStart.Format(utils.DATE_FORMAT),
+			Sessions:       v.Sessions,
+			Hours:          v.Hours,
+			Participants:   v.Participants,
+		})
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+func (h *Contract) GetGameMasterScheduleAct(w http.ResponseWriter, r *http.Request) {
+	h.getGameMasterSchedule(w, r, chi.URLParam(r, "code"))
+}
+
+// GetOwnGameMasterScheduleAct returns the schedule of the signed in admin.
+func (h *Contract) GetOwnGameMasterScheduleAct(w http.ResponseWriter, r *http.Request) {
+	h.getGameMasterSchedule(w, r, bootstrap.GetIdentifierCodeFromToken(context.TODO(), r))
+}
+
+// getGameMasterSchedule returns the rooms a game master hosts in a range of
+// days with their availability and days off.
+func (h *Contract) getGameMasterSchedule(w http.ResponseWriter, r *http.Request, adminCode string) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		param = request.GameMasterScheduleParam{}
+		res   = response.GameMasterScheduleRes{Sessions: make([]response.GameMasterSessionRes, 0)}
+	)
+
+	if err = param.ParseGameMasterSchedule(r.URL.Query()); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	admin, err := m.GetAdminByCode(h.DB, ctx, adminCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	adminId, err := m.GetAdminIdByCode(h.DB, ctx, adminCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	sessions, err := m.GetGameMasterSessions(h.DB, ctx, adminId, param.StartDate, param.EndDate)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	availabilities, err := m.GetGameMasterAvailabilities(h.DB, ctx, adminId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	daysOff, err := m.GetGameMasterDaysOff(h.DB, ctx, adminId, param.StartDate, param.EndDate)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range sessions {
+		var startDate, endDate string
+		if v.StartDate.Valid {
+			startDate = v.StartDate.Time.Format(utils.DATE_FORMAT)
+		}
+		if v.EndDate.Valid {
+			endDate = v.EndDate.Time.Format(utils.DATE_FORMAT)
+		}
+
+		res.Sessions = append(res.Sessions, response.GameMasterSessionRes{
+			RoomCode:     v.RoomCode,
+			Name:         v.Name,
+			GameName:     v.GameName,
+			StartDate:    startDate,
+			EndDate:      endDate,
+			StartTime:    v.StartTime.Format(utils.TIME_FORMAT),
+			EndTime:      v.EndTime.Format(utils.TIME_FORMAT),
+			Status:       v.Status,
+			Participants: v.Participants,
+		})
+	}
+
+	res.GameMasterCode = admin.AdminCode
+	res.GameMasterName = admin.Name
+	res.StartDate = param.StartDate.Format(utils.DATE_FORMAT)
+	res.EndDate = param.EndDate.Format(utils.DATE_FORMAT)
+	res.Availabilities = gameMasterAvailabilityRes(availabilities)
+	res.DaysOff = gameMasterDayOffRes(daysOff)
+
+	h.SendSuccess(w, res, nil)
+}
+
+// lockGameMaster holds the lock of a game master until the returned
+// transaction ends, so no other room of the game master is checked and saved
+// in between. The caller rolls it back once the room is saved.
+func (h *Contract) lockGameMaster(ctx context.Context, m model.Contract, gameMasterId int64) (pgx.Tx, error) {
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.LockGameMasterTrx(tx, ctx, gameMasterId); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// checkGameMasterRoom checks that a game master can host a room on the dates
+// and times of req. Rooms with excludeRoomCode, e.g. the room being updated,
+// are not conflicts.
+func (h *Contract) checkGameMasterRoom(ctx context.Context, m model.Contract, gameMasterId int64, req request.RoomReq, excludeRoomCode string) error {
+	var (
+		slot = model.GameMasterSlotEnt{GameMasterId: gameMasterId, ExcludeRoomCode: excludeRoomCode}
+		err  error
+	)
+
+	if slot.Start, slot.End, err = eventPeriod(req.StartDate, req.EndDate, req.StartTime, req.EndTime); err != nil {
+		return err
+	}
+
+	return m.CheckGameMasterSchedule(h.DB, ctx, slot)
+}
+
+// checkGameMasterSeries checks that the game master of a series can host its
+// rooms from today up to upTo. The rooms of the series itself are not
+// conflicts.
+func (h *Contract) checkGameMasterSeries(ctx context.Context, m model.Contract, series model.RoomSeriesEnt, today, upTo time.Time) error {
+	for _, date := range series.Rule().Dates(upTo) {
+		if date.Before(today) {
+			continue
+		}
+
+		if err := m.CheckGameMasterSchedule(h.DB, ctx, series.Slot(date)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func gameMasterAvailabilityRes(list []model.GameMasterAvailabilityEnt) []response.GameMasterAvailabilityRes {
+	res := make([]response.GameMasterAvailabilityRes, 0)
+	for _, v := range list {
+		res = append(res, response.GameMasterAvailabilityRes{
+			Weekday:   v.Weekday,
+			StartTime: v.StartTime.Format(utils.TIME_FORMAT),
+			EndTime:   v.EndTime.Format(utils.TIME_FORMAT),
+		})
+	}
+
+	return res
+}
+
+func gameMasterDayOffRes(list []model.GameMasterDayOffEnt) []response.GameMasterDayOffRes {
+	res := make([]response.GameMasterDayOffRes, 0)
+	for _, v := range list {
+		res = append(res, response.GameMasterDayOffRes{Date: v.OffDate.Format(utils.DATE_FORMAT), Reason: v.Reason})
+	}
+
+	return res
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"net/http"
+)
+
+// GetJobRunListAct lists the runs of the scheduled jobs for the CMS.
+func (h *Contract) GetJobRunListAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		res   = make([]response.JobRunRes, 0)
+		param = request.JobRunParam{}
+	)
+
+	err = param.ParseJobRun(r.URL.Query())
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data, param, err := m.GetJobRunList(h.DB, ctx, param)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// Populate response
+	for _, v := range data {
+		// A run still in progress has no finished date
+		finishedDate := ""
+		if v.FinishedDate.Valid {
+			finishedDate = v.FinishedDate.Time.Format(utils.DATE_TIME_FORMAT)
+		}
+
+		res = append(res, response.JobRunRes{
+			JobName:       v.JobName,
+			Status:        v.Status,
+			ItemCount:     v.ItemCount,
+			ErrorMessage:  v.ErrorMessage.String,
+			ScheduledDate: v.ScheduledDate.Format(utils.DATE_TIME_FORMAT),
+			StartedDate:   v.StartedDate.Format(utils.DATE_TIME_FORMAT),
+			FinishedDate:  finishedDate,
+		})
+	}
+
+	h.SendSuccess(w, res, param)
+}
@@ -5,13 +5,11 @@ import (
 	"dots-api/bootstrap"
 	POS "dots-api/lib/point_of_sale"
 	"dots-api/lib/point_of_sale/sub_modules"
-	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
 	"dots-api/services/api/request"
 	"dots-api/services/api/response"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
@@ -156,20 +154,6 @@ func (h *Contract) Redeem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Publisher badge
-	queueData := rabbit.QueueDataPayload(
-		rabbit.QueueUserBadge,
-		rabbit.QueueUserBadgeReq(
-			utils.TotalSpend,
-			userId,
-		),
-	)
-	queueHost := m.Config.GetString("queue.rabbitmq.host")
-	err = rabbit.PublishQueue(ctx, queueHost, queueData)
-	if err != nil {
-		log.Printf("Error : %s", err)
-	}
-
 	// Populate response
 	h.SendSuccess(w, response.UserReedemHistoryRes{
 		UserCode:    userIdentifier,
@@ -282,20 +266,6 @@ func (h *Contract) Claim(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Publisher badge
-	queueData := rabbit.QueueDataPayload(
-		rabbit.QueueUserBadge,
-		rabbit.QueueUserBadgeReq(
-			utils.TotalSpend,
-			userId,
-		),
-	)
-	queueHost := m.Config.GetString("queue.rabbitmq.host")
-	err = rabbit.PublishQueue(ctx, queueHost, queueData)
-	if err != nil {
-		log.Printf("Error : %s", err)
-	}
-
 	// Populate response
 	h.SendSuccess(w, response.UserReedemHistoryRes{
 		UserCode:    userCode,
@@ -2,8 +2,8 @@ package handler
 
 import (
 	"context"
+	"database/sql"
 	"dots-api/bootstrap"
-	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
 	"dots-api/services/api/request"
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
 )
 
 // GetRoomList
@@ -33,6 +34,9 @@ func (h *Contract) GetRoomList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Private rooms are only listed in the CMS, members join them by invite
+	param.IncludePrivate = bootstrap.GetIdentifierChannelFromToken(ctx, r) == model.ChannelCMS
+
 	data, param, err := m.GetRoomList(h.DB, ctx, param)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
@@ -68,6 +72,10 @@ func (h *Contract) GetRoomList(w http.ResponseWriter, r *http.Request) {
 			GameCode:           v.GameCode,
 			GameName:           v.GameName,
 			GameImgUrl:         v.GameImgUrl,
+			Visibility:         v.Visibility,
+			RequiresApproval:   v.RequiresApproval,
+			HostUserCode:       v.HostUserCode.String,
+			HostUserName:       v.HostUserName.String,
 		})
 	}
 
@@ -93,6 +101,19 @@ func (h *Contract) GetRoomByCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Members only see a private room once they joined it
+	if roomInfo.Visibility == utils.RoomVisibilityPrivate && bootstrap.GetIdentifierChannelFromToken(ctx, r) == model.ChannelApp {
+		access, err := h.getRoomAccess(ctx, m, roomInfo.RoomId, userCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if !access.CanView() {
+			h.SendBadRequest(w, utils.ErrRoomPrivate)
+			return
+		}
+	}
+
 	// Check if already booked & status active
 	participant, err := m.GetParticipantByRoomCodeAndUserCode(h.DB, ctx, code, userCode)
 	if err != nil {
@@ -123,6 +144,10 @@ func (h *Contract) GetRoomByCode(w http.ResponseWriter, r *http.Request) {
 			Position:       participant.Position,
 			RewardPoint:    int(participant.RewardPoint.Int64),
 			LatestTier:     participant.LatestTier.String,
+			Score:          participant.Score.Int64,
+			Rank:           participant.Rank.Int64,
+			IsTied:         participant.IsTied,
+			Stats:          participant.Stats,
 		}
 		resRoomParticipant = append(resRoomParticipant, resParticipant)
 	}
@@ -148,6 +173,7 @@ func (h *Contract) GetRoomByCode(w http.ResponseWriter, r *http.Request) {
 		StartTime:          roomInfo.StartTime.Format(utils.TIME_FORMAT),
 		EndTime:            roomInfo.EndTime.Format(utils.TIME_FORMAT),
 		MaximumParticipant: roomInfo.MaximumParticipant,
+		MinimalParticipant: roomInfo.MinimalParticipant,
 		BookingPrice:       roomInfo.BookingPrice,
 		RewardPoint:        roomInfo.RewardPoint,
 		InstagramLink:      roomInfo.InstagramLink,
@@ -157,6 +183,10 @@ func (h *Contract) GetRoomByCode(w http.ResponseWriter, r *http.Request) {
 		CurrentUsedSlot:    roomInfo.CurrentUsedSlot,
 		RoomParticipant:    resRoomParticipant,
 		HaveJoined:         haveJoinedRoom,
+		Visibility:         roomInfo.Visibility,
+		RequiresApproval:   roomInfo.RequiresApproval,
+		HostUserCode:       roomInfo.HostUserCode.String,
+		HostUserName:       roomInfo.HostUserName.String,
 	}, nil)
 }
 
@@ -189,6 +219,20 @@ func (h *Contract) AddRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Visibility == "" {
+		req.Visibility = utils.RoomVisibilityPublic
+	}
+
+	if !utils.Contains(utils.RoomVisibility, req.Visibility) {
+		h.SendBadRequest(w, "wrong visibility value for rooms(public|private)")
+		return
+	}
+
+	if req.MinimalParticipant > req.MaximumParticipant {
+		h.SendBadRequest(w, utils.ErrInvalidMinimalParticipant)
+		return
+	}
+
 	if req.StartDate != "" {
 		// Convert start date string to time.Time
 		startDate, err = time.Parse(time.DateOnly, req.StartDate)
@@ -239,6 +283,19 @@ func (h *Contract) AddRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the game master is free, holding their lock until the room is saved
+	lock, err := h.lockGameMaster(ctx, m, gameMasterId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer lock.Rollback(ctx)
+
+	if err = h.checkGameMasterRoom(ctx, m, gameMasterId, req, ""); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
 	// Get Game Id
 	gameId, err := m.GetGameIdByCode(h.DB, ctx, req.GameCode)
 	if err != nil {
@@ -247,6 +304,7 @@ func (h *Contract) AddRoom(w http.ResponseWriter, r *http.Request) {
 	}
 	// Generate Random Code
 	code := utils.GeneratePrefixCode(utils.RoomPrefix)
+	inviteCode, _ := utils.Generate(`[A-Z0-9]{8}`)
 	err = m.AddRoom(
 		h.DB,
 		ctx,
@@ -267,8 +325,12 @@ func (h *Contract) AddRoom(w http.ResponseWriter, r *http.Request) {
 		req.Difficulty,
 		req.Instruction,
 		req.MaximumParticipant,
+		req.MinimalParticipant,
 		req.ImageURL,
-		locationCity)
+		locationCity,
+		req.Visibility,
+		req.RequiresApproval != nil && *req.RequiresApproval,
+		inviteCode)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -297,6 +359,32 @@ func (h *Contract) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	current, err := m.GetRoomByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// The access of the room is kept when the update leaves it out
+	if req.Visibility == "" {
+		req.Visibility = current.Visibility
+	}
+
+	if !utils.Contains(utils.RoomVisibility, req.Visibility) {
+		h.SendBadRequest(w, "wrong visibility value for rooms(public|private)")
+		return
+	}
+
+	requiresApproval := current.RequiresApproval
+	if req.RequiresApproval != nil {
+		requiresApproval = *req.RequiresApproval
+	}
+
+	if req.MinimalParticipant > req.MaximumParticipant {
+		h.SendBadRequest(w, utils.ErrInvalidMinimalParticipant)
+		return
+	}
+
 	// Validate LocationCity
 	locationCity, err := m.GetCafeLocationCityByCode(h.DB, ctx, req.LocationCode)
 	if err != nil {
@@ -311,6 +399,37 @@ func (h *Contract) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the game master is free, holding their lock until the room is saved
+	lock, err := h.lockGameMaster(ctx, m, gameMasterId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer lock.Rollback(ctx)
+
+	if err = h.checkGameMasterRoom(ctx, m, gameMasterId, req, code); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// Check the table of the room is still free
+	roomId, err := m.GetRoomIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	event := model.CafeTableEventEnt{EventType: utils.WaitlistRoom, EventId: roomId, Seats: req.MaximumParticipant, LocationCity: locationCity}
+	if event.Start, event.End, err = eventPeriod(req.StartDate, req.EndDate, req.StartTime, req.EndTime); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = h.checkEventTable(ctx, m, lock, event); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
 	// Get Game Id
 	gameId, err := m.GetGameIdByCode(h.DB, ctx, req.GameCode)
 	if err != nil {
@@ -354,8 +473,11 @@ func (h *Contract) UpdateRoom(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Rooms made before private rooms get their invite code on their first update
+	inviteCode, _ := utils.Generate(`[A-Z0-9]{8}`)
 	err = m.UpdateRoom(h.DB, ctx, code, gameMasterId, gameId, code, req.RoomType, req.Name, req.Description, startDate, endDate,
-		startTime, endTime, float64(req.BookingPrice), req.RewardPoint, req.InstagramLink, req.Status, req.Difficulty, req.Instruction, req.MaximumParticipant, req.ImageURL, locationCity)
+		startTime, endTime, float64(req.BookingPrice), req.RewardPoint, req.InstagramLink, req.Status, req.Difficulty, req.Instruction, req.MaximumParticipant, req.MinimalParticipant, req.ImageURL, locationCity,
+		req.Visibility, requiresApproval, inviteCode)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -394,15 +516,14 @@ func (h *Contract) BookingRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get current total participant
-	totalParticipant, err := m.CountParticipantRoomByRoomId(h.DB, ctx, room.RoomId)
+	// Private rooms are only booked by members who joined them
+	access, err := m.GetRoomAccess(h.DB, ctx, room.RoomId, int64(user.ID))
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
-
-	if totalParticipant >= room.MaximumParticipant {
-		h.SendBadRequest(w, "Sorry, this room is fully booked")
+	if err = access.CanBook(); err != nil {
+		h.SendBadRequest(w, err.Error())
 		return
 	}
 
@@ -433,45 +554,34 @@ func (h *Contract) BookingRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Start a transaction
-	tx, err := h.DB.Begin(ctx)
+	statusParticipant := "pending"
+	earnedPoint := int64(utils.CalculateUserRedeemPoint(room.BookingPrice))
+
+	// Hold the seat first, so the event is not locked while the invoice is created
+	err = m.HoldSeat(h.DB, ctx, utils.WaitlistRoom, room.RoomId, int64(user.ID), func(tx pgx.Tx) error {
+		// check if exist
+		if len(participant.UserCode) > 0 && participant.Status != "active" {
+			//update status
+			return m.UpdateRoomParticipant(tx, ctx, room.RoomId, int64(user.ID), false, participant.Position, statusParticipant, participant.AdditionalInfo.String, earnedPoint, "")
+		}
+		//add participant
+		return m.InsertOneRoomParticipant(tx, ctx, room.RoomId, int64(user.ID), statusParticipant, earnedPoint, "")
+	})
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-		tx.Commit(ctx)
-	}()
 
 	//call xendit
-	_, transactionCode, invoiceUrl, expiredAt, err := m.CreateOneTimeInvoice(tx, ctx, int64(user.ID), utils.UserPointType["ROOM_TYPE"], roomCode, room.BookingPrice, fmt.Sprintf("INVOICE-%s-%s", userCode, roomCode), user.Email.String)
+	invoiceUrl, expiredAt, err := h.invoiceSeat(ctx, m, utils.WaitlistRoom, room.RoomId, int64(user.ID), func(tx pgx.Tx) (string, string, time.Time, error) {
+		_, transactionCode, invoiceUrl, expiredAt, err := m.CreateOneTimeInvoice(tx, ctx, int64(user.ID), utils.UserPointType["ROOM_TYPE"], roomCode, room.BookingPrice, fmt.Sprintf("INVOICE-%s-%s", userCode, roomCode), user.Email.String)
+		return transactionCode, invoiceUrl, expiredAt, err
+	})
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
 
-	statusParticipant := "pending"
-	earnedPoint := int64(utils.CalculateUserRedeemPoint(room.BookingPrice))
-	// check if exist
-	if len(participant.UserCode) > 0 && participant.Status != "active" {
-		//update status
-		err = m.UpdateRoomParticipant(tx, ctx, room.RoomId, int64(user.ID), false, participant.Position, statusParticipant, participant.AdditionalInfo.String, earnedPoint, transactionCode)
-		if err != nil {
-			h.SendBadRequest(w, err.Error())
-			return
-		}
-	} else {
-		//add participant
-		err = m.InsertOneRoomParticipant(tx, ctx, room.RoomId, int64(user.ID), statusParticipant, earnedPoint, transactionCode)
-		if err != nil {
-			h.SendBadRequest(w, err.Error())
-			return
-		}
-	}
-
 	h.SendSuccess(w, response.BookingRes{
 		InvoiceUrl: invoiceUrl,
 		ExpiredAt:  expiredAt.Local().Format(utils.DATE_TIME_FORMAT),
@@ -533,21 +643,22 @@ func (h *Contract) SetWinnerRoomAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, req := range reqs.RoomParticipant {
-		var statusWinner bool
-		roomId, err := m.GetRoomIdByCode(h.DB, ctx, roomCode)
-		if err != nil {
-			h.SendBadRequest(w, err.Error())
-			return
-		}
+	results := roomResults(reqs.RoomParticipant)
+	roomId := room.RoomId
+	for i, req := range reqs.RoomParticipant {
+		var (
+			statusWinner       bool
+			userId             int64
+			roomParticipantEnt *model.RoomParticipantEnt
+		)
 
-		userId, err := m.GetUserIdByUserCode(h.DB, ctx, req.UserCode)
+		userId, err = m.GetUserIdByUserCode(h.DB, ctx, req.UserCode)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
 		}
 
-		roomParticipantEnt, err := m.GetOneRoomParticipant(h.DB, ctx, roomId, int64(userId))
+		roomParticipantEnt, err = m.GetOneRoomParticipant(h.DB, ctx, roomId, userId)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
@@ -557,34 +668,115 @@ func (h *Contract) SetWinnerRoomAct(w http.ResponseWriter, r *http.Request) {
 			statusWinner = true
 		}
 
-		err = m.UpdateRoomParticipant(tx, ctx, roomId, int64(userId), statusWinner, roomParticipantEnt.Position, roomParticipantEnt.Status, "member", int64(roomParticipantEnt.RewardPoint.Int64), roomParticipantEnt.TransactionCode.String)
+		err = m.UpdateRoomParticipant(tx, ctx, roomId, userId, statusWinner, req.Position, roomParticipantEnt.Status, "member", int64(roomParticipantEnt.RewardPoint.Int64), roomParticipantEnt.TransactionCode.String)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		err = m.SetRoomParticipantResultTrx(tx, ctx, roomId, userId, results[i])
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
 		}
 
 		// Publisher badge
-		queueData := rabbit.QueueDataPayload(
-			rabbit.QueueUserBadge,
-			rabbit.QueueUserBadgeReq(
-				utils.SpesificBoardGameCategory,
-				int64(userId),
-			),
-		)
-		queueHost := m.Config.GetString("queue.rabbitmq.host")
-		err = rabbit.PublishQueue(ctx, queueHost, queueData)
+		err = h.publishUserBadges(tx, ctx, userId, utils.SpesificBoardGameCategory, utils.RoomRankFinish)
 		if err != nil {
-			log.Printf("Error : %s", err)
+			h.SendBadRequest(w, err.Error())
+			return
 		}
 	}
 
 	for _, participant := range participants {
 		_ = m.AddUserGameCollections(h.DB, ctx, participant.UserId, room.GameId)
+
+		err = h.publishUserBadges(tx, ctx, participant.UserId, utils.CafeVisit, utils.SocialPlay, utils.GameDiversity)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	if room.GameMasterCode.Valid {
+		gameMasterUserId, userErr := m.GetUserIdByAdminCode(h.DB, ctx, room.GameMasterCode.String)
+		if userErr != nil {
+			log.Printf("Error : %s", userErr)
+		} else if gameMasterUserId > 0 {
+			err = h.publishUserBadges(tx, ctx, gameMasterUserId, utils.GameMasterSession)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+	}
+
+	if room.HostUserCode.Valid {
+		hostUserId, userErr := m.GetUserIdByUserCode(h.DB, ctx, room.HostUserCode.String)
+		if userErr != nil {
+			log.Printf("Error : %s", userErr)
+		} else {
+			err = h.publishUserBadges(tx, ctx, hostUserId, utils.MemberHostSession)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
 	}
 
 	h.SendSuccess(w, nil, nil)
 }
 
+// roomResults returns the final result of each participant in order. Ranks
+// given by the game master are kept. Otherwise the participants are ranked by
+// score, highest first, or by position when there are no scores. Participants
+// with the same score share a rank and the next rank is skipped (1, 1, 3).
+func roomResults(participants []request.RoomParticipant) []model.RoomResultEnt {
+	var (
+		results  = make([]model.RoomResultEnt, len(participants))
+		hasRank  bool
+		hasScore bool
+	)
+	for _, p := range participants {
+		hasRank = hasRank || p.Rank > 0
+		hasScore = hasScore || p.Score != nil
+	}
+
+	for i, p := range participants {
+		results[i].Stats = p.Stats
+		if p.Score != nil {
+			results[i].Score = sql.NullInt64{Int64: *p.Score, Valid: true}
+		}
+
+		switch {
+		case hasRank:
+			results[i].Rank = p.Rank
+		case hasScore:
+			if p.Score == nil {
+				continue
+			}
+			results[i].Rank = 1
+			for _, o := range participants {
+				if o.Score != nil && *o.Score > *p.Score {
+					results[i].Rank++
+				}
+			}
+		default:
+			results[i].Rank = p.Position
+		}
+	}
+
+	ranks := map[int]int{}
+	for _, v := range results {
+		ranks[v.Rank]++
+	}
+	for i, v := range results {
+		results[i].IsTied = v.Rank > 0 && ranks[v.Rank] > 1
+	}
+
+	return results
+}
+
 func (h *Contract) UpdateRoomStatus(w http.ResponseWriter, r *http.Request) {
 	var (
 		err      error
@@ -662,6 +854,11 @@ type roomParams struct {
 }
 
 func isRoomClosed(w http.ResponseWriter, h *Contract, params roomParams) bool {
+	if params.RoomStatus == utils.RoomStatus["CANCELLED"] {
+		h.SendBadRequest(w, utils.ErrEventCancelled)
+		return true
+	}
+
 	if params.RoomStatus != "closed" {
 		return false
 	}
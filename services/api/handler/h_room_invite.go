@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"dots-api/bootstrap"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetRoomInviteAct returns the invite code and link the host shares to let
+// members join a private room.
+func (h *Contract) GetRoomInviteAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	room, err := m.GetRoomInviteByRoomCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// Rooms made before private rooms have no invite code until one is made
+	if !room.InviteCode.Valid {
+		if room, err = h.resetRoomInvite(ctx, m, room); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	h.SendSuccess(w, h.roomInviteRes(room), nil)
+}
+
+// ResetRoomInviteAct replaces the invite code of a room, so a leaked code or
+// link stops working.
+func (h *Contract) ResetRoomInviteAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	room, err := m.GetRoomInviteByRoomCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if room, err = h.resetRoomInvite(ctx, m, room); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, h.roomInviteRes(room), nil)
+}
+
+// JoinRoomAct joins a private room with its invite code. The member can book
+// it right away, or once the host approves them when the room asks for it.
+func (h *Contract) JoinRoomAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		req      = request.JoinRoomReq{}
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	room, err := m.GetRoomInviteByInviteCode(h.DB, ctx, req.InviteCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	status := utils.RoomJoinApproved
+	if room.RequiresApproval {
+		status = utils.RoomJoinPending
+	}
+
+	status, err = m.JoinRoom(h.DB, ctx, room.RoomId, userId, status)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, response.JoinRoomRes{
+		RoomCode: room.RoomCode,
+		Name:     room.Name,
+		Status:   status,
+	}, nil)
+}
+
+// GetRoomJoinRequestsAct returns the members who joined a private room, with
+// an optional status filter for the ones waiting for approval.
+func (h *Contract) GetRoomJoinRequestsAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		ctx    = context.TODO()
+		m      = model.Contract{App: h.App}
+		code   = chi.URLParam(r, "code")
+		status = r.URL.Query().Get("status")
+		res    = make([]response.RoomJoinRequestRes, 0)
+	)
+
+	if status != "" && status != utils.RoomJoinPending && !utils.Contains(utils.RoomJoinReviewStatus, status) {
+		h.SendBadRequest(w, "wrong status value for room join requests(pending|approved|rejected)")
+		return
+	}
+
+	roomId, err := m.GetRoomIdByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetRoomJoinRequests(h.DB, ctx, roomId, status)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		data := response.RoomJoinRequestRes{
+			UserCode:    v.UserCode,
+			UserName:    v.UserName,
+			UserImgUrl:  v.UserImgUrl,
+			Status:      v.Status,
+			ReviewedBy:  v.ReviewedBy.String,
+			CreatedDate: v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+		}
+		if v.ReviewedDate.Valid {
+			data.ReviewedDate = v.ReviewedDate.Time.Format(utils.DATE_TIME_FORMAT)
+		}
+		res = append(res, data)
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// ReviewRoomJoinRequestAct approves or rejects a member waiting to join a
+// private room and lets them know.
+func (h *Contract) ReviewRoomJoinRequestAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		req       = request.ReviewRoomJoinReq{}
+		code      = chi.URLParam(r, "code")
+		userCode  = chi.URLParam(r, "user_code")
+		adminCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if !utils.Contains(utils.RoomJoinReviewStatus, req.Status) {
+		h.SendBadRequest(w, "wrong status value for room join requests(approved|rejected)")
+		return
+	}
+
+	room, err := m.GetRoomInviteByRoomCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.ReviewRoomJoinRequest(h.DB, ctx, room.RoomId, userId, req.Status, adminCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	m.NotifyRoomJoinReviewed(h.DB, ctx, room, userId, req.Status)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// getRoomAccess returns the access of a member to a room.
+func (h *Contract) getRoomAccess(ctx context.Context, m model.Contract, roomId int64, userCode string) (model.RoomAccessEnt, error) {
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		return model.RoomAccessEnt{}, err
+	}
+
+	return m.GetRoomAccess(h.DB, ctx, roomId, userId)
+}
+
+// resetRoomInvite gives a room a new invite code.
+func (h *Contract) resetRoomInvite(ctx context.Context, m model.Contract, room model.RoomInviteEnt) (model.RoomInviteEnt, error) {
+	inviteCode, err := utils.Generate(`[A-Z0-9]{8}`)
+	if err != nil {
+		return room, errors.New(utils.ErrResettingRoomInvite)
+	}
+
+	if err = m.ResetRoomInviteCode(h.DB, ctx, room.RoomId, inviteCode); err != nil {
+		return room, err
+	}
+
+	room.InviteCode.String, room.InviteCode.Valid = inviteCode, true
+	return room, nil
+}
+
+func (h *Contract) roomInviteRes(room model.RoomInviteEnt) response.RoomInviteRes {
+	return response.RoomInviteRes{
+		RoomCode:         room.RoomCode,
+		Visibility:       room.Visibility,
+		RequiresApproval: room.RequiresApproval,
+		InviteCode:       room.InviteCode.String,
+		InviteLink:       h.Config.GetString("web_url") + utils.RoomInviteRoute + room.InviteCode.String,
+	}
+}
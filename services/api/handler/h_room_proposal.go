@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"context"
+	"dots-api/bootstrap"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetRoomProposalListAct returns the approval queue in the CMS and the
+// member's own proposals in the app.
+func (h *Contract) GetRoomProposalListAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		res   = make([]response.RoomProposalRes, 0)
+		param = request.RoomProposalParam{}
+	)
+
+	err = param.ParseRoomProposal(r.URL.Query())
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if bootstrap.GetIdentifierChannelFromToken(ctx, r) == model.ChannelApp {
+		param.UserCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	}
+
+	data, param, err := m.GetRoomProposalList(h.DB, ctx, param)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range data {
+		res = append(res, roomProposalRes(v))
+	}
+
+	h.SendSuccess(w, res, param)
+}
+
+func (h *Contract) GetRoomProposalDetailAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	data, err := h.getRoomProposal(ctx, m, r, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, roomProposalRes(data), nil)
+}
+
+// AddRoomProposalAct proposes a session at a game of a cafe library. It
+// becomes a room once a cafe admin approves it.
+func (h *Contract) AddRoomProposalAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		req      = request.RoomProposalReq{}
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+		data     = model.RoomProposalEnt{ProposalCode: utils.GeneratePrefixCode(utils.RoomProposalPrefix)}
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if data.StartDate, err = time.Parse(time.DateOnly, req.StartDate); err != nil {
+		h.SendBadRequest(w, utils.ErrInvalidStartDateFormat)
+		return
+	}
+	if !data.StartDate.After(recurrence.Today()) {
+		h.SendBadRequest(w, utils.ErrRoomProposalPastDate)
+		return
+	}
+
+	if data.StartTime, err = time.Parse(time.TimeOnly, req.StartTime); err != nil {
+		h.SendBadRequest(w, utils.ErrInvalidStartTimeFormat)
+		return
+	}
+
+	if data.EndTime, err = time.Parse(time.TimeOnly, req.EndTime); err != nil {
+		h.SendBadRequest(w, utils.ErrInvalidEndTimeFormat)
+		return
+	}
+	if !data.EndTime.After(data.StartTime) {
+		h.SendBadRequest(w, utils.ErrRoomProposalTime)
+		return
+	}
+
+	if data.GameId, err = m.GetProposableGameId(h.DB, ctx, req.GameCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if data.UserId, err = m.GetUserIdByUserCode(h.DB, ctx, userCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data.Name = req.Name
+	data.Description = req.Description
+	data.MaximumParticipant = req.MaximumParticipant
+	if err = m.AddRoomProposal(h.DB, ctx, data); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data, err = m.GetRoomProposalByCode(h.DB, ctx, data.ProposalCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, roomProposalRes(data), nil)
+}
+
+// CancelRoomProposalAct withdraws a proposal still waiting for review.
+func (h *Contract) CancelRoomProposalAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		code = chi.URLParam(r, "code")
+	)
+
+	data, err := h.getRoomProposal(ctx, m, r, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.CancelRoomProposal(h.DB, ctx, data.Id); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// ApproveRoomProposalAct opens a proposal as a normal room hosted by the
+// proposing member, with the price and game master set by the admin.
+func (h *Contract) ApproveRoomProposalAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		req       = request.ApproveRoomProposalReq{}
+		code      = chi.URLParam(r, "code")
+		adminCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	if req.RoomType == "" {
+		req.RoomType = "normal"
+	}
+	if !utils.Contains(utils.RoomType, req.RoomType) {
+		h.SendBadRequest(w, "wrong type value for rooms(normal|special_event)")
+		return
+	}
+
+	if req.GameMasterCode == "" {
+		req.GameMasterCode = adminCode
+	}
+
+	data, err := m.GetRoomProposalByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if data.Status != utils.RoomProposalPending {
+		h.SendBadRequest(w, utils.ErrRoomProposalNotPending)
+		return
+	}
+
+	room := model.ProposedRoomEnt{
+		RoomCode:      utils.GeneratePrefixCode(utils.RoomPrefix),
+		RoomType:      req.RoomType,
+		Difficulty:    req.Difficulty,
+		Instruction:   req.Instruction,
+		BookingPrice:  req.BookingPrice,
+		RewardPoint:   req.RewardPoint,
+		InstagramLink: req.InstagramLink,
+		ImageUrl:      req.ImageURL,
+	}
+	room.InviteCode, _ = utils.Generate(`[A-Z0-9]{8}`)
+
+	if room.GameMasterId, err = m.GetAdminIdByCode(h.DB, ctx, req.GameMasterCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if room.LocationCity, err = m.GetCafeLocationCityByCode(h.DB, ctx, data.CafeCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Check the game master is free, holding their lock until the room is saved
+	if err = m.LockGameMasterTrx(tx, ctx, room.GameMasterId); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	startDate := data.StartDate.Format(time.DateOnly)
+	err = h.checkGameMasterRoom(ctx, m, room.GameMasterId, request.RoomReq{
+		StartDate: startDate,
+		EndDate:   startDate,
+		StartTime: data.StartTime.Format(time.TimeOnly),
+		EndTime:   data.EndTime.Format(time.TimeOnly),
+	}, "")
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.ApproveRoomProposalTrx(tx, ctx, data, room, adminCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	m.NotifyRoomProposalReviewed(h.DB, ctx, data, room.RoomCode, "")
+
+	data, err = m.GetRoomProposalByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, roomProposalRes(data), nil)
+}
+
+func (h *Contract) RejectRoomProposalAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err       error
+		ctx       = context.TODO()
+		m         = model.Contract{App: h.App}
+		req       = request.RejectRoomProposalReq{}
+		code      = chi.URLParam(r, "code")
+		adminCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	data, err := m.GetRoomProposalByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.RejectRoomProposal(h.DB, ctx, data.Id, req.Reason, adminCode); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	m.NotifyRoomProposalReviewed(h.DB, ctx, data, "", req.Reason)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// getRoomProposal returns a proposal. Members only get their own.
+func (h *Contract) getRoomProposal(ctx context.Context, m model.Contract, r *http.Request, code string) (model.RoomProposalEnt, error) {
+	data, err := m.GetRoomProposalByCode(h.DB, ctx, code)
+	if err != nil {
+		return data, err
+	}
+
+	if bootstrap.GetIdentifierChannelFromToken(ctx, r) == model.ChannelApp && data.UserCode != bootstrap.GetIdentifierCodeFromToken(ctx, r) {
+		return data, errors.New(utils.ErrGettingRoomProposal)
+	}
+
+	return data, nil
+}
+
+func roomProposalRes(v model.RoomProposalEnt) response.RoomProposalRes {
+	res := response.RoomProposalRes{
+		ProposalCode:       v.ProposalCode,
+		UserCode:           v.UserCode,
+		UserName:           v.UserName,
+		CafeCode:           v.CafeCode,
+		CafeName:           v.CafeName,
+		GameCode:           v.GameCode,
+		GameName:           v.GameName,
+		GameImgUrl:         v.GameImgUrl,
+		Name:               v.Name,
+		Description:        v.Description,
+		StartDate:          v.StartDate.Format(utils.DATE_FORMAT),
+		StartTime:          v.StartTime.Format(utils.TIME_FORMAT),
+		EndTime:            v.EndTime.Format(utils.TIME_FORMAT),
+		MaximumParticipant: v.MaximumParticipant,
+		Status:             v.Status,
+		RoomCode:           v.RoomCode.String,
+		RejectReason:       v.RejectReason.String,
+		ReviewedBy:         v.ReviewedBy.String,
+		CreatedDate:        v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+	}
+	if v.ReviewedDate.Valid {
+		res.ReviewedDate = v.ReviewedDate.Time.Format(utils.DATE_TIME_FORMAT)
+	}
+
+	return res
+}
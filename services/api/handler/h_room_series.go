@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/bootstrap"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Contract) GetRoomSeriesListAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		res   = make([]response.RoomSeriesRes, 0)
+		param = request.RoomSeriesParam{}
+	)
+
+	err = param.ParseRoomSeries(r.URL.Query())
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data, param, err := m.GetRoomSeriesList(h.DB, ctx, param)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range data {
+		res = append(res, roomSeriesRes(v))
+	}
+
+	h.SendSuccess(w, res, param)
+}
+
+// GetRoomSeriesDetailAct returns a series with its upcoming rooms and whether
+// the member is subscribed to it.
+func (h *Contract) GetRoomSeriesDetailAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	series, err := m.GetRoomSeriesByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	occurrences, err := m.GetRoomSeriesOccurrences(h.DB, ctx, series.Id, recurrence.Today())
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	subscribed, err := m.IsRoomSeriesSubscriber(h.DB, ctx, series.Id, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	res := roomSeriesRes(series)
+	res.IsSubscribed = subscribed
+	res.Occurrences = make([]response.RoomSeriesOccurrenceRes, 0)
+	for _, v := range occurrences {
+		res.Occurrences = append(res.Occurrences, response.RoomSeriesOccurrenceRes{
+			RoomCode:       v.RoomCode,
+			OccurrenceDate: v.OccurrenceDate.Format(utils.DATE_FORMAT),
+			Status:         v.Status,
+			IsDetached:     v.IsDetached,
+		})
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// AddRoomSeriesAct creates a series and its rooms for the next
+// utils.RoomSeriesGenerateDays days.
+func (h *Contract) AddRoomSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		req   = request.RoomSeriesReq{}
+		today = recurrence.Today()
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	series, err := h.parseRoomSeries(ctx, m, req)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	series.SeriesCode = utils.GeneratePrefixCode(utils.RoomSeriesPrefix)
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Check the game master is free, holding their lock until the rooms are saved
+	if err = m.LockGameMasterTrx(tx, ctx, series.GameMasterId); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = h.checkGameMasterSeries(ctx, m, series, today, today.AddDate(0, 0, utils.RoomSeriesGenerateDays)); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	series.Id, err = m.AddRoomSeriesTrx(tx, ctx, series)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	_, err = m.GenerateRoomSeriesTrx(tx, ctx, series, today, today.AddDate(0, 0, utils.RoomSeriesGenerateDays), nil)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// UpdateRoomSeriesAct edits a series and all its rooms from today on. Rooms
+// edited on their own keep their changes. Rooms on dates the rule no longer
+// covers, e.g. a new skipped holiday, are removed, and new dates get their
+// rooms. The update is rejected when one of the removed rooms has bookings.
+// Subscribers are told about the new rooms.
+func (h *Contract) UpdateRoomSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err   error
+		ctx   = context.TODO()
+		m     = model.Contract{App: h.App}
+		req   = request.RoomSeriesReq{}
+		code  = chi.URLParam(r, "code")
+		today = recurrence.Today()
+		upTo  = today.AddDate(0, 0, utils.RoomSeriesGenerateDays)
+	)
+
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	current, err := m.GetRoomSeriesByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	series, err := h.parseRoomSeries(ctx, m, req)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	series.Id = current.Id
+	series.SeriesCode = current.SeriesCode
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Check the game master is free, holding their lock until the rooms are saved
+	if err = m.LockGameMasterTrx(tx, ctx, series.GameMasterId); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = h.checkGameMasterSeries(ctx, m, series, today, upTo); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.UpdateRoomSeriesTrx(tx, ctx, series); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.RemoveSeriesRoomsTrx(tx, ctx, series.Id, today, series.Rule().Dates(upTo)); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = m.UpdateFutureSeriesRoomsTrx(tx, ctx, series, today); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	occurrences, err := m.GenerateRoomSeriesTrx(tx, ctx, series, today, upTo, nil)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	m.NotifyRoomSeriesSubscribers(h.DB, ctx, series, occurrences)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+func (h *Contract) SubscribeRoomSeriesAct(w http.ResponseWriter, r *http.Request) {
+	h.subscribeRoomSeries(w, r, true)
+}
+
+func (h *Contract) UnsubscribeRoomSeriesAct(w http.ResponseWriter, r *http.Request) {
+	h.subscribeRoomSeries(w, r, false)
+}
+
+// subscribeRoomSeries subscribes the member to the new rooms of a series, or
+// unsubscribes them.
+func (h *Contract) subscribeRoomSeries(w http.ResponseWriter, r *http.Request, subscribe bool) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	series, err := m.GetRoomSeriesByCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if subscribe {
+		err = m.SubscribeRoomSeries(h.DB, ctx, series.Id, userId)
+	} else {
+		err = m.UnsubscribeRoomSeries(h.DB, ctx, series.Id, userId)
+	}
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// parseRoomSeries validates a series request and maps it to a series.
+func (h *Contract) parseRoomSeries(ctx context.Context, m model.Contract, req request.RoomSeriesReq) (model.RoomSeriesEnt, error) {
+	var (
+		err    error
+		series = model.RoomSeriesEnt{
+			RoomType:           req.RoomType,
+			Name:               req.Name,
+			Description:        req.Description,
+			Instruction:        req.Instruction,
+			Difficulty:         req.Difficulty,
+			MaximumParticipant: req.MaximumParticipant,
+			BookingPrice:       req.BookingPrice,
+			RewardPoint:        req.RewardPoint,
+			InstagramLink:      req.InstagramLink,
+			ImageUrl:           req.ImageURL,
+			Status:             req.Status,
+			Frequency:          req.Frequency,
+			OccurrenceCount:    req.OccurrenceCount,
+			SkipDates:          make([]time.Time, 0),
+		}
+	)
+
+	if !utils.Contains(utils.StatusRoomSeries, req.Status) {
+		return series, errors.New("wrong status value for room series(active|inactive)")
+	}
+
+	if !utils.Contains(utils.RoomType, req.RoomType) {
+		return series, errors.New("wrong type value for rooms(normal|special_event)")
+	}
+
+	if series.StartDate, err = time.Parse(time.DateOnly, req.StartDate); err != nil {
+		return series, errors.New(utils.ErrInvalidStartDateFormat)
+	}
+
+	if req.UntilDate != "" {
+		untilDate, err := time.Parse(time.DateOnly, req.UntilDate)
+		if err != nil {
+			return series, errors.New(utils.ErrInvalidEndDateFormat)
+		}
+		series.UntilDate = sql.NullTime{Time: untilDate, Valid: true}
+	}
+
+	for _, v := range req.SkipDates {
+		skipDate, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return series, errors.New(utils.ErrInvalidSkipDateFormat)
+		}
+		series.SkipDates = append(series.SkipDates, skipDate)
+	}
+
+	if series.StartTime, err = time.Parse(time.TimeOnly, req.StartTime); err != nil {
+		return series, errors.New(utils.ErrInvalidStartTimeFormat)
+	}
+
+	if series.EndTime, err = time.Parse(time.TimeOnly, req.EndTime); err != nil {
+		return series, errors.New(utils.ErrInvalidEndTimeFormat)
+	}
+
+	for _, v := range req.Weekdays {
+		series.Weekdays = append(series.Weekdays, int32(v))
+	}
+
+	if err = series.Rule().Validate(); err != nil {
+		return series, err
+	}
+
+	if series.LocationCity, err = m.GetCafeLocationCityByCode(h.DB, ctx, req.LocationCode); err != nil {
+		return series, err
+	}
+
+	if series.GameMasterId, err = m.GetAdminIdByCode(h.DB, ctx, req.GameMasterCode); err != nil {
+		return series, err
+	}
+
+	if series.GameId, err = m.GetGameIdByCode(h.DB, ctx, req.GameCode); err != nil {
+		return series, err
+	}
+
+	return series, nil
+}
+
+func roomSeriesRes(v model.RoomSeriesEnt) response.RoomSeriesRes {
+	res := response.RoomSeriesRes{
+		SeriesCode:         v.SeriesCode,
+		GameMasterCode:     v.GameMasterCode.String,
+		GameMasterName:     v.GameMasterName.String,
+		GameCode:           v.GameCode,
+		GameName:           v.GameName,
+		RoomType:           v.RoomType,
+		Name:               v.Name,
+		Description:        v.Description,
+		Instruction:        v.Instruction,
+		Difficulty:         v.Difficulty,
+		StartTime:          v.StartTime.Format(utils.TIME_FORMAT),
+		EndTime:            v.EndTime.Format(utils.TIME_FORMAT),
+		MaximumParticipant: v.MaximumParticipant,
+		BookingPrice:       v.BookingPrice,
+		RewardPoint:        v.RewardPoint,
+		InstagramLink:      v.InstagramLink,
+		ImageUrl:           v.ImageUrl,
+		LocationCity:       v.LocationCity,
+		Frequency:          v.Frequency,
+		Weekdays:           v.Weekdays,
+		StartDate:          v.StartDate.Format(utils.DATE_FORMAT),
+		OccurrenceCount:    v.OccurrenceCount,
+		SkipDates:          make([]string, 0),
+		Status:             v.Status,
+		SubscriberCount:    v.SubscriberCount,
+	}
+	if v.UntilDate.Valid {
+		res.UntilDate = v.UntilDate.Time.Format(utils.DATE_FORMAT)
+	}
+	for _, d := range v.SkipDates {
+		res.SkipDates = append(res.SkipDates, d.Format(utils.DATE_FORMAT))
+	}
+
+	return res
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"dots-api/lib/s3"
+	"dots-api/lib/sharecard"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/request"
+	"dots-api/services/api/response"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const shareCardDateFormat = "02 January 2006"
+
+// CreateShareCardAct renders a shareable PNG card for a member's badge, current
+// tier or tournament placing and returns its URL. Cards are cached in the
+// upload storage and only rendered again when their content changes. A card
+// rendered without its image is not cached, so the image is fetched again on
+// the next request.
+func (h *Contract) CreateShareCardAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err            error
+		ctx            = context.TODO()
+		m              = model.Contract{App: h.App}
+		req            = request.ShareCardReq{}
+		code           = chi.URLParam(r, "code")
+		uploadContract = s3.New(h.App)
+	)
+
+	// Binding and Validate
+	if err = h.BindAndValidate(r, &req); err != nil {
+		h.SendBindAndValidateError(w, err)
+		return
+	}
+
+	content, err := m.GetShareCardContent(h.DB, ctx, code, req.CardType, req.ReferenceCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	cached, err := m.GetShareCard(h.DB, ctx, content.UserId, req.CardType, content.ReferenceCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	// A tier has no earning date, so the card keeps the date it was first rendered
+	date := time.Now().UTC()
+	if content.Date.Valid {
+		date = content.Date.Time
+	} else if cached.Id != 0 {
+		date = cached.CreatedDate
+	}
+
+	card := sharecard.Card{
+		Brand:      utils.ShareCardBrand,
+		Heading:    shareCardHeading(req.CardType, content.Position),
+		Title:      content.Title,
+		MemberName: content.MemberName,
+		Date:       date.In(utils.GetTimeLocationWIB()).Format(shareCardDateFormat),
+		CafeName:   content.CafeName,
+		ImageURL:   content.ImageURL,
+	}
+
+	contentHash, err := shareCardHash(card)
+	if err != nil {
+		h.SendBadRequest(w, utils.ErrRenderingShareCard)
+		return
+	}
+
+	if cached.Id == 0 || cached.ContentHash != contentHash {
+		image, degraded, err := sharecard.Render(card)
+		if err != nil {
+			log.Printf("Error : %s", err)
+			h.SendBadRequest(w, utils.ErrRenderingShareCard)
+			return
+		}
+
+		name := fmt.Sprintf("%s/%s-%s-%s-%s.png", utils.ShareCardPath, code, req.CardType, content.ReferenceCode, contentHash[:12])
+		if degraded {
+			name = fmt.Sprintf("%s/%s-%s-%s-%s-fallback.png", utils.ShareCardPath, code, req.CardType, content.ReferenceCode, contentHash[:12])
+		}
+		imageURL, err := uploadContract.UploadObjectS3(name, "image/png", image)
+		if err != nil {
+			log.Printf("Error : %s", err)
+			h.SendBadRequest(w, utils.ErrUploadingShareCard)
+			return
+		}
+
+		if degraded {
+			h.SendSuccess(w, response.ShareCardRes{
+				CardType:      req.CardType,
+				ReferenceCode: content.ReferenceCode,
+				ImageURL:      imageURL,
+				CreatedDate:   time.Now().UTC().Format(utils.DATE_TIME_FORMAT),
+			}, nil)
+			return
+		}
+
+		cached, err = m.SaveShareCard(h.DB, ctx, content.UserId, req.CardType, content.ReferenceCode, contentHash, imageURL)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	h.SendSuccess(w, response.ShareCardRes{
+		CardType:      cached.CardType,
+		ReferenceCode: cached.ReferenceCode,
+		ImageURL:      cached.ImageURL,
+		CreatedDate:   cached.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+		UpdatedDate:   formatNullTime(cached.UpdatedDate),
+	}, nil)
+}
+
+// shareCardHeading returns the line shown above the card image.
+func shareCardHeading(cardType string, position int) string {
+	switch cardType {
+	case utils.ShareCardTypeTier:
+		return "Tier Up"
+	case utils.ShareCardTypeTournament:
+		return fmt.Sprintf("Tournament %s Place", ordinal(position))
+	default:
+		return "Badge Unlocked"
+	}
+}
+
+// shareCardHash identifies the content of a card, so a cached card is reused
+// until the badge, member name or any other shown value changes.
+func shareCardHash(card sharecard.Card) (string, error) {
+	data, err := json.Marshal(card)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -3,18 +3,17 @@ package handler
 import (
 	"context"
 	"dots-api/bootstrap"
-	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
 	"dots-api/services/api/request"
 	"dots-api/services/api/response"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
 )
 
 // GetTournamentList
@@ -237,6 +236,7 @@ func (h *Contract) GetTournamentDetailAct(w http.ResponseWriter, r *http.Request
 		StartTime:              dataTournament.StartTime.Format(utils.TIME_FORMAT),
 		EndTime:                dataTournament.EndTime.Format(utils.TIME_FORMAT),
 		PlayerSlot:             dataTournament.PlayerSlot,
+		MinimalParticipant:     dataTournament.MinimalParticipant,
 		ParticipantVP:          dataTournament.ParticipantVP,
 		Status:                 dataTournament.Status,
 		CurrentUsedSlot:        dataTournament.CurrentUsedSlot,
@@ -278,6 +278,11 @@ func (h *Contract) AddTournamentAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.MinimalParticipant > req.PlayerSlot {
+		h.SendBadRequest(w, utils.ErrInvalidMinimalParticipant)
+		return
+	}
+
 	// Start a transaction
 	tx, err := h.DB.Begin(ctx)
 	if err != nil {
@@ -366,6 +371,7 @@ func (h *Contract) AddTournamentAct(w http.ResponseWriter, r *http.Request) {
 		startTime,
 		endTime,
 		req.PlayerSlot,
+		req.MinimalParticipant,
 		req.ParticipantVP,
 		req.Status,
 		locationCity,
@@ -451,6 +457,11 @@ func (h *Contract) UpdateTournamentAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.MinimalParticipant > req.PlayerSlot {
+		h.SendBadRequest(w, utils.ErrInvalidMinimalParticipant)
+		return
+	}
+
 	// Start a transaction
 	tx, err := h.DB.Begin(ctx)
 	if err != nil {
@@ -522,6 +533,20 @@ func (h *Contract) UpdateTournamentAct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check the table of the tournament is still free
+	if req.StartDate != "" {
+		event := model.CafeTableEventEnt{EventType: utils.WaitlistTournament, EventId: tournamentId, Seats: int(req.PlayerSlot), LocationCity: locationCity}
+		if event.Start, event.End, err = eventPeriod(req.StartDate, req.EndDate, req.StartTime, req.EndTime); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		if err = h.checkEventTable(ctx, m, tx, event); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
 	if req.Status == "inactive" {
 		// Get current total participant
 		totalParticipant, err := m.CountParticipantTournamentByTournamentId(h.DB, ctx, tournamentId)
@@ -541,7 +566,7 @@ func (h *Contract) UpdateTournamentAct(w http.ResponseWriter, r *http.Request) {
 		tx, ctx, gameId, tournamentCode, req.ImageUrl,
 		req.Name, req.TournamentRules, req.Level, req.Status,
 		req.PrizesImgUrl, req.BookingPrice, startDate, endDate,
-		startTime, endTime, req.PlayerSlot,
+		startTime, endTime, req.PlayerSlot, req.MinimalParticipant,
 		req.ParticipantVP, locationCity,
 	)
 	if err != nil {
@@ -689,17 +714,10 @@ func (h *Contract) SetWinnerTournamentAct(w http.ResponseWriter, r *http.Request
 			}
 
 			// Publisher badge
-			queueData := rabbit.QueueDataPayload(
-				rabbit.QueueUserBadge,
-				rabbit.QueueUserBadgeReq(
-					utils.SpesificBoardGameCategory,
-					int64(userId),
-				),
-			)
-			queueHost := m.Config.GetString("queue.rabbitmq.host")
-			err = rabbit.PublishQueue(ctx, queueHost, queueData)
+			err = h.publishUserBadges(tx, ctx, int64(userId), utils.SpesificBoardGameCategory)
 			if err != nil {
-				log.Printf("Error : %s", err)
+				h.SendBadRequest(w, err.Error())
+				return
 			}
 		} else {
 			// Update participant tournament info
@@ -720,6 +738,12 @@ func (h *Contract) SetWinnerTournamentAct(w http.ResponseWriter, r *http.Request
 
 	for _, participant := range participants {
 		_ = m.AddUserGameCollections(h.DB, ctx, participant.UserId, tournamentData.GameId)
+
+		err = h.publishUserBadges(tx, ctx, participant.UserId, utils.CafeVisit, utils.SocialPlay, utils.GameDiversity)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
 	}
 
 	h.SendSuccess(w, nil, nil)
@@ -789,18 +813,6 @@ func (h *Contract) BookingTournamentAct(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Get current total participant
-	totalParticipant, err := m.CountParticipantTournamentByTournamentId(h.DB, ctx, trnm.TournamentId)
-	if err != nil {
-		h.SendBadRequest(w, err.Error())
-		return
-	}
-
-	if totalParticipant >= int(trnm.PlayerSlot) {
-		h.SendBadRequest(w, "Sorry, this tournament is fully booked")
-		return
-	}
-
 	// Check if already booked
 	participant, err := m.GetOneTournamentParticipant(h.DB, ctx, trnm.TournamentId, int64(user.ID))
 	if err != nil && err.Error() != utils.EmptyData {
@@ -828,45 +840,34 @@ func (h *Contract) BookingTournamentAct(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Start a transaction
-	tx, err := h.DB.Begin(ctx)
+	statusParticipant := "pending"
+	earnedPoint := int64(utils.CalculateUserRedeemPoint(trnm.BookingPrice))
+
+	// Hold the seat first, so the event is not locked while the invoice is created
+	err = m.HoldSeat(h.DB, ctx, utils.WaitlistTournament, trnm.TournamentId, int64(user.ID), func(tx pgx.Tx) error {
+		// check if exist
+		if participant.Id > 0 && participant.Status != "active" {
+			//update status
+			return m.UpdateTournamentParticipant(tx, ctx, trnm.TournamentId, int64(user.ID), false, participant.Position, statusParticipant, participant.AdditionalInfo.String, earnedPoint, "")
+		}
+		//add participant
+		return m.InsertOneTournamentParticipant(tx, ctx, trnm.TournamentId, int64(user.ID), false, 0, statusParticipant, participant.AdditionalInfo.String, earnedPoint, "")
+	})
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback(ctx)
-		}
-		tx.Commit(ctx)
-	}()
 
 	//call xendit
-	_, transactionCode, invoiceUrl, expiredAt, err := m.CreateOneTimeInvoice(tx, ctx, int64(user.ID), utils.UserPointType["TOURNAMENT_TYPE"], tournamentCode, trnm.BookingPrice, fmt.Sprintf("INVOICE-%s-%s", userCode, tournamentCode), user.Email.String)
+	invoiceUrl, expiredAt, err := h.invoiceSeat(ctx, m, utils.WaitlistTournament, trnm.TournamentId, int64(user.ID), func(tx pgx.Tx) (string, string, time.Time, error) {
+		_, transactionCode, invoiceUrl, expiredAt, err := m.CreateOneTimeInvoice(tx, ctx, int64(user.ID), utils.UserPointType["TOURNAMENT_TYPE"], tournamentCode, trnm.BookingPrice, fmt.Sprintf("INVOICE-%s-%s", userCode, tournamentCode), user.Email.String)
+		return transactionCode, invoiceUrl, expiredAt, err
+	})
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
 
-	statusParticipant := "pending"
-	earnedPoint := int64(utils.CalculateUserRedeemPoint(trnm.BookingPrice))
-	// check if exist
-	if participant.Id > 0 && participant.Status != "active" {
-		//update status
-		err = m.UpdateTournamentParticipant(tx, ctx, trnm.TournamentId, int64(user.ID), false, participant.Position, statusParticipant, participant.AdditionalInfo.String, earnedPoint, transactionCode)
-		if err != nil {
-			h.SendBadRequest(w, err.Error())
-			return
-		}
-	} else {
-		//add participant
-		err = m.InsertOneTournamentParticipant(tx, ctx, trnm.TournamentId, int64(user.ID), false, 0, statusParticipant, participant.AdditionalInfo.String, earnedPoint, transactionCode)
-		if err != nil {
-			h.SendBadRequest(w, err.Error())
-			return
-		}
-	}
-
 	h.SendSuccess(w, response.BookingRes{
 		InvoiceUrl: invoiceUrl,
 		ExpiredAt:  expiredAt.Local().Format(utils.DATE_TIME_FORMAT),
@@ -918,6 +919,11 @@ type tournamentParams struct {
 }
 
 func isTournamentClosed(w http.ResponseWriter, h *Contract, params tournamentParams) bool {
+	if params.TournamentStatus == utils.TournamentStatus["CANCELLED"] {
+		h.SendBadRequest(w, utils.ErrEventCancelled)
+		return true
+	}
+
 	if params.TournamentStatus != "closed" {
 		return false
 	}
@@ -104,7 +104,7 @@ func (h *Contract) AddTournamentBadgeAct(w http.ResponseWriter, r *http.Request)
 		badgeCode := utils.GeneratePrefixCode(utils.BadgePrefix)
 
 		// Add Badge
-		badgeID, err = m.AddBadge(tx, ctx, badgeCode, v.BadgeCategory, v.Name, v.ImageURL, v.VPPoint, v.Status, v.Description, parentCode)
+		badgeID, err = m.AddBadge(tx, ctx, badgeCode, v.BadgeCategory, v.Name, v.ImageURL, v.VPPoint, v.Status, v.Description, parentCode, utils.BadgeRuleOperatorAll)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
@@ -180,7 +180,7 @@ func (h *Contract) UpdateTournamentBadgeAct(w http.ResponseWriter, r *http.Reque
 		}
 
 		// Update Badge
-		err = m.UpdateBadgeByCode(tx, ctx, v.BadgeCategory, v.Name, v.Description, v.ImageURL, v.Status, v.VPPoint, v.BadgeCode)
+		err = m.UpdateBadgeByCode(tx, ctx, v.BadgeCategory, v.Name, v.Description, v.ImageURL, v.Status, v.VPPoint, utils.BadgeRuleOperatorAll, v.BadgeCode)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
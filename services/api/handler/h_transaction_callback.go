@@ -12,6 +12,7 @@ import (
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -23,6 +24,7 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 		req            = request.InvoiceCallbackRequest{}
 		xPlayer        string
 		bannerImageUri string
+		waitlistOffers []model.WaitlistEnt
 	)
 
 	if err = h.Bind(r, &req); err != nil {
@@ -83,12 +85,24 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 	switch trx.DataSource {
 	case utils.UserPointType["ROOM_TYPE"]:
 		// execute update status participant room
-		participant, err := m.GetParticipantByRoomCodeAndUserCode(h.DB, ctx, trx.SourceCode, trx.UserCode)
+		var participant model.RoomParticipantResp
+		participant, err = m.GetParticipantByRoomCodeAndUserCode(h.DB, ctx, trx.SourceCode, trx.UserCode)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
 		}
 
+		var cancelled bool
+		cancelled, err = refundIfCancelled(tx, ctx, m, req.Status, utils.WaitlistRoom, participant.RoomId, participant.UserId, trx)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if cancelled {
+			h.SendSuccess(w, nil, nil)
+			return
+		}
+
 		//update status participant
 		err = m.UpdateRoomParticipant(tx, ctx, participant.RoomId, participant.UserId, participant.StatusWinner, participant.Position, statusParticipant, participant.AdditionalInfo.String, participant.RewardPoint.Int64, participant.TransactionCode.String)
 		if err != nil {
@@ -96,6 +110,15 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// The seat of an unpaid booking goes to the waitlist
+		if statusParticipant == "cancel" {
+			waitlistOffers, err = m.OfferWaitlistSeatsTrx(tx, ctx, utils.WaitlistRoom, participant.RoomId)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+
 		if req.Status == "PAID" {
 			// Add user point from price
 			earnedPoint := utils.CalculateUserRedeemPoint(trx.Price)
@@ -105,27 +128,27 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			// Add user point from vp point participation
-			err = m.AddUserPoint(tx, ctx, participant.UserId, trx.DataSource, trx.SourceCode, participant.ParticipationPoint)
-			if err != nil {
-				h.SendBadRequest(w, err.Error())
-				return
+			// Add user point from vp point participation, unless it is awarded on check-in
+			if !h.Config.GetBool("attendance.award_point_on_check_in") {
+				err = m.AddUserPoint(tx, ctx, participant.UserId, trx.DataSource, trx.SourceCode, participant.ParticipationPoint)
+				if err != nil {
+					h.SendBadRequest(w, err.Error())
+					return
+				}
 			}
 		}
 
 		// Publisher badge
-		queueData := rabbit.QueueDataPayload(
+		err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
 			rabbit.QueueUserBadge,
 			rabbit.QueueUserBadgeReq(
 				utils.TimeLimit,
 				trx.UserId,
 			),
-		)
-
-		queueHost := m.Config.GetString("queue.rabbitmq.host")
-		err = rabbit.PublishQueue(ctx, queueHost, queueData)
+		))
 		if err != nil {
-			log.Printf("Error : %s", err)
+			h.SendBadRequest(w, err.Error())
+			return
 		}
 
 		xPlayer = participant.UserXPlayer
@@ -134,20 +157,36 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 	case utils.UserPointType["TOURNAMENT_TYPE"]:
 		// execute update status participant tournament
 
+		var (
+			trnm        model.TournamentsEnt
+			participant model.TournamentParticipantRespEnt
+		)
+
 		// Get tournament by code
-		trnm, err := m.GetTournamentByCode(h.DB, ctx, trx.SourceCode)
+		trnm, err = m.GetTournamentByCode(h.DB, ctx, trx.SourceCode)
 		if err != nil {
 			h.SendBadRequest(w, err.Error())
 			return
 		}
 
 		// Check if already booked
-		participant, err := m.GetParticipantByTournamentCodeAndUserCode(h.DB, ctx, trnm.TournamentCode, trx.UserCode)
+		participant, err = m.GetParticipantByTournamentCodeAndUserCode(h.DB, ctx, trnm.TournamentCode, trx.UserCode)
 		if err != nil && err.Error() != utils.EmptyData {
 			h.SendBadRequest(w, err.Error())
 			return
 		}
 
+		var cancelled bool
+		cancelled, err = refundIfCancelled(tx, ctx, m, req.Status, utils.WaitlistTournament, trnm.TournamentId, trx.UserId, trx)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if cancelled {
+			h.SendSuccess(w, nil, nil)
+			return
+		}
+
 		//update status participant
 		err = m.UpdateTournamentParticipant(tx, ctx, participant.TournamentId, participant.UserId, participant.StatusWinner, participant.Position, statusParticipant, participant.AdditionalInfo.String, participant.RewardPoint.Int64, participant.TransactionCode.String)
 		if err != nil {
@@ -155,6 +194,15 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// The seat of an unpaid booking goes to the waitlist
+		if statusParticipant == "cancel" {
+			waitlistOffers, err = m.OfferWaitlistSeatsTrx(tx, ctx, utils.WaitlistTournament, participant.TournamentId)
+			if err != nil {
+				h.SendBadRequest(w, err.Error())
+				return
+			}
+		}
+
 		if req.Status == "PAID" {
 			// Add user point
 			earnedPoint := utils.CalculateUserRedeemPoint(trx.Price)
@@ -174,42 +222,61 @@ func (h *Contract) TransactionCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Publisher badge total spent on status PAID
 	if req.Status == "PAID" {
-		queueData := rabbit.QueueDataPayload(
+		err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
 			rabbit.QueueUserBadge,
 			rabbit.QueueUserBadgeReq(
 				utils.TotalSpend,
 				trx.UserId,
 			),
-		)
-
-		queueHost := m.Config.GetString("queue.rabbitmq.host")
-		err = rabbit.PublishQueue(ctx, queueHost, queueData)
+		))
 		if err != nil {
-			log.Printf("Error : %s", err)
+			h.SendBadRequest(w, err.Error())
+			return
 		}
 	}
 
 	// Publisher badge
-	queueData := rabbit.QueueDataPayload(
+	err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
 		rabbit.QueueUserBadge,
 		rabbit.QueueUserBadgeReq(
 			utils.SpesificBoardGameCategory,
 			trx.UserId,
 		),
-	)
-
-	queueHost := m.Config.GetString("queue.rabbitmq.host")
-	err = rabbit.PublishQueue(ctx, queueHost, queueData)
+	))
 	if err != nil {
-		log.Printf("Error : %s", err)
+		h.SendBadRequest(w, err.Error())
+		return
 	}
 
 	// Notification Handler
 	sendNotification(h.DB, ctx, m, req.Status, trx, xPlayer, bannerImageUri)
+	m.PushWaitlistOffers(waitlistOffers)
 
 	h.SendSuccess(w, nil, nil)
 }
 
+// refundIfCancelled locks the booking paid by a transaction and, when its
+// event was cancelled before the payment arrived, records the refund of the
+// payment instead of confirming the booking. It reports whether the event was
+// cancelled, in which case the booking is left cancelled and no VP is awarded.
+func refundIfCancelled(tx pgx.Tx, ctx context.Context, m model.Contract, paymentStatus, eventType string, eventId, userId int64, trx model.OriginUserTransactionEnt) (bool, error) {
+	eventStatus, err := m.LockBookingTrx(tx, ctx, eventType, eventId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	if paymentStatus != "PAID" || eventStatus != utils.RoomStatus["CANCELLED"] {
+		return false, nil
+	}
+
+	err = m.RefundCancelledBookingTrx(tx, ctx, eventType, eventId, userId, trx.TransactionCode, int64(trx.Price))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // Send Notification via email & PN - private function
 func sendNotification(db *pgxpool.Pool, ctx context.Context, m model.Contract, paymentStatus string, data model.OriginUserTransactionEnt, xPlayer string, bannerImageUri string) {
 	// email := mail.New(m.App)
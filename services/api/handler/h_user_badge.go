@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"dots-api/lib/badge"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
 	"dots-api/services/api/request"
@@ -50,6 +51,8 @@ func (h *Contract) GetUserBadgeListAct(w http.ResponseWriter, r *http.Request) {
 			CreatedDate:   v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
 			IsBadgeOwned:  v.IsBadgeOwned.Bool,
 			NeedToClaim:   v.NeedToClaim.Bool,
+			ExpiredDate:   formatNullTime(v.ExpiredDate),
+			IsArchived:    v.IsArchived,
 		})
 	}
 
@@ -87,6 +90,8 @@ func (h *Contract) GetUserBadgeByBadgeCodeAct(w http.ResponseWriter, r *http.Req
 		CreatedDate:   v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
 		IsBadgeOwned:  v.IsBadgeOwned.Bool,
 		NeedToClaim:   v.NeedToClaim.Bool,
+		ExpiredDate:   formatNullTime(v.ExpiredDate),
+		IsArchived:    v.IsArchived,
 	}
 
 	h.SendSuccess(w, res, nil)
@@ -129,14 +134,43 @@ func (h *Contract) UpdateUserBadgeByBadgeCodeAct(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// A level is void once the user owns a higher level of its series
+	if badges.SeriesLevel.Valid {
+		highestLevel, err := m.GetHighestSeriesLevel(h.DB, ctx, userId, badges.ParentCode.String)
+		if err != nil {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if badges.SeriesLevel.Int64 < highestLevel {
+			tx.Rollback(ctx)
+			h.SendBadRequest(w, utils.ErrBadgeSeriesLevelSuperseded)
+			return
+		}
+	}
+
 	err = m.UpdateUserBadge(tx, ctx, int64(userId), badges.Id, req.IsClaim)
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
 	}
 
+	// A series level only grants the VP on top of the lower levels already claimed
+	vpPoint := badges.VPPoint
+	if badges.SeriesLevel.Valid {
+		claimedPoint, err := m.GetClaimedSeriesPoint(h.DB, ctx, userId, badges.ParentCode.String, badges.SeriesLevel.Int64)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		vpPoint -= claimedPoint
+		if vpPoint < 0 {
+			vpPoint = 0
+		}
+	}
+
 	// Add user point for all the winners
-	err = m.AddUserPoint(tx, ctx, userId, utils.Badge, badgeCode, int(badges.VPPoint))
+	err = m.AddUserPoint(tx, ctx, userId, utils.Badge, badgeCode, int(vpPoint))
 	if err != nil {
 		h.SendBadRequest(w, err.Error())
 		return
@@ -152,3 +186,164 @@ func (h *Contract) UpdateUserBadgeByBadgeCodeAct(w http.ResponseWriter, r *http.
 
 	h.SendSuccess(w, nil, nil)
 }
+
+// GetUserBadgeProgressAct returns the progress of a user towards every active badge.
+func (h *Contract) GetUserBadgeProgressAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		src  = m.NewBadgeSource(h.DB)
+		res  = make([]response.UserBadgeProgressRes, 0)
+		code = chi.URLParam(r, "code")
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	data, err := m.GetUserBadgeProgressList(h.DB, ctx, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range data {
+		badgeRules, err := m.GetBadgeRuleList(h.DB, ctx, v.Id)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if len(badgeRules) == 0 {
+			continue
+		}
+
+		progress, percentage, err := badge.MeasureAll(ctx, src.Within(v.AvailableStartDate, v.AvailableEndDate), userId, v.RuleOperator, model.BadgeRules(badgeRules))
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if v.IsOwned {
+			percentage = 100
+		}
+
+		rules := make([]response.BadgeRuleProgressRes, 0, len(badgeRules))
+		for i, rule := range badgeRules {
+			rules = append(rules, response.BadgeRuleProgressRes{
+				KeyCondition: rule.KeyCondition,
+				Value:        rule.Value,
+				Current:      progress[i].Current,
+				Target:       progress[i].Target,
+				Percentage:   progress[i].Percentage(),
+			})
+		}
+
+		res = append(res, response.UserBadgeProgressRes{
+			BadgeCode:     v.BadgeCode,
+			BadgeName:     v.Name,
+			BadgeImageURL: v.ImageURL,
+			BadgeCategory: v.BadgeCategory,
+			Description:   v.Description.String,
+			VPPoint:       v.VPPoint,
+			RuleOperator:  v.RuleOperator,
+			IsBadgeOwned:  v.IsOwned,
+			Percentage:    percentage,
+			Rules:         rules,
+		})
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// GetUserBadgeSeriesAct returns the progression of a user through every active badge series.
+func (h *Contract) GetUserBadgeSeriesAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		src  = m.NewBadgeSource(h.DB)
+		res  = make([]response.UserBadgeSeriesRes, 0)
+		code = chi.URLParam(r, "code")
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	series, err := m.GetBadgeSeriesList(h.DB, ctx, true)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range series {
+		levels, err := m.GetBadgeSeriesLevels(h.DB, ctx, v.SeriesCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+
+		ownedIds, err := m.GetUserBadgeIdsBySeries(h.DB, ctx, userId, v.SeriesCode)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		owned := make(map[int64]bool, len(ownedIds))
+		for _, id := range ownedIds {
+			owned[id] = true
+		}
+
+		seriesRes := response.UserBadgeSeriesRes{
+			SeriesCode:  v.SeriesCode,
+			Name:        v.Name,
+			Description: v.Description.String,
+			ImageURL:    v.ImageURL,
+			TotalLevel:  len(levels),
+			Levels:      make([]response.UserBadgeSeriesLevelRes, 0, len(levels)),
+		}
+
+		for _, level := range levels {
+			if owned[level.Id] && level.SeriesLevel.Int64 > seriesRes.CurrentLevel {
+				seriesRes.CurrentLevel = level.SeriesLevel.Int64
+			}
+		}
+
+		for _, level := range levels {
+			levelRes := response.UserBadgeSeriesLevelRes{
+				Level:         level.SeriesLevel.Int64,
+				BadgeCode:     level.BadgeCode,
+				BadgeName:     level.Name,
+				BadgeImageURL: level.ImageURL,
+				VPPoint:       level.VPPoint,
+				IsBadgeOwned:  owned[level.Id],
+			}
+
+			// Levels up to the current one are complete
+			if level.SeriesLevel.Int64 <= seriesRes.CurrentLevel {
+				levelRes.Percentage = 100
+			} else {
+				badgeRules, err := m.GetBadgeRuleList(h.DB, ctx, level.Id)
+				if err != nil {
+					h.SendBadRequest(w, err.Error())
+					return
+				}
+
+				_, levelRes.Percentage, err = badge.MeasureAll(ctx, src.Within(level.AvailableStartDate, level.AvailableEndDate), userId, level.RuleOperator, model.BadgeRules(badgeRules))
+				if err != nil {
+					h.SendBadRequest(w, err.Error())
+					return
+				}
+			}
+
+			seriesRes.Levels = append(seriesRes.Levels, levelRes)
+		}
+
+		res = append(res, seriesRes)
+	}
+
+	h.SendSuccess(w, res, nil)
+}
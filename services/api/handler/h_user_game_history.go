@@ -42,6 +42,11 @@ func (h *Contract) GetUserGameHistoryAct(w http.ResponseWriter, r *http.Request)
 			GameId:       v.GameId.Int64,
 			GameName:     v.GameName.String,
 			GameImageUrl: v.GameImageUrl.String,
+			EventCode:    v.EventCode.String,
+			Score:        v.Score.Int64,
+			Rank:         v.Rank.Int64,
+			IsTied:       v.IsTied,
+			Stats:        v.Stats,
 		})
 	}
 
@@ -0,0 +1,536 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/bootstrap"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"dots-api/services/api/response"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v4"
+)
+
+// bookingEvent is a room or tournament members book seats for. Its start and
+// end are in WIB.
+type bookingEvent struct {
+	Id          int64
+	Code        string
+	Status      string
+	Seats       int
+	Taken       int
+	RewardPoint int
+	StartDate   time.Time
+	EndDate     time.Time
+}
+
+func (h *Contract) JoinRoomWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.joinWaitlist(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) JoinTournamentWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.joinWaitlist(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) LeaveRoomWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.leaveWaitlist(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) LeaveTournamentWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.leaveWaitlist(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) CancelRoomBookingAct(w http.ResponseWriter, r *http.Request) {
+	h.cancelBooking(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) CancelTournamentBookingAct(w http.ResponseWriter, r *http.Request) {
+	h.cancelBooking(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) RemoveRoomParticipantAct(w http.ResponseWriter, r *http.Request) {
+	h.removeParticipant(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) RemoveTournamentParticipantAct(w http.ResponseWriter, r *http.Request) {
+	h.removeParticipant(w, r, utils.WaitlistTournament)
+}
+
+func (h *Contract) GetRoomWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.getWaitlist(w, r, utils.WaitlistRoom)
+}
+
+func (h *Contract) GetTournamentWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	h.getWaitlist(w, r, utils.WaitlistTournament)
+}
+
+// GetUserWaitlistAct lists the waitlists a member is on with their position.
+func (h *Contract) GetUserWaitlistAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		res  = make([]response.WaitlistRes, 0)
+		code = chi.URLParam(r, "code")
+	)
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetWaitlistByUserId(h.DB, ctx, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		res = append(res, waitlistRes(v))
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+// joinWaitlist puts the member at the end of the waitlist of a fully booked event.
+func (h *Contract) joinWaitlist(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if event.Status != utils.RoomStatus["ACTIVE"] {
+		h.SendBadRequest(w, "Waitlist is only available for active events")
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if eventType == utils.WaitlistRoom {
+		access, err := m.GetRoomAccess(h.DB, ctx, event.Id, userId)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		if err = access.CanBook(); err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+	}
+
+	participant, err := m.GetEventParticipant(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if len(participant.Status) > 0 && participant.Status != "cancel" {
+		h.SendBadRequest(w, utils.ErrWaitlistAlreadyBooked)
+		return
+	}
+
+	held, err := m.CountHeldWaitlistSeats(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if event.Taken+held < event.Seats {
+		h.SendBadRequest(w, utils.ErrWaitlistSeatAvailable)
+		return
+	}
+
+	err = m.JoinWaitlist(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	entry, err := m.GetWaitlistEntry(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	h.SendSuccess(w, waitlistRes(entry), nil)
+}
+
+// leaveWaitlist takes the member off a waitlist. A seat they were offered
+// rolls to the next member right away.
+func (h *Contract) leaveWaitlist(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	offers, err := h.releaseSeat(ctx, m, eventType, event.Id, func(tx pgx.Tx) error {
+		left, err := m.UpdateWaitlistStatusTrx(tx, ctx, eventType, event.Id, userId, utils.WaitlistLeft)
+		if err != nil {
+			return err
+		}
+		if !left {
+			return errors.New(utils.ErrNotOnWaitlist)
+		}
+
+		return nil
+	})
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	m.PushWaitlistOffers(offers)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// cancelBooking cancels the unpaid booking of the member and offers the seat
+// to the waitlist. Its invoice is expired once the booking is cancelled.
+func (h *Contract) cancelBooking(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = bootstrap.GetIdentifierCodeFromToken(ctx, r)
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	participant, err := m.GetEventParticipant(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if participant.Status != "pending" {
+		h.SendBadRequest(w, utils.ErrNoPendingBooking)
+		return
+	}
+
+	order, err := m.GetTransactionByCode(h.DB, ctx, userCode, participant.TransactionCode.String)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	offers, err := h.releaseSeat(ctx, m, eventType, event.Id, func(tx pgx.Tx) error {
+		return m.CancelEventParticipantTrx(tx, ctx, eventType, event.Id, userId)
+	})
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	expireInvoice(m, order.AggregatorCode)
+	m.PushWaitlistOffers(offers)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// removeParticipant removes a member from an event from the CMS and offers
+// their seat to the waitlist. An unpaid invoice of the member is expired. Paid
+// bookings are refused, they are only refunded by cancelling the event.
+func (h *Contract) removeParticipant(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err      error
+		ctx      = context.TODO()
+		m        = model.Contract{App: h.App}
+		code     = chi.URLParam(r, "code")
+		userCode = chi.URLParam(r, "user_code")
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	if event.Status == utils.RoomStatus["CLOSED"] {
+		h.SendBadRequest(w, "Modifications are not allowed on closed events")
+		return
+	}
+	if event.Status == utils.RoomStatus["CANCELLED"] {
+		h.SendBadRequest(w, utils.ErrEventCancelled)
+		return
+	}
+
+	userId, err := m.GetUserIdByUserCode(h.DB, ctx, userCode)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	participant, err := m.GetEventParticipant(h.DB, ctx, eventType, event.Id, userId)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if len(participant.Status) == 0 {
+		h.SendBadRequest(w, utils.EmptyData)
+		return
+	}
+	if participant.Status == "active" {
+		h.SendBadRequest(w, utils.ErrPaidBookingNotRemovable)
+		return
+	}
+
+	var aggregatorCode string
+	if participant.Status == "pending" {
+		order, err := m.GetTransactionByCode(h.DB, ctx, userCode, participant.TransactionCode.String)
+		if err != nil {
+			h.SendBadRequest(w, err.Error())
+			return
+		}
+		aggregatorCode = order.AggregatorCode
+	}
+
+	offers, err := h.releaseSeat(ctx, m, eventType, event.Id, func(tx pgx.Tx) error {
+		// The booking may have been paid since it was read
+		status, err := m.GetEventParticipantStatusTrx(tx, ctx, eventType, event.Id, userId)
+		if err != nil {
+			return err
+		}
+		if status == "active" {
+			return errors.New(utils.ErrPaidBookingNotRemovable)
+		}
+
+		if eventType == utils.WaitlistTournament {
+			return m.DeleteTournamentParticipant(tx, ctx, event.Id, userId)
+		}
+		return m.DeleteRoomParticipant(tx, ctx, event.Id, userId)
+	})
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+	if len(aggregatorCode) > 0 {
+		expireInvoice(m, aggregatorCode)
+	}
+	m.PushWaitlistOffers(offers)
+
+	h.SendSuccess(w, nil, nil)
+}
+
+// expireInvoice expires the invoice of a cancelled booking so it can no longer
+// be paid. The booking is already cancelled, so a failure is only logged and
+// the invoice is left to expire on its own.
+func expireInvoice(m model.Contract, aggregatorCode string) {
+	if err := m.ExpireInvoice(aggregatorCode); err != nil {
+		log.Printf("Error : %s", err)
+	}
+}
+
+// invoiceSeat creates the invoice of a seat held with model.HoldSeat, keeps
+// the seat until the invoice expires and closes the waitlist offer of the
+// member. create returns the transaction code, the invoice URL and the expiry
+// of the invoice. When it fails, the hold is released and the seat offered to
+// the waitlist.
+func (h *Contract) invoiceSeat(ctx context.Context, m model.Contract, eventType string, eventId, userId int64, create func(tx pgx.Tx) (string, string, time.Time, error)) (string, time.Time, error) {
+	invoiceUrl, expiredAt, err := h.confirmSeat(ctx, m, eventType, eventId, userId, create)
+	if err == nil {
+		return invoiceUrl, expiredAt, nil
+	}
+
+	offers, releaseErr := h.releaseSeat(ctx, m, eventType, eventId, func(tx pgx.Tx) error {
+		return m.CancelEventParticipantTrx(tx, ctx, eventType, eventId, userId)
+	})
+	if releaseErr != nil {
+		// The hold still expires through the release-seat-holds job
+		log.Printf("Error : %s", releaseErr)
+	}
+	m.PushWaitlistOffers(offers)
+
+	return "", time.Time{}, err
+}
+
+func (h *Contract) confirmSeat(ctx context.Context, m model.Contract, eventType string, eventId, userId int64, create func(tx pgx.Tx) (string, string, time.Time, error)) (string, time.Time, error) {
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	transactionCode, invoiceUrl, expiredAt, err := create(tx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Keep the seat of the unpaid booking until its invoice expires
+	if err = m.ConfirmSeatHoldTrx(tx, ctx, eventType, eventId, userId, transactionCode, expiredAt); err != nil {
+		return "", time.Time{}, err
+	}
+
+	// Close the offer of a member booking from the waitlist
+	if _, err = m.UpdateWaitlistStatusTrx(tx, ctx, eventType, eventId, userId, utils.WaitlistBooked); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return invoiceUrl, expiredAt, nil
+}
+
+// releaseSeat frees a seat of an event with release and offers it to the
+// waitlist in the same transaction.
+func (h *Contract) releaseSeat(ctx context.Context, m model.Contract, eventType string, eventId int64, release func(tx pgx.Tx) error) ([]model.WaitlistEnt, error) {
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = release(tx); err != nil {
+		return nil, err
+	}
+
+	offers, err := m.OfferWaitlistSeatsTrx(tx, ctx, eventType, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// getWaitlist lists the members waiting for a seat of an event for the CMS.
+func (h *Contract) getWaitlist(w http.ResponseWriter, r *http.Request, eventType string) {
+	var (
+		err  error
+		ctx  = context.TODO()
+		m    = model.Contract{App: h.App}
+		res  = make([]response.WaitlistRes, 0)
+		code = chi.URLParam(r, "code")
+	)
+
+	event, err := h.getBookingEvent(ctx, m, eventType, code)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	list, err := m.GetWaitlistByEvent(h.DB, ctx, eventType, event.Id)
+	if err != nil {
+		h.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		res = append(res, waitlistRes(v))
+	}
+
+	h.SendSuccess(w, res, nil)
+}
+
+func (h *Contract) getBookingEvent(ctx context.Context, m model.Contract, eventType, code string) (bookingEvent, error) {
+	if eventType == utils.WaitlistTournament {
+		trnm, err := m.GetTournamentByCode(h.DB, ctx, code)
+		if err != nil {
+			return bookingEvent{}, err
+		}
+
+		taken, err := m.CountParticipantTournamentByTournamentId(h.DB, ctx, trnm.TournamentId)
+		if err != nil {
+			return bookingEvent{}, err
+		}
+
+		return bookingEvent{
+			Id:        trnm.TournamentId,
+			Code:      trnm.TournamentCode,
+			Status:    trnm.Status,
+			Seats:     int(trnm.PlayerSlot),
+			Taken:     taken,
+			StartDate: eventTime(trnm.StartDate, trnm.StartTime),
+			EndDate:   eventTime(trnm.EndDate, trnm.EndTime),
+		}, nil
+	}
+
+	room, err := m.GetRoomByCode(h.DB, ctx, code)
+	if err != nil {
+		return bookingEvent{}, err
+	}
+
+	taken, err := m.CountParticipantRoomByRoomId(h.DB, ctx, room.RoomId)
+	if err != nil {
+		return bookingEvent{}, err
+	}
+
+	return bookingEvent{
+		Id:          room.RoomId,
+		Code:        room.RoomCode,
+		Status:      room.Status,
+		Seats:       room.MaximumParticipant,
+		Taken:       taken,
+		RewardPoint: room.RewardPoint,
+		StartDate:   eventTime(room.StartDate, room.StartTime),
+		EndDate:     eventTime(room.EndDate, room.EndTime),
+	}, nil
+}
+
+// eventTime combines the date and time of an event, scheduled in WIB.
+func eventTime(date sql.NullTime, clock time.Time) time.Time {
+	return time.Date(date.Time.Year(), date.Time.Month(), date.Time.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, utils.GetTimeLocationWIB())
+}
+
+func waitlistRes(v model.WaitlistEnt) response.WaitlistRes {
+	res := response.WaitlistRes{
+		EventType:     v.EventType,
+		EventCode:     v.EventCode,
+		EventName:     v.EventName,
+		EventImageUrl: v.EventImageUrl,
+		UserCode:      v.UserCode,
+		UserName:      v.UserName,
+		Status:        v.Status,
+		Position:      v.Position,
+		CreatedDate:   v.CreatedDate.Format(utils.DATE_TIME_FORMAT),
+	}
+	if v.OfferExpiredDate.Valid {
+		res.OfferExpiredDate = v.OfferExpiredDate.Time.Format(utils.DATE_TIME_FORMAT)
+	}
+
+	return res
+}
@@ -16,20 +16,22 @@ import (
 )
 
 type AdminEnt struct {
-	ID          int          `db:"id"`
-	AdminCode   string       `db:"admin_code"`
-	Email       string       `db:"email"`
-	Name        string       `db:"name"`
-	UserName    string       `db:"username"`
-	PhoneNumber string       `db:"phone_number"`
-	Password    string       `db:"password"`
-	Status      string       `db:"status"`
-	ImageURL    string       `db:"image_url"`
-	RoleId      int          `db:"role_id"`
-	Role        string       `db:"role"`
-	CreatedDate time.Time    `db:"created_date"`
-	UpdatedDate sql.NullTime `db:"updated_date"`
-	DeletedDate sql.NullTime `db:"deleted_date"`
+	ID          int           `db:"id"`
+	AdminCode   string        `db:"admin_code"`
+	Email       string        `db:"email"`
+	Name        string        `db:"name"`
+	UserName    string        `db:"username"`
+	PhoneNumber string        `db:"phone_number"`
+	Password    string        `db:"password"`
+	Status      string        `db:"status"`
+	ImageURL    string        `db:"image_url"`
+	RoleId      int           `db:"role_id"`
+	Role        string        `db:"role"`
+	UserId      sql.NullInt64 `db:"user_id"`
+	UserCode    string        `db:"user_code"`
+	CreatedDate time.Time     `db:"created_date"`
+	UpdatedDate sql.NullTime  `db:"updated_date"`
+	DeletedDate sql.NullTime  `db:"deleted_date"`
 }
 
 func (c *Contract) GetAdminList(db *pgxpool.Pool, ctx context.Context, param request.AdminParam) ([]AdminEnt, request.AdminParam, error) {
@@ -165,12 +167,13 @@ func (c *Contract) GetAdminByCode(db *pgxpool.Pool, ctx context.Context, adminCo
 	var (
 		err  error
 		data AdminEnt
-		sql  = `SELECT a.admin_code, a.email, a.name, a.username, a.password, a.status, r.name as role, a.phone_number, a.image_url
+		sql  = `SELECT a.admin_code, a.email, a.name, a.username, a.password, a.status, r.name as role, a.phone_number, a.image_url, a.user_id, COALESCE(u.user_code, '')
 		FROM admins a left join roles r on r.id = a.role_id
+		left join users u on u.id = a.user_id
 		WHERE a.admin_code = $1`
 	)
 
-	err = db.QueryRow(ctx, sql, adminCode).Scan(&data.AdminCode, &data.Email, &data.Name, &data.UserName, &data.Password, &data.Status, &data.Role, &data.PhoneNumber, &data.ImageURL)
+	err = db.QueryRow(ctx, sql, adminCode).Scan(&data.AdminCode, &data.Email, &data.Name, &data.UserName, &data.Password, &data.Status, &data.Role, &data.PhoneNumber, &data.ImageURL, &data.UserId, &data.UserCode)
 	if err != nil {
 		return data, c.errHandler("model.GetAdminByCode", err, utils.ErrGettingAdminByCode)
 	}
@@ -214,7 +217,7 @@ func (c *Contract) GetAdminByPhoneNumber(db *pgxpool.Pool, ctx context.Context,
 	return data, nil
 }
 
-func (c *Contract) AddAdmin(db *pgxpool.Pool, ctx context.Context, adminCode, email, name, userName, password, status, role, phoneNumber, imageURL string) error {
+func (c *Contract) AddAdmin(db *pgxpool.Pool, ctx context.Context, adminCode, email, name, userName, password, status, role, phoneNumber, imageURL string, userId sql.NullInt64) error {
 	var (
 		err    error
 		roleId int
@@ -227,8 +230,8 @@ func (c *Contract) AddAdmin(db *pgxpool.Pool, ctx context.Context, adminCode, em
 	}
 
 	// Insert data to database
-	sql := `INSERT INTO admins(admin_code, email, name, username, password, status, phone_number, image_url, role_id, created_date)
-	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`
+	sql := `INSERT INTO admins(admin_code, email, name, username, password, status, phone_number, image_url, role_id, created_date, user_id)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`
 
 	if role == "admin" {
 		roleId = utils.RoleAdminId
@@ -236,7 +239,7 @@ func (c *Contract) AddAdmin(db *pgxpool.Pool, ctx context.Context, adminCode, em
 		roleId = utils.RoleCashierId
 	}
 
-	_, err = db.Exec(ctx, sql, adminCode, email, name, userName, hashedPassword, status, phoneNumber, imageURL, roleId, time.Now().In(time.UTC))
+	_, err = db.Exec(ctx, sql, adminCode, email, name, userName, hashedPassword, status, phoneNumber, imageURL, roleId, time.Now().In(time.UTC), userId)
 	if err != nil {
 		return c.errHandler("model.AddAdmin", err, utils.ErrAddingAdmin)
 	}
@@ -244,13 +247,13 @@ func (c *Contract) AddAdmin(db *pgxpool.Pool, ctx context.Context, adminCode, em
 	return nil
 }
 
-func (c *Contract) UpdateAdminByCode(db *pgxpool.Pool, ctx context.Context, adminCode, email, name, userName, password, status, role, phoneNumber, imageURL string) error {
+func (c *Contract) UpdateAdminByCode(db *pgxpool.Pool, ctx context.Context, adminCode, email, name, userName, password, status, role, phoneNumber, imageURL string, userId sql.NullInt64) error {
 	var (
 		err    error
 		roleId int
 		sql    = `
 		UPDATE admins 
-		SET email=$1,name=$2,username=$3,password=$4,status=$5,phone_number=$6,image_url=$7,updated_date=$8,role_id=$9
+		SET email=$1,name=$2,username=$3,password=$4,status=$5,phone_number=$6,image_url=$7,updated_date=$8,role_id=$9,user_id=$11
 		WHERE admin_code = $10`
 	)
 
@@ -297,6 +300,10 @@ func (c *Contract) UpdateAdminByCode(db *pgxpool.Pool, ctx context.Context, admi
 		status = adminExist.Status
 	}
 
+	if !userId.Valid {
+		userId = adminExist.UserId
+	}
+
 	roleId = adminExist.RoleId
 	if role == "admin" {
 		roleId = utils.RoleAdminId
@@ -304,7 +311,7 @@ func (c *Contract) UpdateAdminByCode(db *pgxpool.Pool, ctx context.Context, admi
 		roleId = utils.RoleCashierId
 	}
 
-	_, err = db.Exec(ctx, sql, email, name, userName, password, status, phoneNumber, imageURL, time.Now().In(time.UTC), roleId, adminCode)
+	_, err = db.Exec(ctx, sql, email, name, userName, password, status, phoneNumber, imageURL, time.Now().In(time.UTC), roleId, adminCode, userId)
 	if err != nil {
 		return c.errHandler("model.UpdateAdminByCode", err, utils.ErrUpdatingAdmin)
 	}
@@ -358,3 +365,20 @@ func (c *Contract) IsAdminUsernameExist(db *pgxpool.Pool, ctx context.Context, u
 
 	return isExist, nil
 }
+
+// GetUserIdByAdminCode returns the ID of the member account linked to the
+// admin, or 0 when the admin has no member account.
+func (c *Contract) GetUserIdByAdminCode(db *pgxpool.Pool, ctx context.Context, adminCode string) (int64, error) {
+	var (
+		err      error
+		userId   int64
+		sqlQuery = `SELECT COALESCE(user_id, 0) FROM admins WHERE admin_code = $1`
+	)
+
+	err = db.QueryRow(ctx, sqlQuery, adminCode).Scan(&userId)
+	if err != nil && err != pgx.ErrNoRows {
+		return 0, c.errHandler("model.GetUserIdByAdminCode", err, utils.ErrGettingUserByAdminCode)
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/qr"
+	"dots-api/lib/utils"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// AttendanceEnt is the attendance of a paid booking for a room or tournament.
+type AttendanceEnt struct {
+	EventType        string         `db:"event_type"`
+	EventCode        string         `db:"event_code"`
+	EventName        string         `db:"event_name"`
+	EventStartDate   sql.NullTime   `db:"event_start_date"`
+	UserCode         string         `db:"user_code"`
+	UserName         string         `db:"user_name"`
+	AttendanceStatus string         `db:"attendance_status"`
+	CheckedInDate    sql.NullTime   `db:"checked_in_date"`
+	CheckInMethod    sql.NullString `db:"check_in_method"`
+	CheckedInBy      sql.NullString `db:"checked_in_by"`
+}
+
+// attendanceQuery lists the paid bookings of one event type with their attendance.
+func attendanceQuery(eventType string, table waitlistTable) string {
+	return `
+		SELECT
+			'` + eventType + `' AS event_type, e.` + table.code + ` AS event_code, COALESCE(e.name, '') AS event_name, e.start_date,
+			u.user_code, COALESCE(u.username, '') AS user_name,
+			p.attendance_status, p.checked_in_date, p.check_in_method, p.checked_in_by
+		FROM ` + table.participants + ` p
+			JOIN ` + table.event + ` e ON e.id = p.` + table.participantEvent + `
+			JOIN users u ON u.id = p.user_id
+		WHERE p.status = 'active'`
+}
+
+func (c *Contract) scanAttendance(rows pgx.Rows, funcName string) ([]AttendanceEnt, error) {
+	var list []AttendanceEnt
+
+	defer rows.Close()
+	for rows.Next() {
+		var data AttendanceEnt
+		err := rows.Scan(
+			&data.EventType, &data.EventCode, &data.EventName, &data.EventStartDate,
+			&data.UserCode, &data.UserName,
+			&data.AttendanceStatus, &data.CheckedInDate, &data.CheckInMethod, &data.CheckedInBy,
+		)
+		if err != nil {
+			return list, c.errHandler(funcName, err, utils.ErrScanningAttendance)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// GetEventAttendance returns the attendance of the paid bookings of an event.
+func (c *Contract) GetEventAttendance(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) ([]AttendanceEnt, error) {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := attendanceQuery(eventType, table) + ` AND p.` + table.participantEvent + ` = $1 ORDER BY p.checked_in_date NULLS LAST, u.username`
+	rows, err := db.Query(ctx, query, eventId)
+	if err != nil {
+		return nil, c.errHandler("model.GetEventAttendance", err, utils.ErrGettingAttendance)
+	}
+
+	return c.scanAttendance(rows, "model.GetEventAttendance")
+}
+
+// GetUserAttendance returns the attendance of a member for every room and
+// tournament they paid for, latest event first.
+func (c *Contract) GetUserAttendance(db *pgxpool.Pool, ctx context.Context, userId int64) ([]AttendanceEnt, error) {
+	query := attendanceQuery(utils.WaitlistRoom, waitlistTables[utils.WaitlistRoom]) + ` AND p.user_id = $1
+		UNION ALL` + attendanceQuery(utils.WaitlistTournament, waitlistTables[utils.WaitlistTournament]) + ` AND p.user_id = $1
+		ORDER BY start_date DESC`
+	rows, err := db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, c.errHandler("model.GetUserAttendance", err, utils.ErrGettingAttendance)
+	}
+
+	return c.scanAttendance(rows, "model.GetUserAttendance")
+}
+
+// GetCheckInToken returns the secret of the check-in QR code of an event and
+// creates it the first time.
+func (c *Contract) GetCheckInToken(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) (string, error) {
+	var (
+		table, ok = waitlistTables[eventType]
+		token     string
+	)
+	if !ok {
+		return "", fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	newToken, _ := utils.Generate(`[a-zA-Z0-9]{32}`)
+	query := `UPDATE ` + table.event + ` SET check_in_token = COALESCE(check_in_token, $1) WHERE id = $2 RETURNING check_in_token`
+	err := db.QueryRow(ctx, query, newToken, eventId).Scan(&token)
+	if err != nil {
+		return "", c.errHandler("model.GetCheckInToken", err, utils.ErrGettingCheckInToken)
+	}
+
+	return token, nil
+}
+
+// GetCheckInQRCode returns the check-in QR code of an event as a base64 image.
+// The image is generated once per token in the upload path.
+func (c *Contract) GetCheckInQRCode(ctx context.Context, eventType, eventCode, token string) (string, error) {
+	var (
+		uploadPath = c.Config.GetString("upload_path")
+		fileName   = fmt.Sprintf("%s_CHECK_IN_%s", eventCode, token)
+	)
+
+	qrString, err := utils.ImageToBase64(uploadPath + "/" + fileName + ".jpg")
+	if err == nil {
+		return qrString, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", c.errHandler("model.GetCheckInQRCode", err, utils.ErrGettingCheckInQrCode)
+	}
+
+	fileName, err = qr.GenerateQRCode(strings.Join([]string{utils.CheckInQRPrefix, eventType, eventCode, token}, ":"), fileName, uploadPath)
+	if err != nil {
+		return "", c.errHandler("model.GetCheckInQRCode", err, utils.ErrGettingCheckInQrCode)
+	}
+
+	qrString, err = utils.ImageToBase64(uploadPath + "/" + fileName)
+	if err != nil {
+		return "", c.errHandler("model.GetCheckInQRCode", err, utils.ErrGettingCheckInQrCode)
+	}
+
+	return qrString, nil
+}
+
+// CheckInEventParticipantTrx records that a member with a paid booking showed
+// up at an event. It reports false when there is no such booking or the
+// member already checked in, so a check-in is only counted once.
+func (c *Contract) CheckInEventParticipantTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64, method, checkedInBy string) (bool, error) {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return false, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `
+		UPDATE ` + table.participants + `
+		SET attendance_status = $1, checked_in_date = $2, check_in_method = $3, checked_in_by = NULLIF($4, ''), updated_date = $2
+		WHERE ` + table.participantEvent + ` = $5 AND user_id = $6 AND status = 'active' AND checked_in_date IS NULL`
+	tag, err := tx.Exec(ctx, query, utils.AttendanceAttended, time.Now().UTC(), method, checkedInBy, eventId, userId)
+	if err != nil {
+		return false, c.errHandler("model.CheckInEventParticipantTrx", err, utils.ErrCheckingIn)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// MarkNoShows flags the paid bookings that did not check in within
+// utils.NoShowGraceMinutes of the start of their event, which is scheduled in
+// WIB. A member flagged as a no-show can still check in late. It returns the
+// number of bookings flagged.
+func (c *Contract) MarkNoShows(db *pgxpool.Pool, ctx context.Context) (int, error) {
+	var (
+		now    = time.Now().UTC()
+		cutoff = now.Add(-time.Duration(utils.NoShowGraceMinutes) * time.Minute)
+		total  int64
+	)
+
+	for _, table := range waitlistTables {
+		query := `
+			UPDATE ` + table.participants + ` p SET attendance_status = $1, updated_date = $2
+			FROM ` + table.event + ` e
+			WHERE e.id = p.` + table.participantEvent + ` AND p.status = 'active' AND p.attendance_status = $3
+				AND (e.start_date + COALESCE(e.start_time, '00:00'::time)) AT TIME ZONE 'Asia/Jakarta' <= $4`
+		tag, err := db.Exec(ctx, query, utils.AttendanceNoShow, now, utils.AttendanceExpected, cutoff)
+		if err != nil {
+			return 0, c.errHandler("model.MarkNoShows", err, utils.ErrMarkingNoShows)
+		}
+		total += tag.RowsAffected()
+	}
+
+	return int(total), nil
+}
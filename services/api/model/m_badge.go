@@ -17,18 +17,44 @@ import (
 )
 
 type BadgeEnt struct {
-	Id            int64          `db:"id"`
-	BadgeCode     string         `db:"badge_code"`
-	BadgeCategory string         `db:"badge_category"`
-	Name          string         `db:"name"`
-	ImageURL      string         `db:"image_url"`
-	VPPoint       int64          `db:"vp_point"`
-	Status        string         `db:"status"`
-	Description   sql.NullString `db:"description"`
-	ParentCode    sql.NullString `db:"parent_code"`
-	CreatedDate   time.Time      `db:"created_date"`
-	UpdatedDate   sql.NullTime   `db:"updated_date"`
-	DeletedDate   sql.NullTime   `db:"deleted_date"`
+	Id                 int64          `db:"id"`
+	BadgeCode          string         `db:"badge_code"`
+	BadgeCategory      string         `db:"badge_category"`
+	Name               string         `db:"name"`
+	ImageURL           string         `db:"image_url"`
+	VPPoint            int64          `db:"vp_point"`
+	Status             string         `db:"status"`
+	Description        sql.NullString `db:"description"`
+	ParentCode         sql.NullString `db:"parent_code"`
+	RuleOperator       string         `db:"rule_operator"`
+	SeriesLevel        sql.NullInt64  `db:"series_level"`
+	AvailableStartDate sql.NullTime   `db:"available_start_date"`
+	AvailableEndDate   sql.NullTime   `db:"available_end_date"`
+	ExpiredDate        sql.NullTime   `db:"expired_date"`
+	CreatedDate        time.Time      `db:"created_date"`
+	UpdatedDate        sql.NullTime   `db:"updated_date"`
+	DeletedDate        sql.NullTime   `db:"deleted_date"`
+}
+
+// IsAvailable reports whether the badge can be earned at the given time.
+func (b BadgeEnt) IsAvailable(now time.Time) bool {
+	if b.AvailableStartDate.Valid && now.Before(b.AvailableStartDate.Time) {
+		return false
+	}
+	if b.AvailableEndDate.Valid && now.After(b.AvailableEndDate.Time) {
+		return false
+	}
+
+	return true
+}
+
+// badgeAvailableCondition filters badges that can be earned at the time bound
+// to the given placeholder.
+func badgeAvailableCondition(alias string, placeholder int) string {
+	return fmt.Sprintf(
+		"(%[1]s.available_start_date IS NULL OR %[1]s.available_start_date <= $%[2]d) AND (%[1]s.available_end_date IS NULL OR %[1]s.available_end_date >= $%[2]d)",
+		alias, placeholder,
+	)
 }
 
 // GetBadgeList retrieves a list of all badges from the database.
@@ -38,7 +64,7 @@ func (c *Contract) GetBadgeList(db *pgxpool.Pool, ctx context.Context, param req
 		list       []BadgeEnt
 		paramQuery []interface{}
 		totalData  int
-		query      = `SELECT id, badge_code, badge_category, description, vp_point, name, image_url, status, parent_code, created_date, updated_date, deleted_date FROM badges`
+		query      = `SELECT id, badge_code, badge_category, description, vp_point, name, image_url, status, parent_code, rule_operator, series_level, available_start_date, available_end_date, expired_date, created_date, updated_date, deleted_date FROM badges`
 	)
 
 	// Populate Search
@@ -81,7 +107,8 @@ func (c *Contract) GetBadgeList(db *pgxpool.Pool, ctx context.Context, param req
 		var badge BadgeEnt
 		err = rows.Scan(
 			&badge.Id, &badge.BadgeCode, &badge.BadgeCategory, &badge.Description, &badge.VPPoint, &badge.Name,
-			&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.CreatedDate,
+			&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.RuleOperator, &badge.SeriesLevel,
+			&badge.AvailableStartDate, &badge.AvailableEndDate, &badge.ExpiredDate, &badge.CreatedDate,
 			&badge.UpdatedDate, &badge.DeletedDate,
 		)
 		if err != nil {
@@ -101,7 +128,7 @@ func (c *Contract) GetUnownedBadgeListByUserCode(db *pgxpool.Pool, ctx context.C
 		where      []string
 		totalData  int
 		query      = `SELECT 
-			id, badge_code, badge_category, description, vp_point, name, image_url, status, parent_code, created_date, updated_date, deleted_date 
+			id, badge_code, badge_category, description, vp_point, name, image_url, status, parent_code, rule_operator, series_level, available_start_date, available_end_date, expired_date, created_date, updated_date, deleted_date 
 		FROM badges`
 	)
 
@@ -110,6 +137,10 @@ func (c *Contract) GetUnownedBadgeListByUserCode(db *pgxpool.Pool, ctx context.C
 	where = append(where, "status = 'active'")
 	where = append(where, "deleted_date IS NULL")
 
+	paramQuery = append(paramQuery, time.Now().UTC())
+	where = append(where, badgeAvailableCondition("badges", len(paramQuery)))
+	where = append(where, fmt.Sprintf("(expired_date IS NULL OR expired_date > $%d)", len(paramQuery)))
+
 	query += " WHERE " + strings.Join(where, " AND ")
 	{
 		newQcount := `SELECT COUNT(*) FROM ( ` + query + ` ) AS total`
@@ -140,7 +171,8 @@ func (c *Contract) GetUnownedBadgeListByUserCode(db *pgxpool.Pool, ctx context.C
 		var badge BadgeEnt
 		err = rows.Scan(
 			&badge.Id, &badge.BadgeCode, &badge.BadgeCategory, &badge.Description, &badge.VPPoint, &badge.Name,
-			&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.CreatedDate,
+			&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.RuleOperator, &badge.SeriesLevel,
+			&badge.AvailableStartDate, &badge.AvailableEndDate, &badge.ExpiredDate, &badge.CreatedDate,
 			&badge.UpdatedDate, &badge.DeletedDate,
 		)
 		if err != nil {
@@ -156,10 +188,11 @@ func (c *Contract) GetUnownedBadgeListByUserCode(db *pgxpool.Pool, ctx context.C
 func (c *Contract) GetBadgeDetailByCode(db *pgxpool.Pool, ctx context.Context, code string) (BadgeEnt, error) {
 	var badge BadgeEnt
 
-	query := `SELECT id, badge_code, badge_category,  vp_point, description, name, image_url, status, created_date, updated_date, deleted_date FROM badges WHERE badge_code = $1 AND deleted_date is null`
+	query := `SELECT id, badge_code, badge_category,  vp_point, description, name, image_url, status, parent_code, rule_operator, series_level, available_start_date, available_end_date, expired_date, created_date, updated_date, deleted_date FROM badges WHERE badge_code = $1 AND deleted_date is null`
 	err := db.QueryRow(ctx, query, code).Scan(
 		&badge.Id, &badge.BadgeCode, &badge.BadgeCategory, &badge.VPPoint, &badge.Description, &badge.Name,
-		&badge.ImageURL, &badge.Status, &badge.CreatedDate,
+		&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.RuleOperator, &badge.SeriesLevel,
+		&badge.AvailableStartDate, &badge.AvailableEndDate, &badge.ExpiredDate, &badge.CreatedDate,
 		&badge.UpdatedDate, &badge.DeletedDate,
 	)
 	if err != nil {
@@ -212,12 +245,12 @@ func (c *Contract) GetBadgeListByKeyCondition(db *pgxpool.Pool, ctx context.Cont
 	)
 
 	query := `
-	SELECT 
+	SELECT DISTINCT
 		b.badge_code 
 	FROM badges_rules br 
-	LEFT JOIN badges b ON b.id = br.badge_id AND b.badge_category != $2
-	WHERE br.key_condition = $1`
-	rows, err := db.Query(ctx, query, keyCondition, utils.BadgeCategoryGift.String())
+	JOIN badges b ON b.id = br.badge_id AND b.badge_category != $2
+	WHERE br.key_condition = $1 AND b.status = 'active' AND b.deleted_date IS NULL AND ` + badgeAvailableCondition("b", 3)
+	rows, err := db.Query(ctx, query, keyCondition, utils.BadgeCategoryGift.String(), time.Now().UTC())
 	if err != nil {
 		return list, c.errHandler("model.GetBadgeListByKeyCondition", err, utils.ErrGettingBadgeRuleList)
 	}
@@ -238,11 +271,11 @@ func (c *Contract) GetBadgeListByKeyCondition(db *pgxpool.Pool, ctx context.Cont
 }
 
 // AddBadge adds a new badge to the database within a transaction.
-func (c *Contract) AddBadge(tx pgx.Tx, ctx context.Context, badgeCode, badgeCategory, name, imageURL string, vpPoint int64, status, description, parentCode string) (int64, error) {
+func (c *Contract) AddBadge(tx pgx.Tx, ctx context.Context, badgeCode, badgeCategory, name, imageURL string, vpPoint int64, status, description, parentCode, ruleOperator string) (int64, error) {
 	var id int64
 
-	query := `INSERT INTO badges(badge_code, badge_category, vp_point, name, image_url, status, description, parent_code, created_date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
-	err := tx.QueryRow(ctx, query, badgeCode, badgeCategory, vpPoint, name, imageURL, status, description, parentCode, time.Now().UTC()).Scan(&id)
+	query := `INSERT INTO badges(badge_code, badge_category, vp_point, name, image_url, status, description, parent_code, rule_operator, created_date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	err := tx.QueryRow(ctx, query, badgeCode, badgeCategory, vpPoint, name, imageURL, status, description, parentCode, ruleOperator, time.Now().UTC()).Scan(&id)
 	if err != nil {
 		return 0, c.errHandler("model.AddBadge", err, utils.ErrAddingBadge)
 	}
@@ -251,10 +284,10 @@ func (c *Contract) AddBadge(tx pgx.Tx, ctx context.Context, badgeCode, badgeCate
 }
 
 // UpdateBadge updates an existing badge in the database.
-func (c *Contract) UpdateBadgeByCode(tx pgx.Tx, ctx context.Context, badgeCategory, name, description, imageURL, status string, vpPoint int64, badgeCode string) error {
-	query := `UPDATE badges SET badge_category = $1, name = $2, description = $3, image_url = $4, status = $5, vp_point = $6, updated_date = $7 WHERE badge_code = $8`
+func (c *Contract) UpdateBadgeByCode(tx pgx.Tx, ctx context.Context, badgeCategory, name, description, imageURL, status string, vpPoint int64, ruleOperator, badgeCode string) error {
+	query := `UPDATE badges SET badge_category = $1, name = $2, description = $3, image_url = $4, status = $5, vp_point = $6, rule_operator = $7, updated_date = $8 WHERE badge_code = $9`
 
-	_, err := tx.Exec(ctx, query, badgeCategory, name, description, imageURL, status, vpPoint, time.Now().UTC(), badgeCode)
+	_, err := tx.Exec(ctx, query, badgeCategory, name, description, imageURL, status, vpPoint, ruleOperator, time.Now().UTC(), badgeCode)
 	if err != nil {
 		return c.errHandler("model.UpdateBadgeByCode", err, utils.ErrUpdatingBadge)
 	}
@@ -262,6 +295,31 @@ func (c *Contract) UpdateBadgeByCode(tx pgx.Tx, ctx context.Context, badgeCatego
 	return nil
 }
 
+// UpdateBadgeSchedule sets the earning window and expiry of a badge. The
+// badge is published again by the scheduler once its window opens.
+func (c *Contract) UpdateBadgeSchedule(tx pgx.Tx, ctx context.Context, badgeCode string, availableStartDate, availableEndDate, expiredDate sql.NullTime) error {
+	query := `UPDATE badges SET available_start_date = $1, available_end_date = $2, expired_date = $3, published_date = NULL WHERE badge_code = $4`
+
+	_, err := tx.Exec(ctx, query, availableStartDate, availableEndDate, expiredDate, badgeCode)
+	if err != nil {
+		return c.errHandler("model.UpdateBadgeSchedule", err, utils.ErrUpdatingBadge)
+	}
+
+	return nil
+}
+
+// SetBadgeSeriesLevel makes a badge the given level of a badge series.
+func (c *Contract) SetBadgeSeriesLevel(tx pgx.Tx, ctx context.Context, badgeCode string, level int) error {
+	query := `UPDATE badges SET series_level = $1 WHERE badge_code = $2`
+
+	_, err := tx.Exec(ctx, query, level, badgeCode)
+	if err != nil {
+		return c.errHandler("model.SetBadgeSeriesLevel", err, utils.ErrUpdatingBadge)
+	}
+
+	return nil
+}
+
 // DeleteBadge marks a badge as deleted in the database.
 func (c *Contract) DeleteBadge(tx pgx.Tx, ctx context.Context, id int64) error {
 	query := `UPDATE badges SET deleted_date = $1 WHERE id = $2`
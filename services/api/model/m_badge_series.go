@@ -0,0 +1,218 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// BadgeSeriesEnt is a group of badges earned as ordered levels, such as
+// Bronze/Silver/Gold. Its levels are the badges whose parent_code is the
+// series code, ordered by series_level.
+type BadgeSeriesEnt struct {
+	Id          int64          `db:"id"`
+	SeriesCode  string         `db:"series_code"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	ImageURL    string         `db:"image_url"`
+	Status      string         `db:"status"`
+	CreatedDate time.Time      `db:"created_date"`
+	UpdatedDate sql.NullTime   `db:"updated_date"`
+	DeletedDate sql.NullTime   `db:"deleted_date"`
+}
+
+// AddBadgeSeries adds a new badge series within a transaction.
+func (c *Contract) AddBadgeSeries(tx pgx.Tx, ctx context.Context, seriesCode, name, description, imageURL, status string) error {
+	query := `INSERT INTO badge_series(series_code, name, description, image_url, status, created_date) VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.Exec(ctx, query, seriesCode, name, description, imageURL, status, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.AddBadgeSeries", err, utils.ErrAddingBadgeSeries)
+	}
+
+	return nil
+}
+
+// UpdateBadgeSeries updates a badge series within a transaction.
+func (c *Contract) UpdateBadgeSeries(tx pgx.Tx, ctx context.Context, seriesCode, name, description, imageURL, status string) error {
+	query := `UPDATE badge_series SET name = $1, description = $2, image_url = $3, status = $4, updated_date = $5 WHERE series_code = $6 AND deleted_date IS NULL`
+
+	_, err := tx.Exec(ctx, query, name, description, imageURL, status, time.Now().UTC(), seriesCode)
+	if err != nil {
+		return c.errHandler("model.UpdateBadgeSeries", err, utils.ErrUpdatingBadgeSeries)
+	}
+
+	return nil
+}
+
+// GetBadgeSeriesList retrieves every badge series, optionally only the active ones.
+func (c *Contract) GetBadgeSeriesList(db *pgxpool.Pool, ctx context.Context, activeOnly bool) ([]BadgeSeriesEnt, error) {
+	var (
+		list  []BadgeSeriesEnt
+		query = `SELECT id, series_code, name, description, image_url, status, created_date, updated_date, deleted_date FROM badge_series WHERE deleted_date IS NULL`
+	)
+
+	if activeOnly {
+		query += " AND status = 'active'"
+	}
+	query += " ORDER BY created_date DESC"
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return list, c.errHandler("model.GetBadgeSeriesList", err, utils.ErrGettingBadgeSeries)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data BadgeSeriesEnt
+		err = rows.Scan(
+			&data.Id, &data.SeriesCode, &data.Name, &data.Description, &data.ImageURL, &data.Status,
+			&data.CreatedDate, &data.UpdatedDate, &data.DeletedDate,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetBadgeSeriesList", err, utils.ErrScanningBadgeSeries)
+		}
+		list = append(list, data)
+	}
+
+	if err = rows.Err(); err != nil {
+		return list, c.errHandler("model.GetBadgeSeriesList", err, utils.ErrScanningBadgeSeries)
+	}
+
+	return list, nil
+}
+
+// GetBadgeSeriesByCode retrieves a badge series by its code.
+func (c *Contract) GetBadgeSeriesByCode(db *pgxpool.Pool, ctx context.Context, seriesCode string) (BadgeSeriesEnt, error) {
+	var data BadgeSeriesEnt
+
+	query := `SELECT id, series_code, name, description, image_url, status, created_date, updated_date, deleted_date FROM badge_series WHERE series_code = $1 AND deleted_date IS NULL`
+	err := db.QueryRow(ctx, query, seriesCode).Scan(
+		&data.Id, &data.SeriesCode, &data.Name, &data.Description, &data.ImageURL, &data.Status,
+		&data.CreatedDate, &data.UpdatedDate, &data.DeletedDate,
+	)
+	if err != nil {
+		return data, c.errHandler("model.GetBadgeSeriesByCode", err, utils.ErrGettingBadgeSeries)
+	}
+
+	return data, nil
+}
+
+// GetBadgeSeriesLevels retrieves the levels of a badge series, lowest first.
+func (c *Contract) GetBadgeSeriesLevels(db *pgxpool.Pool, ctx context.Context, seriesCode string) ([]BadgeEnt, error) {
+	var list []BadgeEnt
+
+	query := `SELECT id, badge_code, badge_category, vp_point, description, name, image_url, status, parent_code, rule_operator, series_level, available_start_date, available_end_date, expired_date, created_date, updated_date, deleted_date
+	          FROM badges
+	          WHERE parent_code = $1 AND series_level IS NOT NULL AND deleted_date IS NULL
+	          ORDER BY series_level`
+
+	rows, err := db.Query(ctx, query, seriesCode)
+	if err != nil {
+		return nil, c.errHandler("model.GetBadgeSeriesLevels", err, utils.ErrGettingListBadgeByParentCode)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var badge BadgeEnt
+		err := rows.Scan(
+			&badge.Id, &badge.BadgeCode, &badge.BadgeCategory, &badge.VPPoint, &badge.Description, &badge.Name,
+			&badge.ImageURL, &badge.Status, &badge.ParentCode, &badge.RuleOperator, &badge.SeriesLevel,
+			&badge.AvailableStartDate, &badge.AvailableEndDate, &badge.ExpiredDate, &badge.CreatedDate,
+			&badge.UpdatedDate, &badge.DeletedDate,
+		)
+		if err != nil {
+			return nil, c.errHandler("model.GetBadgeSeriesLevels", err, utils.ErrGettingBadgeByCodeByParentCode)
+		}
+		list = append(list, badge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, c.errHandler("model.GetBadgeSeriesLevels", err, utils.ErrGettingListBadgeByParentCode)
+	}
+
+	return list, nil
+}
+
+// DeleteBadgeSeries marks a badge series and its levels as deleted.
+func (c *Contract) DeleteBadgeSeries(tx pgx.Tx, ctx context.Context, seriesCode string) error {
+	now := time.Now().UTC()
+
+	_, err := tx.Exec(ctx, `UPDATE badge_series SET deleted_date = $1 WHERE series_code = $2`, now, seriesCode)
+	if err != nil {
+		return c.errHandler("model.DeleteBadgeSeries", err, utils.ErrDeletingBadgeSeries)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE badges SET deleted_date = $1 WHERE parent_code = $2 AND series_level IS NOT NULL`, now, seriesCode)
+	if err != nil {
+		return c.errHandler("model.DeleteBadgeSeries", err, utils.ErrDeletingBadgeSeries)
+	}
+
+	return nil
+}
+
+// GetUserBadgeIdsBySeries returns the IDs of the series levels owned by a user.
+func (c *Contract) GetUserBadgeIdsBySeries(db *pgxpool.Pool, ctx context.Context, userId int64, seriesCode string) ([]int64, error) {
+	var list []int64
+
+	query := `SELECT ub.badge_id FROM users_badges ub JOIN badges b ON b.id = ub.badge_id WHERE ub.user_id = $1 AND b.parent_code = $2 AND b.series_level IS NOT NULL`
+	rows, err := db.Query(ctx, query, userId, seriesCode)
+	if err != nil {
+		return nil, c.errHandler("model.GetUserBadgeIdsBySeries", err, utils.ErrGettingBadgeSeries)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, c.errHandler("model.GetUserBadgeIdsBySeries", err, utils.ErrScanningBadgeSeries)
+		}
+		list = append(list, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, c.errHandler("model.GetUserBadgeIdsBySeries", err, utils.ErrScanningBadgeSeries)
+	}
+
+	return list, nil
+}
+
+// GetClaimedSeriesPoint returns the highest VP point of the lower series levels
+// a user has already claimed. Claiming a higher level only grants the difference.
+func (c *Contract) GetClaimedSeriesPoint(db *pgxpool.Pool, ctx context.Context, userId int64, seriesCode string, level int64) (int64, error) {
+	var point int64
+
+	query := `
+		SELECT COALESCE(MAX(b.vp_point), 0)
+		FROM users_badges ub
+		JOIN badges b ON b.id = ub.badge_id
+		WHERE ub.user_id = $1 AND ub.is_claim = true AND b.parent_code = $2 AND b.series_level < $3`
+	err := db.QueryRow(ctx, query, userId, seriesCode, level).Scan(&point)
+	if err != nil {
+		return 0, c.errHandler("model.GetClaimedSeriesPoint", err, utils.ErrGettingClaimedSeriesPoint)
+	}
+
+	return point, nil
+}
+
+// GetHighestSeriesLevel returns the highest level of a badge series owned by a
+// user, or 0 when they own none.
+func (c *Contract) GetHighestSeriesLevel(db *pgxpool.Pool, ctx context.Context, userId int64, seriesCode string) (int64, error) {
+	var level int64
+
+	query := `
+		SELECT COALESCE(MAX(b.series_level), 0)
+		FROM users_badges ub
+		JOIN badges b ON b.id = ub.badge_id
+		WHERE ub.user_id = $1 AND b.parent_code = $2 AND b.series_level IS NOT NULL AND b.deleted_date IS NULL`
+	err := db.QueryRow(ctx, query, userId, seriesCode).Scan(&level)
+	if err != nil {
+		return 0, c.errHandler("model.GetHighestSeriesLevel", err, utils.ErrGettingHighestSeriesLevel)
+	}
+
+	return level, nil
+}
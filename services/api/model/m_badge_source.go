@@ -0,0 +1,412 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/badge"
+	"dots-api/lib/utils"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// BadgeSource reads the member statistics used by the badge rule evaluators.
+// Only the activity inside its earning window counts, see Within.
+type BadgeSource struct {
+	c     *Contract
+	db    *pgxpool.Pool
+	start sql.NullTime
+	end   sql.NullTime
+}
+
+var (
+	_ badge.Source    = BadgeSource{}
+	_ badge.SetSource = BadgeSource{}
+)
+
+// NewBadgeSource returns a badge.Source backed by the given pool.
+func (c *Contract) NewBadgeSource(db *pgxpool.Pool) BadgeSource {
+	return BadgeSource{c: c, db: db}
+}
+
+// Within returns a copy of the source counting only the activity inside the
+// earning window of a badge. An open bound does not limit the activity.
+func (s BadgeSource) Within(start, end sql.NullTime) BadgeSource {
+	s.start, s.end = start, end
+
+	return s
+}
+
+func (s BadgeSource) CountGamesPlayed(ctx context.Context, userId int64, gameCodes []string, bookingPrice float64, needGM bool) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountGamesPlayed", gamesPlayedMetric(gameCodes, bookingPrice, needGM), userId)
+}
+
+func (s BadgeSource) CountParticipations(ctx context.Context, userId int64, startDate, endDate string) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountParticipations", participationsMetric(startDate, endDate), userId)
+}
+
+func (s BadgeSource) TotalSpend(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.TotalSpend", totalSpendMetric, userId)
+}
+
+func (s BadgeSource) CountTournamentWon(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountTournamentWon", tournamentWonMetric, userId)
+}
+
+func (s BadgeSource) CountGameCollections(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountGameCollections", gameCollectionsMetric, userId)
+}
+
+func (s BadgeSource) CountCafeVisits(ctx context.Context, userId int64, cafeCode string) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountCafeVisits", cafeVisitsMetric(cafeCode), userId)
+}
+
+func (s BadgeSource) CountCoPlayers(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountCoPlayers", coPlayersMetric, userId)
+}
+
+func (s BadgeSource) CountGameCategories(ctx context.Context, userId int64, category string) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountGameCategories", gameCategoriesMetric(category), userId)
+}
+
+func (s BadgeSource) CountGameMasterSessions(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountGameMasterSessions", gameMasterSessionsMetric, userId)
+}
+
+func (s BadgeSource) CountRoomRankFinishes(ctx context.Context, userId int64, maxRank int64, gameCode string) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountRoomRankFinishes", roomRankFinishesMetric(maxRank, gameCode), userId)
+}
+
+func (s BadgeSource) CountMemberHostSessions(ctx context.Context, userId int64) (int64, error) {
+	return s.countMetric(ctx, "model.BadgeSource.CountMemberHostSessions", memberHostSessionsMetric, userId)
+}
+
+func (s BadgeSource) UsersByGamesPlayed(ctx context.Context, gameCodes []string, bookingPrice float64, needGM bool, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByGamesPlayed", gamesPlayedMetric(gameCodes, bookingPrice, needGM), min)
+}
+
+func (s BadgeSource) UsersByParticipations(ctx context.Context, startDate, endDate string, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByParticipations", participationsMetric(startDate, endDate), min)
+}
+
+func (s BadgeSource) UsersByTotalSpend(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByTotalSpend", totalSpendMetric, min)
+}
+
+func (s BadgeSource) UsersByTournamentWon(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByTournamentWon", tournamentWonMetric, min)
+}
+
+func (s BadgeSource) UsersByGameCollections(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByGameCollections", gameCollectionsMetric, min)
+}
+
+func (s BadgeSource) UsersByCafeVisits(ctx context.Context, cafeCode string, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByCafeVisits", cafeVisitsMetric(cafeCode), min)
+}
+
+func (s BadgeSource) UsersByCoPlayers(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByCoPlayers", coPlayersMetric, min)
+}
+
+func (s BadgeSource) UsersByGameCategories(ctx context.Context, category string, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByGameCategories", gameCategoriesMetric(category), min)
+}
+
+func (s BadgeSource) UsersByGameMasterSessions(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByGameMasterSessions", gameMasterSessionsMetric, min)
+}
+
+func (s BadgeSource) UsersByRoomRankFinishes(ctx context.Context, maxRank int64, gameCode string, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByRoomRankFinishes", roomRankFinishesMetric(maxRank, gameCode), min)
+}
+
+func (s BadgeSource) UsersByMemberHostSessions(ctx context.Context, min int64) ([]int64, error) {
+	return s.usersByMetric(ctx, "model.BadgeSource.UsersByMemberHostSessions", memberHostSessionsMetric, min)
+}
+
+// metric builds a query returning one (user_id, total) row per member. Its
+// arguments are added through the scope, which also narrows the activity to
+// the earning window and, when counting for one member, to that member.
+type metric func(sc *metricScope) string
+
+type metricScope struct {
+	args   []interface{}
+	start  sql.NullTime
+	end    sql.NullTime
+	userId sql.NullInt64
+}
+
+// arg adds an argument to the query and returns its placeholder.
+func (sc *metricScope) arg(value interface{}) string {
+	sc.args = append(sc.args, value)
+
+	return fmt.Sprintf("$%d", len(sc.args))
+}
+
+// window keeps the activity whose date column is inside the earning window.
+func (sc *metricScope) window(column string) string {
+	var cond string
+	if sc.start.Valid {
+		cond += " AND " + column + " >= " + sc.arg(sc.start.Time)
+	}
+	if sc.end.Valid {
+		cond += " AND " + column + " <= " + sc.arg(sc.end.Time)
+	}
+
+	return cond
+}
+
+// user keeps the rows of the member being counted, if any.
+func (sc *metricScope) user(column string) string {
+	if !sc.userId.Valid {
+		return ""
+	}
+
+	return " AND " + column + " = " + sc.arg(sc.userId.Int64)
+}
+
+// participations lists every active room and tournament participation with
+// the session it belongs to, of the member being counted or of everyone.
+func (sc *metricScope) participations() string {
+	return sc.participationsOf(sc.user)
+}
+
+// allParticipations lists the participations of every member.
+func (sc *metricScope) allParticipations() string {
+	return sc.participationsOf(func(string) string { return "" })
+}
+
+func (sc *metricScope) participationsOf(user func(column string) string) string {
+	return `
+	SELECT rp.user_id, 'room' AS source, r.id AS session_id, r.game_id, r.start_date
+	FROM rooms_participants rp
+	JOIN rooms r ON rp.room_id = r.id
+	WHERE rp.status = 'active'` + user("rp.user_id") + sc.window("r.start_date") + `
+	UNION ALL
+	SELECT tp.user_id, 'tournament' AS source, t.id AS session_id, t.game_id, t.start_date
+	FROM tournament_participants tp
+	JOIN tournaments t ON tp.tournament_id = t.id
+	WHERE tp.status = 'active'` + user("tp.user_id") + sc.window("t.start_date")
+}
+
+// gamesPlayedMetric counts the rooms and tournaments of the given games joined
+// at the given booking price or more. needGM only counts the rooms with a game
+// master.
+func gamesPlayedMetric(gameCodes []string, bookingPrice float64, needGM bool) metric {
+	return func(sc *metricScope) string {
+		roomFilter := ""
+		if needGM {
+			roomFilter = " AND r.game_master_id IS NOT NULL"
+		}
+
+		codes, price := sc.arg(gameCodes), sc.arg(bookingPrice)
+
+		return `
+	SELECT p.user_id, COUNT(*) AS total FROM (
+		SELECT rp.user_id
+		FROM rooms_participants rp
+		JOIN rooms r ON rp.room_id = r.id
+		JOIN games g ON g.id = r.game_id
+		WHERE rp.status = 'active' AND g.game_code = ANY(` + codes + `) AND r.booking_price >= ` + price + roomFilter + sc.user("rp.user_id") + sc.window("r.start_date") + `
+		UNION ALL
+		SELECT tp.user_id
+		FROM tournament_participants tp
+		JOIN tournaments t ON tp.tournament_id = t.id
+		JOIN games g ON g.id = t.game_id
+		WHERE tp.status = 'active' AND g.game_code = ANY(` + codes + `) AND t.booking_price >= ` + price + sc.user("tp.user_id") + sc.window("t.start_date") + `
+	) p
+	GROUP BY p.user_id`
+	}
+}
+
+// participationsMetric counts the rooms and tournaments joined from startDate
+// on, up to endDate unless it is empty.
+func participationsMetric(startDate, endDate string) metric {
+	return func(sc *metricScope) string {
+		period := " AND p.start_date >= " + sc.arg(startDate) + "::date"
+		if endDate != "" {
+			period += " AND p.start_date <= " + sc.arg(endDate) + "::date"
+		}
+
+		return `
+	SELECT p.user_id, COUNT(*) AS total
+	FROM (` + sc.participations() + `) p
+	WHERE true` + period + `
+	GROUP BY p.user_id`
+	}
+}
+
+// totalSpendMetric sums the paid bookings and the redeemed invoices of a
+// member.
+func totalSpendMetric(sc *metricScope) string {
+	return `
+	SELECT p.user_id, COALESCE(SUM(p.amount), 0)::bigint AS total FROM (
+		SELECT user_id, price AS amount FROM users_transactions WHERE status = 'PAID'` + sc.user("user_id") + sc.window("created_date") + `
+		UNION ALL
+		SELECT user_id, invoice_amount AS amount FROM user_redeem_histories WHERE true` + sc.user("user_id") + sc.window("created_date") + `
+	) p
+	GROUP BY p.user_id`
+}
+
+// tournamentWonMetric counts the tournaments won by a member.
+func tournamentWonMetric(sc *metricScope) string {
+	return `
+	SELECT tp.user_id, COUNT(*) AS total
+	FROM tournament_participants tp
+	JOIN tournaments t ON tp.tournament_id = t.id
+	WHERE tp.status_winner = true` + sc.user("tp.user_id") + sc.window("t.start_date") + `
+	GROUP BY tp.user_id`
+}
+
+// gameCollectionsMetric counts the games a member added to their collection.
+func gameCollectionsMetric(sc *metricScope) string {
+	return `
+	SELECT user_id, COUNT(*) AS total
+	FROM users_game_collections
+	WHERE true` + sc.user("user_id") + sc.window("created_date") + `
+	GROUP BY user_id`
+}
+
+// cafeVisitsMetric counts the distinct days a member played at a cafe.
+func cafeVisitsMetric(cafeCode string) metric {
+	return func(sc *metricScope) string {
+		return `
+	SELECT p.user_id, COUNT(DISTINCT p.start_date) AS total
+	FROM (` + sc.participations() + `) p
+	JOIN games g ON g.id = p.game_id
+	JOIN cafes c ON c.id = g.cafe_id
+	WHERE c.cafe_code = ` + sc.arg(cafeCode) + `
+	GROUP BY p.user_id`
+	}
+}
+
+// coPlayersMetric counts the distinct members sharing a room or tournament
+// with a member.
+func coPlayersMetric(sc *metricScope) string {
+	return `
+	SELECT p.user_id, COUNT(DISTINCT o.user_id) AS total
+	FROM (` + sc.participations() + `) p
+	JOIN (` + sc.allParticipations() + `) o ON o.source = p.source AND o.session_id = p.session_id AND o.user_id <> p.user_id
+	GROUP BY p.user_id`
+}
+
+// gameCategoriesMetric counts the distinct game mechanics or game types a
+// member played.
+func gameCategoriesMetric(category string) metric {
+	return func(sc *metricScope) string {
+		if category == utils.GameType {
+			return `
+	SELECT p.user_id, COUNT(DISTINCT g.game_type) AS total
+	FROM (` + sc.participations() + `) p
+	JOIN games g ON g.id = p.game_id
+	GROUP BY p.user_id`
+		}
+
+		return `
+	SELECT p.user_id, COUNT(DISTINCT lower(gc.category_name)) AS total
+	FROM (` + sc.participations() + `) p
+	JOIN games_categories gc ON gc.game_id = p.game_id
+	GROUP BY p.user_id`
+	}
+}
+
+// gameMasterSessionsMetric counts the finished rooms hosted by a member as
+// game master. Game masters are admins linked to their member account.
+func gameMasterSessionsMetric(sc *metricScope) string {
+	return `
+	SELECT a.user_id, COUNT(DISTINCT r.id) AS total
+	FROM rooms r
+	JOIN admins a ON a.id = r.game_master_id
+	WHERE a.user_id IS NOT NULL AND r.deleted_date IS NULL AND r.status = 'closed'` + sc.user("a.user_id") + sc.window("r.start_date") + `
+	GROUP BY a.user_id`
+}
+
+// roomRankFinishesMetric counts the rooms a member finished at rank maxRank or
+// better, of the given game or of every game when gameCode is empty.
+func roomRankFinishesMetric(maxRank int64, gameCode string) metric {
+	return func(sc *metricScope) string {
+		rank, code := sc.arg(maxRank), sc.arg(gameCode)
+
+		return `
+	SELECT rp.user_id, COUNT(*) AS total
+	FROM rooms_participants rp
+	JOIN rooms r ON rp.room_id = r.id
+	JOIN games g ON g.id = r.game_id
+	WHERE rp.status = 'active' AND rp."rank" BETWEEN 1 AND ` + rank + ` AND (` + code + `::text = '' OR g.game_code = ` + code + `)` + sc.user("rp.user_id") + sc.window("r.start_date") + `
+	GROUP BY rp.user_id`
+	}
+}
+
+// memberHostSessionsMetric counts the finished rooms a member hosted from
+// their own approved proposals. Cancelled rooms do not count.
+func memberHostSessionsMetric(sc *metricScope) string {
+	return `
+	SELECT r.host_user_id AS user_id, COUNT(*) AS total
+	FROM rooms r
+	WHERE r.host_user_id IS NOT NULL AND r.deleted_date IS NULL AND r.status = 'closed'` + sc.user("r.host_user_id") + sc.window("r.start_date") + `
+	GROUP BY r.host_user_id`
+}
+
+func (s BadgeSource) scope() *metricScope {
+	return &metricScope{start: s.start, end: s.end}
+}
+
+// countMetric returns the total of one member in a metric query. The metric
+// only reads the rows of that member.
+func (s BadgeSource) countMetric(ctx context.Context, funcName string, m metric, userId int64) (int64, error) {
+	var (
+		total int64
+		sc    = s.scope()
+	)
+	sc.userId = sql.NullInt64{Int64: userId, Valid: true}
+
+	query := `SELECT COALESCE((SELECT SUM(m.total) FROM (` + m(sc) + `) m), 0)::bigint`
+	err := s.db.QueryRow(ctx, query, sc.args...).Scan(&total)
+	if err != nil {
+		return 0, s.c.errHandler(funcName, err, utils.ErrCountingBadgeMetric)
+	}
+
+	return total, nil
+}
+
+// usersByMetric returns the members whose total in a metric query reaches min.
+func (s BadgeSource) usersByMetric(ctx context.Context, funcName string, m metric, min int64) ([]int64, error) {
+	sc := s.scope()
+	query := `SELECT m.user_id FROM (` + m(sc) + `) m WHERE m.total >= ` + sc.arg(min)
+
+	return s.queryUserIds(ctx, funcName, query, sc.args...)
+}
+
+func (s BadgeSource) queryUserIds(ctx context.Context, funcName, query string, args ...interface{}) ([]int64, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, s.c.errHandler(funcName, err, utils.ErrGettingQualifiedUsers)
+	}
+	defer rows.Close()
+
+	var list []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, s.c.errHandler(funcName, err, utils.ErrGettingQualifiedUsers)
+		}
+		list = append(list, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, s.c.errHandler(funcName, err, utils.ErrGettingQualifiedUsers)
+	}
+
+	return list, nil
+}
+
+// BadgeRules converts stored badge rules into evaluator rules.
+func BadgeRules(list []BadgeRuleEnt) []badge.Rule {
+	rules := make([]badge.Rule, 0, len(list))
+	for _, v := range list {
+		rules = append(rules, badge.Rule{KeyCondition: v.KeyCondition, Value: v.Value})
+	}
+
+	return rules
+}
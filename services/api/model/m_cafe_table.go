@@ -0,0 +1,300 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	CafeTableEnt struct {
+		Id        int64  `db:"id"`
+		TableCode string `db:"table_code"`
+		CafeCode  string `db:"cafe_code"`
+		CafeCity  string `db:"cafe_city"`
+		Name      string `db:"name"`
+		TableType string `db:"table_type"`
+		Seats     int    `db:"seats"`
+		Status    string `db:"status"`
+	}
+
+	// CafeTableEventEnt is a room or tournament played at a table. Start and
+	// End are the WIB wall clock, in UTC like every event date.
+	CafeTableEventEnt struct {
+		EventType    string        `db:"event_type"`
+		EventId      int64         `db:"event_id"`
+		EventCode    string        `db:"event_code"`
+		Seats        int           `db:"seats"`
+		LocationCity string        `db:"location_city"`
+		Start        time.Time     `db:"start"`
+		End          time.Time     `db:"end"`
+		TableId      sql.NullInt64 `db:"table_id"`
+	}
+
+	// CafeTableBookingEnt is a room or tournament taking up a table on a day
+	// of a floor plan.
+	CafeTableBookingEnt struct {
+		TableId      int64     `db:"table_id"`
+		EventType    string    `db:"event_type"`
+		EventCode    string    `db:"event_code"`
+		EventName    string    `db:"event_name"`
+		Start        time.Time `db:"start"`
+		End          time.Time `db:"end"`
+		Seats        int       `db:"seats"`
+		Participants int       `db:"participants"`
+		Status       string    `db:"status"`
+	}
+)
+
+// cafeTableEventTypes are the events played at tables, in the order floor
+// plans and conflicts list them.
+var cafeTableEventTypes = []string{utils.WaitlistRoom, utils.WaitlistTournament}
+
+const cafeTableQuery = `
+	SELECT t.id, t.table_code, c.cafe_code, COALESCE(c.city, ''), t.name, t.table_type, t.seats, t.status
+	FROM cafe_tables t
+		JOIN cafes c ON c.id = t.cafe_id
+	WHERE t.deleted_date IS NULL`
+
+func scanCafeTable(row pgx.Row, data *CafeTableEnt) error {
+	return row.Scan(&data.Id, &data.TableCode, &data.CafeCode, &data.CafeCity, &data.Name, &data.TableType, &data.Seats, &data.Status)
+}
+
+// GetCafeTables returns the tables of a cafe.
+func (c *Contract) GetCafeTables(db *pgxpool.Pool, ctx context.Context, cafeId int64) ([]CafeTableEnt, error) {
+	var list []CafeTableEnt
+
+	rows, err := db.Query(ctx, cafeTableQuery+` AND t.cafe_id = $1 ORDER BY t.name, t.id`, cafeId)
+	if err != nil {
+		return nil, c.errHandler("model.GetCafeTables", err, utils.ErrGettingCafeTables)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data CafeTableEnt
+		if err = scanCafeTable(rows, &data); err != nil {
+			return nil, c.errHandler("model.GetCafeTables", err, utils.ErrGettingCafeTables)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+func (c *Contract) GetCafeTableByCode(db *pgxpool.Pool, ctx context.Context, code string) (CafeTableEnt, error) {
+	var data CafeTableEnt
+
+	err := scanCafeTable(db.QueryRow(ctx, cafeTableQuery+` AND t.table_code = $1`, code), &data)
+	if err != nil {
+		return data, c.errHandler("model.GetCafeTableByCode", err, utils.ErrGettingCafeTableByCode)
+	}
+
+	return data, nil
+}
+
+func (c *Contract) GetCafeTableById(db *pgxpool.Pool, ctx context.Context, id int64) (CafeTableEnt, error) {
+	var data CafeTableEnt
+
+	err := scanCafeTable(db.QueryRow(ctx, cafeTableQuery+` AND t.id = $1`, id), &data)
+	if err != nil {
+		return data, c.errHandler("model.GetCafeTableById", err, utils.ErrGettingCafeTableByCode)
+	}
+
+	return data, nil
+}
+
+func (c *Contract) AddCafeTable(db *pgxpool.Pool, ctx context.Context, cafeId int64, data CafeTableEnt) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO cafe_tables (cafe_id, table_code, name, table_type, seats, status, created_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		cafeId, data.TableCode, data.Name, data.TableType, data.Seats, data.Status, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.AddCafeTable", err, utils.ErrAddingCafeTable)
+	}
+
+	return nil
+}
+
+func (c *Contract) UpdateCafeTable(db *pgxpool.Pool, ctx context.Context, data CafeTableEnt) error {
+	_, err := db.Exec(ctx, `
+		UPDATE cafe_tables SET name = $1, table_type = $2, seats = $3, status = $4, updated_date = $5
+		WHERE id = $6`,
+		data.Name, data.TableType, data.Seats, data.Status, time.Now().UTC(), data.Id)
+	if err != nil {
+		return c.errHandler("model.UpdateCafeTable", err, utils.ErrUpdatingCafeTable)
+	}
+
+	return nil
+}
+
+// DeleteCafeTable removes a table that no upcoming room or tournament is
+// assigned to. Past events keep it.
+func (c *Contract) DeleteCafeTable(db *pgxpool.Pool, ctx context.Context, id int64, today time.Time) error {
+	var queries []string
+	for _, eventType := range cafeTableEventTypes {
+		table := waitlistTables[eventType]
+		queries = append(queries, `
+			SELECT 1 FROM `+table.event+` r
+			WHERE r.table_id = $1 AND r.deleted_date IS NULL AND COALESCE(r.end_date, r.start_date) >= $2`)
+	}
+
+	var inUse bool
+	err := db.QueryRow(ctx, `SELECT EXISTS (`+strings.Join(queries, " UNION ALL ")+`)`, id, today).Scan(&inUse)
+	if err != nil {
+		return c.errHandler("model.DeleteCafeTable", err, utils.ErrDeletingCafeTable)
+	}
+	if inUse {
+		return errors.New(utils.ErrCafeTableInUse)
+	}
+
+	now := time.Now().UTC()
+	_, err = db.Exec(ctx, `UPDATE cafe_tables SET updated_date = $1, deleted_date = $1 WHERE id = $2`, now, id)
+	if err != nil {
+		return c.errHandler("model.DeleteCafeTable", err, utils.ErrDeletingCafeTable)
+	}
+
+	return nil
+}
+
+// GetCafeTableEvent returns the schedule, seats and table of a room or
+// tournament.
+func (c *Contract) GetCafeTableEvent(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) (CafeTableEventEnt, error) {
+	var (
+		data      = CafeTableEventEnt{EventType: eventType, EventId: eventId}
+		table, ok = waitlistTables[eventType]
+	)
+	if !ok {
+		return data, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `
+		SELECT r.` + table.code + `, r.` + table.seats + `, COALESCE(r.location_city, ''), ` + eventStart + `, ` + eventEnd + `, r.table_id
+		FROM ` + table.event + ` r WHERE r.id = $1`
+	err := db.QueryRow(ctx, query, eventId).Scan(&data.EventCode, &data.Seats, &data.LocationCity, &data.Start, &data.End, &data.TableId)
+	if err != nil {
+		return data, c.errHandler("model.GetCafeTableEvent", err, utils.ErrCheckingCafeTable)
+	}
+
+	return data, nil
+}
+
+// LockCafeTableTrx locks a table until tx ends, so its bookings are checked
+// and an event assigned to it by one request at a time. CheckCafeTableBooking
+// and the write of the event follow the lock. The row is locked FOR NO KEY
+// UPDATE, so events referencing it can still be written through other
+// connections while tx is open.
+func (c *Contract) LockCafeTableTrx(tx pgx.Tx, ctx context.Context, tableId int64) error {
+	_, err := tx.Exec(ctx, `SELECT id FROM cafe_tables WHERE id = $1 FOR NO KEY UPDATE`, tableId)
+	if err != nil {
+		return c.errHandler("model.LockCafeTableTrx", err, utils.ErrCheckingCafeTable)
+	}
+
+	return nil
+}
+
+// CheckCafeTableBooking checks that event can be played at a table: the table
+// is active, in the city of the event, seats everyone and is not booked for
+// another room or tournament at the same time. Cancelled events do not book
+// their table. The error names what is in the way.
+func (c *Contract) CheckCafeTableBooking(db *pgxpool.Pool, ctx context.Context, data CafeTableEnt, event CafeTableEventEnt) error {
+	if data.Status != "active" {
+		return fmt.Errorf(utils.ErrCafeTableInactive, data.Name)
+	}
+	if event.LocationCity != "" && data.CafeCity != event.LocationCity {
+		return fmt.Errorf(utils.ErrCafeTableOtherCity, data.Name, data.CafeCity, event.LocationCity)
+	}
+	if event.Seats > data.Seats {
+		return fmt.Errorf(utils.ErrCafeTableTooSmall, data.Name, data.Seats, event.Seats)
+	}
+
+	var queries []string
+	for _, eventType := range cafeTableEventTypes {
+		table := waitlistTables[eventType]
+		queries = append(queries, `
+			SELECT '`+eventType+`' AS event_type, r.`+table.code+` AS event_code, COALESCE(r.name, '') AS event_name, `+eventStart+` AS start, `+eventEnd+` AS "end"
+			FROM `+table.event+` r
+			WHERE r.table_id = $1 AND r.deleted_date IS NULL AND r.status != 'cancelled' AND NOT ('`+eventType+`' = $2 AND r.id = $3)
+				AND `+eventStart+` < $5::timestamp AND $4::timestamp < `+eventEnd)
+	}
+
+	var (
+		eventType, code, name string
+		start, end            time.Time
+	)
+	err := db.QueryRow(ctx, strings.Join(queries, " UNION ALL ")+` ORDER BY start LIMIT 1`,
+		data.Id, event.EventType, event.EventId, event.Start, event.End,
+	).Scan(&eventType, &code, &name, &start, &end)
+	if err == nil {
+		return fmt.Errorf(utils.ErrCafeTableConflict, data.Name, eventType, code, name, start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+	}
+	if err != pgx.ErrNoRows {
+		return c.errHandler("model.CheckCafeTableBooking", err, utils.ErrCheckingCafeTable)
+	}
+
+	return nil
+}
+
+// SetEventTable assigns a room or tournament to a table, or unassigns it.
+func (c *Contract) SetEventTable(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64, tableId sql.NullInt64) error {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	_, err := db.Exec(ctx, `UPDATE `+table.event+` SET table_id = $1, updated_date = $2 WHERE id = $3`, tableId, time.Now().UTC(), eventId)
+	if err != nil {
+		return c.errHandler("model.SetEventTable", err, utils.ErrAssigningCafeTable)
+	}
+
+	return nil
+}
+
+// GetCafeTableBookings returns the rooms and tournaments played at the tables
+// of a cafe on date, in order.
+func (c *Contract) GetCafeTableBookings(db *pgxpool.Pool, ctx context.Context, cafeId int64, date time.Time) ([]CafeTableBookingEnt, error) {
+	var (
+		list    []CafeTableBookingEnt
+		queries []string
+	)
+
+	for _, eventType := range cafeTableEventTypes {
+		table := waitlistTables[eventType]
+		queries = append(queries, `
+			SELECT
+				r.table_id, '`+eventType+`' AS event_type, r.`+table.code+` AS event_code, COALESCE(r.name, '') AS event_name,
+				`+eventStart+` AS start, `+eventEnd+` AS "end", r.`+table.seats+`,
+				(SELECT COUNT(*) FROM `+table.participants+` p WHERE p.`+table.participantEvent+` = r.id AND p.status = 'active'),
+				r.status
+			FROM `+table.event+` r
+				JOIN cafe_tables t ON t.id = r.table_id
+			WHERE t.cafe_id = $1 AND t.deleted_date IS NULL AND r.deleted_date IS NULL
+				AND r.start_date <= $2 AND COALESCE(r.end_date, r.start_date) >= $2`)
+	}
+
+	rows, err := db.Query(ctx, strings.Join(queries, " UNION ALL ")+` ORDER BY start`, cafeId, date)
+	if err != nil {
+		return nil, c.errHandler("model.GetCafeTableBookings", err, utils.ErrGettingFloorPlan)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data CafeTableBookingEnt
+		err = rows.Scan(
+			&data.TableId, &data.EventType, &data.EventCode, &data.EventName,
+			&data.Start, &data.End, &data.Seats, &data.Participants, &data.Status,
+		)
+		if err != nil {
+			return nil, c.errHandler("model.GetCafeTableBookings", err, utils.ErrGettingFloorPlan)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
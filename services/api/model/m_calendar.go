@@ -0,0 +1,188 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"dots-api/lib/utils"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CalendarEventEnt is a room or tournament shown in a calendar feed.
+type CalendarEventEnt struct {
+	EventType   string       `db:"event_type"`
+	EventCode   string       `db:"event_code"`
+	Name        string       `db:"name"`
+	Description string       `db:"description"`
+	GameName    string       `db:"game_name"`
+	CafeName    string       `db:"cafe_name"`
+	CafeAddress string       `db:"cafe_address"`
+	StartDate   sql.NullTime `db:"start_date"`
+	EndDate     sql.NullTime `db:"end_date"`
+	StartTime   time.Time    `db:"start_time"`
+	EndTime     time.Time    `db:"end_time"`
+	Status      string       `db:"status"`
+	UpdatedDate time.Time    `db:"updated_date"`
+}
+
+var (
+	// calendarRoomQuery and calendarTournamentQuery select the events of a
+	// calendar feed, filtered by the WHERE clause that follows them.
+	calendarRoomQuery = `
+		SELECT
+			'room' AS event_type, r.room_code AS event_code, r.name, COALESCE(r.description, '') AS description,
+			g.name AS game_name, c.name AS cafe_name, COALESCE(c.address, '') AS cafe_address,
+			r.start_date, r.end_date, COALESCE(r.start_time, '00:00:00') AS start_time, COALESCE(r.end_time, '23:59:59') AS end_time,
+			r.status, COALESCE(r.updated_date, r.created_date) AS updated_date
+		FROM rooms r
+			JOIN games g ON g.id = r.game_id
+			JOIN cafes c ON c.id = g.cafe_id`
+
+	calendarTournamentQuery = `
+		SELECT
+			'tournament' AS event_type, t.tournament_code AS event_code, t.name, COALESCE(t.tournament_rules, '') AS description,
+			g.name AS game_name, c.name AS cafe_name, COALESCE(c.address, '') AS cafe_address,
+			t.start_date, t.end_date, COALESCE(t.start_time, '00:00:00') AS start_time, COALESCE(t.end_time, '23:59:59') AS end_time,
+			t.status, COALESCE(t.updated_date, t.created_date) AS updated_date
+		FROM tournaments t
+			JOIN games g ON g.id = t.game_id
+			JOIN cafes c ON c.id = g.cafe_id`
+)
+
+func (c *Contract) scanCalendarEvents(rows pgx.Rows, funcName string) ([]CalendarEventEnt, error) {
+	var list []CalendarEventEnt
+
+	defer rows.Close()
+	for rows.Next() {
+		var data CalendarEventEnt
+		err := rows.Scan(
+			&data.EventType, &data.EventCode, &data.Name, &data.Description,
+			&data.GameName, &data.CafeName, &data.CafeAddress,
+			&data.StartDate, &data.EndDate, &data.StartTime, &data.EndTime,
+			&data.Status, &data.UpdatedDate,
+		)
+		if err != nil {
+			return list, c.errHandler(funcName, err, utils.ErrGettingCalendarEvents)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// newCalendarToken returns 32 random bytes from crypto/rand, hex encoded.
+func newCalendarToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// GetCalendarToken returns the secret of the calendar feed of a member and
+// creates it the first time.
+func (c *Contract) GetCalendarToken(db *pgxpool.Pool, ctx context.Context, userId int64) (string, error) {
+	var token string
+
+	newToken, err := newCalendarToken()
+	if err != nil {
+		return "", c.errHandler("model.GetCalendarToken", err, utils.ErrGettingCalendarToken)
+	}
+
+	query := `UPDATE users SET calendar_token = COALESCE(calendar_token, $1) WHERE id = $2 RETURNING calendar_token`
+	err = db.QueryRow(ctx, query, newToken, userId).Scan(&token)
+	if err != nil {
+		return "", c.errHandler("model.GetCalendarToken", err, utils.ErrGettingCalendarToken)
+	}
+
+	return token, nil
+}
+
+// ResetCalendarToken replaces the secret of the calendar feed of a member, so
+// the feed link shared before stops working.
+func (c *Contract) ResetCalendarToken(db *pgxpool.Pool, ctx context.Context, userId int64) (string, error) {
+	var token string
+
+	newToken, err := newCalendarToken()
+	if err != nil {
+		return "", c.errHandler("model.ResetCalendarToken", err, utils.ErrResettingCalendarToken)
+	}
+
+	query := `UPDATE users SET calendar_token = $1 WHERE id = $2 RETURNING calendar_token`
+	err = db.QueryRow(ctx, query, newToken, userId).Scan(&token)
+	if err != nil {
+		return "", c.errHandler("model.ResetCalendarToken", err, utils.ErrResettingCalendarToken)
+	}
+
+	return token, nil
+}
+
+func (c *Contract) GetUserIdByCalendarToken(db *pgxpool.Pool, ctx context.Context, token string) (int64, error) {
+	var userId int64
+
+	query := `SELECT id FROM users WHERE calendar_token = $1 AND deleted_date IS NULL`
+	err := db.QueryRow(ctx, query, token).Scan(&userId)
+	if err == pgx.ErrNoRows {
+		return 0, errors.New(utils.ErrInvalidCalendarToken)
+	}
+	if err != nil {
+		return 0, c.errHandler("model.GetUserIdByCalendarToken", err, utils.ErrGettingCalendarToken)
+	}
+
+	return userId, nil
+}
+
+// GetUserCalendarEvents lists the paid bookings of a member ending from from
+// on, and the events they were booked on that were cancelled.
+func (c *Contract) GetUserCalendarEvents(db *pgxpool.Pool, ctx context.Context, userId int64, from time.Time) ([]CalendarEventEnt, error) {
+	query := calendarRoomQuery + `
+			JOIN rooms_participants rp ON rp.room_id = r.id AND rp.user_id = $1
+		WHERE r.deleted_date IS NULL AND r.end_date >= $2 AND (
+			rp.status = 'active' OR EXISTS (
+				SELECT 1 FROM event_cancellations ec
+					JOIN event_cancellation_participants ecp ON ecp.cancellation_id = ec.id
+				WHERE ec.event_type = 'room' AND ec.event_id = r.id AND ecp.user_id = rp.user_id
+			)
+		)
+		UNION ALL` + calendarTournamentQuery + `
+			JOIN tournament_participants tp ON tp.tournament_id = t.id AND tp.user_id = $1
+		WHERE t.deleted_date IS NULL AND t.end_date >= $2 AND (
+			tp.status = 'active' OR EXISTS (
+				SELECT 1 FROM event_cancellations ec
+					JOIN event_cancellation_participants ecp ON ecp.cancellation_id = ec.id
+				WHERE ec.event_type = 'tournament' AND ec.event_id = t.id AND ecp.user_id = tp.user_id
+			)
+		)
+		ORDER BY start_date, start_time`
+	rows, err := db.Query(ctx, query, userId, from)
+	if err != nil {
+		return nil, c.errHandler("model.GetUserCalendarEvents", err, utils.ErrGettingCalendarEvents)
+	}
+
+	return c.scanCalendarEvents(rows, "model.GetUserCalendarEvents")
+}
+
+// GetCafeCalendarEvents lists the public rooms and the tournaments of a cafe
+// ending from from on. Cancelled events stay in the list so subscribed
+// calendars remove them.
+func (c *Contract) GetCafeCalendarEvents(db *pgxpool.Pool, ctx context.Context, cafeCode string, from time.Time) ([]CalendarEventEnt, error) {
+	query := calendarRoomQuery + `
+		WHERE c.cafe_code = $1 AND r.deleted_date IS NULL AND r.end_date >= $2
+			AND r.visibility = 'public' AND r.status IN ('active', 'cancelled')
+		UNION ALL` + calendarTournamentQuery + `
+		WHERE c.cafe_code = $1 AND t.deleted_date IS NULL AND t.end_date >= $2
+			AND t.status IN ('active', 'cancelled')
+		ORDER BY start_date, start_time`
+	rows, err := db.Query(ctx, query, cafeCode, from)
+	if err != nil {
+		return nil, c.errHandler("model.GetCafeCalendarEvents", err, utils.ErrGettingCalendarEvents)
+	}
+
+	return c.scanCalendarEvents(rows, "model.GetCafeCalendarEvents")
+}
@@ -0,0 +1,530 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/mail"
+	"dots-api/lib/onesignal"
+	"dots-api/lib/utils"
+	payment "dots-api/lib/xendit"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	EventCancellationEnt struct {
+		Id            int64     `db:"id"`
+		EventType     string    `db:"event_type"`
+		EventId       int64     `db:"event_id"`
+		EventName     string    `db:"event_name"`
+		EventImageUrl string    `db:"event_image_url"`
+		Reason        string    `db:"reason"`
+		CancelledBy   string    `db:"cancelled_by"`
+		CreatedDate   time.Time `db:"created_date"`
+	}
+
+	// EventCancellationParticipantEnt is a member booked on a cancelled event
+	// and what happened to their booking.
+	EventCancellationParticipantEnt struct {
+		UserId            int64     `db:"user_id"`
+		UserCode          string    `db:"user_code"`
+		UserName          string    `db:"user_name"`
+		UserEmail         string    `db:"user_email"`
+		UserXPlayer       string    `db:"user_x_player"`
+		ParticipantStatus string    `db:"participant_status"`
+		TransactionCode   string    `db:"transaction_code"`
+		AggregatorCode    string    `db:"aggregator_code"`
+		TransactionStatus string    `db:"transaction_status"`
+		Amount            int64     `db:"amount"`
+		RefundStatus      string    `db:"refund_status"`
+		RefundId          string    `db:"refund_id"`
+		RefundError       string    `db:"refund_error"`
+		ReversedPoint     int       `db:"reversed_point"`
+		PushSent          bool      `db:"push_sent"`
+		EmailSent         bool      `db:"email_sent"`
+		CreatedDate       time.Time `db:"created_date"`
+	}
+)
+
+// CancelEvent cancels a room or tournament. The bookings are cancelled, the
+// VP earned from the event is taken back and every booked member is recorded
+// with a pending refund in one transaction. SettleEventCancellations then
+// refunds the paid bookings through the payment gateway and notifies the
+// members, retrying the refunds that fail.
+func (c *Contract) CancelEvent(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64, eventCode string, reason, cancelledBy string) (EventCancellationEnt, []EventCancellationParticipantEnt, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return EventCancellationEnt{}, nil, c.errHandler("model.CancelEvent", err, utils.ErrCancellingEvent)
+	}
+	defer tx.Rollback(ctx)
+
+	participants, err := c.GetCancellableParticipantsTrx(tx, ctx, eventType, eventId)
+	if err != nil {
+		return EventCancellationEnt{}, nil, err
+	}
+
+	cancellation, err := c.CancelEventTrx(tx, ctx, eventType, eventId, reason, cancelledBy)
+	if err != nil {
+		return cancellation, nil, err
+	}
+
+	for i := range participants {
+		participants[i].ReversedPoint, err = c.ReverseEventPointTrx(tx, ctx, eventType, eventCode, participants[i].UserId)
+		if err != nil {
+			return cancellation, nil, err
+		}
+
+		participants[i].RefundStatus = utils.RefundPending
+		if err = c.AddEventCancellationParticipantTrx(tx, ctx, cancellation.Id, participants[i]); err != nil {
+			return cancellation, nil, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return cancellation, nil, c.errHandler("model.CancelEvent", err, utils.ErrCancellingEvent)
+	}
+
+	return cancellation, participants, nil
+}
+
+// GetCancellableParticipantsTrx lists and locks the members holding a seat of
+// an event, with the transaction of their booking.
+func (c *Contract) GetCancellableParticipantsTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId int64) ([]EventCancellationParticipantEnt, error) {
+	var list []EventCancellationParticipantEnt
+
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return list, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `
+		SELECT
+			p.user_id, u.user_code, COALESCE(u.fullname, '') AS user_name, u.email AS user_email, COALESCE(u.x_player, '') AS user_x_player,
+			p.status, COALESCE(p.transaction_code, '') AS transaction_code,
+			COALESCE(ut.aggregator_code, '') AS aggregator_code, COALESCE(ut.status, '') AS transaction_status, COALESCE(ut.price, 0) AS amount
+		FROM ` + table.participants + ` p
+			JOIN users u ON u.id = p.user_id
+			LEFT JOIN users_transactions ut ON ut.transaction_code = p.transaction_code
+		WHERE p.` + table.participantEvent + ` = $1 AND p.status IN ('active', 'pending')
+		ORDER BY p.id
+		FOR UPDATE OF p`
+	rows, err := tx.Query(ctx, query, eventId)
+	if err != nil {
+		return list, c.errHandler("model.GetCancellableParticipantsTrx", err, utils.ErrCancellingEvent)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data EventCancellationParticipantEnt
+		err = rows.Scan(
+			&data.UserId, &data.UserCode, &data.UserName, &data.UserEmail, &data.UserXPlayer,
+			&data.ParticipantStatus, &data.TransactionCode,
+			&data.AggregatorCode, &data.TransactionStatus, &data.Amount,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetCancellableParticipantsTrx", err, utils.ErrCancellingEvent)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// CancelEventTrx sets an event to cancelled, cancels its bookings, closes its
+// waitlist, frees its table and records the cancellation. It fails when the
+// event is already closed or cancelled.
+func (c *Contract) CancelEventTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId int64, reason, adminCode string) (EventCancellationEnt, error) {
+	var (
+		now  = time.Now().UTC()
+		data = EventCancellationEnt{EventType: eventType, EventId: eventId, Reason: reason, CancelledBy: adminCode}
+	)
+
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return data, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	// The table of the event is freed for other events
+	query := `
+		UPDATE ` + table.event + ` SET status = $1, table_id = NULL, updated_date = $2
+		WHERE id = $3 AND status NOT IN ('closed', 'cancelled')
+		RETURNING COALESCE(name, ''), COALESCE(image_url, '')`
+	err := tx.QueryRow(ctx, query, utils.RoomStatus["CANCELLED"], now, eventId).Scan(&data.EventName, &data.EventImageUrl)
+	if err == pgx.ErrNoRows {
+		return data, errors.New(utils.ErrEventNotCancellable)
+	}
+	if err != nil {
+		return data, c.errHandler("model.CancelEventTrx", err, utils.ErrCancellingEvent)
+	}
+
+	query = `UPDATE ` + table.participants + ` SET status = 'cancel', updated_date = $1 WHERE ` + table.participantEvent + ` = $2 AND status IN ('active', 'pending')`
+	if _, err = tx.Exec(ctx, query, now, eventId); err != nil {
+		return data, c.errHandler("model.CancelEventTrx", err, utils.ErrCancellingEvent)
+	}
+
+	query = `UPDATE event_waitlists SET status = $1, updated_date = $2 WHERE event_type = $3 AND event_id = $4 AND status IN ('waiting', 'offered')`
+	if _, err = tx.Exec(ctx, query, utils.WaitlistExpired, now, eventType, eventId); err != nil {
+		return data, c.errHandler("model.CancelEventTrx", err, utils.ErrCancellingEvent)
+	}
+
+	query = `
+		INSERT INTO event_cancellations (event_type, event_id, reason, cancelled_by, created_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_date`
+	err = tx.QueryRow(ctx, query, eventType, eventId, reason, adminCode, now).Scan(&data.Id, &data.CreatedDate)
+	if err != nil {
+		return data, c.errHandler("model.CancelEventTrx", err, utils.ErrCancellingEvent)
+	}
+
+	return data, nil
+}
+
+// ReverseEventPointTrx takes back the VP a member earned from an event and
+// returns how many points were taken.
+func (c *Contract) ReverseEventPointTrx(tx pgx.Tx, ctx context.Context, eventType, eventCode string, userId int64) (int, error) {
+	var point int
+
+	query := `SELECT COALESCE(SUM(point), 0) FROM users_points WHERE user_id = $1 AND data_source = $2 AND source_code = $3`
+	err := tx.QueryRow(ctx, query, userId, eventType, eventCode).Scan(&point)
+	if err != nil {
+		return 0, c.errHandler("model.ReverseEventPointTrx", err, utils.ErrReversingEventPoint)
+	}
+
+	if point <= 0 {
+		return 0, nil
+	}
+
+	if err = c.AddUserPoint(tx, ctx, userId, eventType, eventCode, -point); err != nil {
+		return 0, err
+	}
+
+	return point, nil
+}
+
+// LockBookingTrx locks the booking of a member and the event it is for, in the
+// order CancelEvent takes them, and returns the status of the event. A payment
+// confirmed while they are locked is settled either before the event is
+// cancelled, or after it and then refunded.
+func (c *Contract) LockBookingTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64) (string, error) {
+	var status string
+
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return status, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `SELECT id FROM ` + table.participants + ` WHERE ` + table.participantEvent + ` = $1 AND user_id = $2 FOR UPDATE`
+	_, err := tx.Exec(ctx, query, eventId, userId)
+	if err != nil {
+		return status, c.errHandler("model.LockBookingTrx", err, utils.ErrLockingBooking)
+	}
+
+	query = `SELECT status FROM ` + table.event + ` WHERE id = $1 FOR SHARE`
+	err = tx.QueryRow(ctx, query, eventId).Scan(&status)
+	if err != nil {
+		return status, c.errHandler("model.LockBookingTrx", err, utils.ErrLockingBooking)
+	}
+
+	return status, nil
+}
+
+// RefundCancelledBookingTrx records a booking paid after its event was
+// cancelled with a pending refund, so SettleEventCancellations refunds it and
+// tells the member. A booking whose refund is already on its way is left as it
+// is.
+func (c *Contract) RefundCancelledBookingTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64, transactionCode string, amount int64) error {
+	query := `
+		INSERT INTO event_cancellation_participants (
+			cancellation_id, user_id, participant_status, transaction_code, amount,
+			refund_status, reversed_point, created_date
+		)
+		SELECT id, $3, 'pending', $4, $5, $6, 0, $7
+		FROM event_cancellations
+		WHERE event_type = $1 AND event_id = $2
+		ON CONFLICT (cancellation_id, user_id) DO UPDATE SET
+			transaction_code = EXCLUDED.transaction_code, amount = EXCLUDED.amount,
+			refund_status = EXCLUDED.refund_status, refund_id = '', refund_error = '',
+			push_sent = false, email_sent = false, settle_attempts = 0, notified_date = NULL, settled_date = NULL
+		WHERE event_cancellation_participants.refund_status IN ($8, $9, $10)`
+	_, err := tx.Exec(ctx, query,
+		eventType, eventId, userId, transactionCode, amount, utils.RefundPending, time.Now().UTC(),
+		utils.RefundInvoiceExpired, utils.RefundExpireFailed, utils.RefundNoPayment,
+	)
+	if err != nil {
+		return c.errHandler("model.RefundCancelledBookingTrx", err, utils.ErrAddingEventCancellation)
+	}
+
+	return nil
+}
+
+// SettleCancelledBooking refunds a paid booking of a cancelled event through
+// the payment gateway, or expires its invoice when it is still unpaid. The
+// outcome is set on data.
+func (c *Contract) SettleCancelledBooking(tx pgx.Tx, ctx context.Context, data *EventCancellationParticipantEnt) {
+	xendit := payment.XenditClient{Key: c.Config.GetString("xendit.api_key")}
+
+	switch data.TransactionStatus {
+	case utils.PaymentStatus["PAID"], utils.PaymentStatus["SETTLED"]:
+		refund, errX := xendit.CreateRefund(data.AggregatorCode, data.TransactionCode, float64(data.Amount), utils.RefundReason)
+		if errX != nil {
+			data.RefundStatus = utils.RefundFailed
+			data.RefundError = errX.Error()
+			return
+		}
+
+		data.RefundStatus = utils.RefundRefunded
+		data.RefundId = refund.GetId()
+
+		query := `UPDATE users_transactions SET status = $1, updated_date = $2 WHERE transaction_code = $3`
+		_, err := tx.Exec(ctx, query, utils.PaymentStatus["REFUNDED"], time.Now().UTC(), data.TransactionCode)
+		if err != nil {
+			log.Printf("Error : %s", c.errHandler("model.SettleCancelledBooking", err, utils.ErrRefundingTransaction))
+		}
+
+	case utils.PaymentStatus["PENDING"]:
+		if err := c.ExpireInvoice(data.AggregatorCode); err != nil {
+			data.RefundStatus = utils.RefundExpireFailed
+			data.RefundError = err.Error()
+			return
+		}
+		data.RefundStatus = utils.RefundInvoiceExpired
+
+	default:
+		data.RefundStatus = utils.RefundNoPayment
+	}
+}
+
+// NotifyEventCancelled tells a member their event was cancelled, in the app,
+// by push notification and by email.
+func (c *Contract) NotifyEventCancelled(db *pgxpool.Pool, ctx context.Context, event EventCancellationEnt, eventCode string, eventDate time.Time, data *EventCancellationParticipantEnt) {
+	var (
+		date   = eventDate.Format(utils.DATE_FORMAT)
+		refund string
+	)
+
+	switch data.RefundStatus {
+	case utils.RefundRefunded:
+		refund = fmt.Sprintf(utils.CanceledRoomRefundDescription, data.Amount)
+	case utils.RefundFailed:
+		refund = utils.CanceledRoomRefundFailed
+	case utils.RefundInvoiceExpired:
+		refund = utils.CanceledRoomInvoiceExpired
+	}
+	description := fmt.Sprintf(utils.CanceledRoomDescription, event.EventName, date, refund)
+
+	descriptionJSON, err := json.Marshal(description)
+	if err != nil {
+		log.Printf("Error : %s", err)
+		return
+	}
+
+	err = c.AddNotification(db, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "user", data.UserCode, eventCode, utils.CanceledRoom, utils.CanceledRoomTitle, descriptionJSON, event.EventImageUrl)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	}
+
+	_, err = onesignal.New(c.App).CreateOSNotifications(data.UserXPlayer, utils.CanceledRoomTitle, description, event.EventType)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	} else {
+		data.PushSent = true
+	}
+
+	err = mail.New(c.App).SendMail(mail.EventCancelled, mail.MailSubj[mail.EventCancelled], data.UserEmail, mail.EventCancelledData{
+		Name:         data.UserName,
+		EventName:    event.EventName,
+		EventDate:    date,
+		Reason:       event.Reason,
+		RefundStatus: data.RefundStatus,
+		Amount:       data.Amount,
+	})
+	if err != nil {
+		log.Printf("Error : %s", err)
+	} else {
+		data.EmailSent = true
+	}
+}
+
+func (c *Contract) AddEventCancellationParticipantTrx(tx pgx.Tx, ctx context.Context, cancellationId int64, data EventCancellationParticipantEnt) error {
+	query := `
+		INSERT INTO event_cancellation_participants (
+			cancellation_id, user_id, participant_status, transaction_code, amount,
+			refund_status, reversed_point, created_date
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.Exec(ctx, query,
+		cancellationId, data.UserId, data.ParticipantStatus, data.TransactionCode, data.Amount,
+		data.RefundStatus, data.ReversedPoint, time.Now().UTC(),
+	)
+	if err != nil {
+		return c.errHandler("model.AddEventCancellationParticipantTrx", err, utils.ErrAddingEventCancellation)
+	}
+
+	return nil
+}
+
+// SettleEventCancellations settles the bookings of cancelled events still
+// pending, and retries the failed refunds and invoice expiries up to
+// utils.RefundMaxAttempts times. Each booking is settled in a transaction of
+// its own that locks its report row, so a crash leaves it to the next run and
+// concurrent runs skip it. A member is notified once, after the first attempt.
+// It returns how many bookings were settled.
+func (c *Contract) SettleEventCancellations(db *pgxpool.Pool, ctx context.Context) (int, error) {
+	var (
+		total int
+		done  = []int64{}
+	)
+
+	for {
+		id, settled, err := c.settleNextEventCancellation(db, ctx, done)
+		if err != nil {
+			return total, err
+		}
+		if id == 0 {
+			return total, nil
+		}
+
+		done = append(done, id)
+		if settled {
+			total++
+		}
+	}
+}
+
+// settleNextEventCancellation settles the first booking to settle that is not
+// in done. It returns the ID of its report row, 0 when there is none left, and
+// whether the booking is settled for good.
+func (c *Contract) settleNextEventCancellation(db *pgxpool.Pool, ctx context.Context, done []int64) (int64, bool, error) {
+	var (
+		id       int64
+		notified bool
+		event    EventCancellationEnt
+		code     string
+		date     time.Time
+		data     EventCancellationParticipantEnt
+	)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, false, c.errHandler("model.SettleEventCancellations", err, utils.ErrSettlingEventCancellation)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT
+			ecp.id, ec.id, ec.event_type, ec.event_id, ec.reason,
+			COALESCE(r.room_code, t.tournament_code, '') AS event_code,
+			COALESCE(r.name, t.name, '') AS event_name,
+			COALESCE(r.image_url, t.image_url, '') AS event_image_url,
+			COALESCE(r.start_date, t.start_date) AS event_date,
+			ecp.user_id, u.user_code, COALESCE(u.fullname, '') AS user_name, u.email AS user_email, COALESCE(u.x_player, '') AS user_x_player,
+			ecp.participant_status, ecp.transaction_code,
+			COALESCE(ut.aggregator_code, '') AS aggregator_code, COALESCE(ut.status, '') AS transaction_status, ecp.amount,
+			ecp.reversed_point, ecp.push_sent, ecp.email_sent, ecp.notified_date IS NOT NULL AS notified
+		FROM event_cancellation_participants ecp
+			JOIN event_cancellations ec ON ec.id = ecp.cancellation_id
+			JOIN users u ON u.id = ecp.user_id
+			LEFT JOIN users_transactions ut ON ecp.transaction_code != '' AND ut.transaction_code = ecp.transaction_code
+			LEFT JOIN rooms r ON ec.event_type = 'room' AND r.id = ec.event_id
+			LEFT JOIN tournaments t ON ec.event_type = 'tournament' AND t.id = ec.event_id
+		WHERE ecp.refund_status IN ($1, $2, $3) AND ecp.settle_attempts < $4 AND NOT (ecp.id = ANY($5))
+		ORDER BY ecp.id
+		LIMIT 1
+		FOR UPDATE OF ecp SKIP LOCKED`
+	err = tx.QueryRow(ctx, query, utils.RefundPending, utils.RefundFailed, utils.RefundExpireFailed, utils.RefundMaxAttempts, done).Scan(
+		&id, &event.Id, &event.EventType, &event.EventId, &event.Reason,
+		&code, &event.EventName, &event.EventImageUrl, &date,
+		&data.UserId, &data.UserCode, &data.UserName, &data.UserEmail, &data.UserXPlayer,
+		&data.ParticipantStatus, &data.TransactionCode,
+		&data.AggregatorCode, &data.TransactionStatus, &data.Amount,
+		&data.ReversedPoint, &data.PushSent, &data.EmailSent, &notified,
+	)
+	if err == pgx.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, c.errHandler("model.SettleEventCancellations", err, utils.ErrSettlingEventCancellation)
+	}
+
+	c.SettleCancelledBooking(tx, ctx, &data)
+	if !notified {
+		c.NotifyEventCancelled(db, ctx, event, code, date, &data)
+	}
+
+	settled := data.RefundStatus != utils.RefundFailed && data.RefundStatus != utils.RefundExpireFailed
+	query = `
+		UPDATE event_cancellation_participants SET
+			refund_status = $1, refund_id = $2, refund_error = $3, push_sent = $4, email_sent = $5,
+			settle_attempts = settle_attempts + 1,
+			notified_date = COALESCE(notified_date, $6),
+			settled_date = CASE WHEN $7 THEN $6 END
+		WHERE id = $8`
+	_, err = tx.Exec(ctx, query, data.RefundStatus, data.RefundId, data.RefundError, data.PushSent, data.EmailSent, time.Now().UTC(), settled, id)
+	if err != nil {
+		return 0, false, c.errHandler("model.SettleEventCancellations", err, utils.ErrSettlingEventCancellation)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, false, c.errHandler("model.SettleEventCancellations", err, utils.ErrSettlingEventCancellation)
+	}
+
+	return id, settled, nil
+}
+
+func (c *Contract) GetEventCancellation(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) (EventCancellationEnt, error) {
+	var data EventCancellationEnt
+
+	query := `
+		SELECT id, event_type, event_id, reason, cancelled_by, created_date
+		FROM event_cancellations
+		WHERE event_type = $1 AND event_id = $2`
+	err := db.QueryRow(ctx, query, eventType, eventId).Scan(
+		&data.Id, &data.EventType, &data.EventId, &data.Reason, &data.CancelledBy, &data.CreatedDate,
+	)
+	if err != nil {
+		return data, c.errHandler("model.GetEventCancellation", err, utils.ErrGettingEventCancellation)
+	}
+
+	return data, nil
+}
+
+func (c *Contract) GetEventCancellationParticipants(db *pgxpool.Pool, ctx context.Context, cancellationId int64) ([]EventCancellationParticipantEnt, error) {
+	var list []EventCancellationParticipantEnt
+
+	query := `
+		SELECT
+			ecp.user_id, u.user_code, COALESCE(u.fullname, '') AS user_name,
+			ecp.participant_status, ecp.transaction_code, ecp.amount,
+			ecp.refund_status, ecp.refund_id, ecp.refund_error, ecp.reversed_point,
+			ecp.push_sent, ecp.email_sent, ecp.created_date
+		FROM event_cancellation_participants ecp
+			JOIN users u ON u.id = ecp.user_id
+		WHERE ecp.cancellation_id = $1
+		ORDER BY ecp.id`
+	rows, err := db.Query(ctx, query, cancellationId)
+	if err != nil {
+		return list, c.errHandler("model.GetEventCancellationParticipants", err, utils.ErrGettingEventCancellation)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data EventCancellationParticipantEnt
+		err = rows.Scan(
+			&data.UserId, &data.UserCode, &data.UserName,
+			&data.ParticipantStatus, &data.TransactionCode, &data.Amount,
+			&data.RefundStatus, &data.RefundId, &data.RefundError, &data.ReversedPoint,
+			&data.PushSent, &data.EmailSent, &data.CreatedDate,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetEventCancellationParticipants", err, utils.ErrGettingEventCancellation)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// TestPaidAfterCancelEventIsRefunded pays the booking of a room at different
+// points of its cancellation, the way the payment callback does, and checks
+// the booking is only confirmed when the room was not cancelled yet, and
+// otherwise left cancelled with one pending refund.
+func TestPaidAfterCancelEventIsRefunded(t *testing.T) {
+	ctx := context.Background()
+	c, db := testContract(t, ctx)
+
+	tests := []struct {
+		name string
+		// before runs ahead of the payment, the cases that cancel the room
+		// do it there
+		before        func(t *testing.T, roomId, userId int64)
+		bookingStatus string
+		wantCancelled bool
+	}{
+		{
+			name:          "paid before the cancellation",
+			bookingStatus: "pending",
+		},
+		{
+			name:          "paid before the refund is settled",
+			bookingStatus: "pending",
+			before: func(t *testing.T, roomId, userId int64) {
+				cancelTestRoom(t, ctx, c, db, roomId)
+			},
+			wantCancelled: true,
+		},
+		{
+			name:          "paid after the invoice expired",
+			bookingStatus: "pending",
+			before: func(t *testing.T, roomId, userId int64) {
+				cancelTestRoom(t, ctx, c, db, roomId)
+				_, err := db.Exec(ctx, `
+					UPDATE event_cancellation_participants
+					SET refund_status = $1, settle_attempts = 1, notified_date = NOW(), settled_date = NOW()
+					WHERE user_id = $2`,
+					utils.RefundInvoiceExpired, userId)
+				if err != nil {
+					t.Fatalf("settle refund: %v", err)
+				}
+			},
+			wantCancelled: true,
+		},
+		{
+			name:          "paid after the booking was released",
+			bookingStatus: "cancel",
+			before: func(t *testing.T, roomId, userId int64) {
+				cancelTestRoom(t, ctx, c, db, roomId)
+			},
+			wantCancelled: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				code   = fmt.Sprintf("TRX%d", i)
+				userId = testUser(t, ctx, db, i+1)
+				roomId = testRoom(t, ctx, db, fmt.Sprintf("ROOM%d", i), 5)
+			)
+
+			_, err := db.Exec(ctx, `
+				INSERT INTO users_transactions (user_id, data_source, source_code, transaction_code, aggregator_code, price, status)
+				VALUES ($1, 'room', $2, $3, $3, 50000, 'PENDING')`,
+				userId, fmt.Sprintf("ROOM%d", i), code)
+			if err != nil {
+				t.Fatalf("insert transaction: %v", err)
+			}
+			_, err = db.Exec(ctx, `INSERT INTO rooms_participants (room_id, user_id, status, transaction_code) VALUES ($1, $2, $3, $4)`,
+				roomId, userId, tt.bookingStatus, code)
+			if err != nil {
+				t.Fatalf("insert participant: %v", err)
+			}
+
+			if tt.before != nil {
+				tt.before(t, roomId, userId)
+			}
+
+			tx, err := db.Begin(ctx)
+			if err != nil {
+				t.Fatalf("begin: %v", err)
+			}
+			defer tx.Rollback(ctx)
+
+			if _, err = tx.Exec(ctx, `UPDATE users_transactions SET status = 'PAID' WHERE transaction_code = $1`, code); err != nil {
+				t.Fatalf("pay transaction: %v", err)
+			}
+
+			status, err := c.LockBookingTrx(tx, ctx, utils.WaitlistRoom, roomId, userId)
+			if err != nil {
+				t.Fatalf("LockBookingTrx: %v", err)
+			}
+			if cancelled := status == utils.RoomStatus["CANCELLED"]; cancelled != tt.wantCancelled {
+				t.Fatalf("room status %q, want cancelled %v", status, tt.wantCancelled)
+			}
+			if !tt.wantCancelled {
+				return
+			}
+
+			if err = c.RefundCancelledBookingTrx(tx, ctx, utils.WaitlistRoom, roomId, userId, code, 50000); err != nil {
+				t.Fatalf("RefundCancelledBookingTrx: %v", err)
+			}
+			if err = tx.Commit(ctx); err != nil {
+				t.Fatalf("commit: %v", err)
+			}
+
+			var (
+				bookingStatus, refundStatus, transactionCode string
+				amount                                       int64
+				attempts, refunds                            int
+				notified, settled                            bool
+			)
+			err = db.QueryRow(ctx, `SELECT status FROM rooms_participants WHERE room_id = $1 AND user_id = $2`, roomId, userId).Scan(&bookingStatus)
+			if err != nil {
+				t.Fatalf("get participant: %v", err)
+			}
+			if bookingStatus != "cancel" {
+				t.Errorf("booking status %q, want cancel", bookingStatus)
+			}
+
+			err = db.QueryRow(ctx, `
+				SELECT COUNT(*) OVER (), ecp.refund_status, ecp.transaction_code, ecp.amount, ecp.settle_attempts,
+					ecp.notified_date IS NOT NULL, ecp.settled_date IS NOT NULL
+				FROM event_cancellation_participants ecp
+					JOIN event_cancellations ec ON ec.id = ecp.cancellation_id
+				WHERE ec.event_type = 'room' AND ec.event_id = $1 AND ecp.user_id = $2`, roomId, userId,
+			).Scan(&refunds, &refundStatus, &transactionCode, &amount, &attempts, &notified, &settled)
+			if err != nil {
+				t.Fatalf("get refund: %v", err)
+			}
+			if refunds != 1 || refundStatus != utils.RefundPending || transactionCode != code || amount != 50000 {
+				t.Errorf("%d refunds %q of %s for %d, want 1 %q of %s for 50000", refunds, refundStatus, transactionCode, amount, utils.RefundPending, code)
+			}
+			if attempts != 0 || notified || settled {
+				t.Errorf("refund attempted %d times, notified %v and settled %v, want a refund still to settle", attempts, notified, settled)
+			}
+		})
+	}
+}
+
+// cancelTestRoom cancels a room through CancelEvent.
+func cancelTestRoom(t *testing.T, ctx context.Context, c Contract, db *pgxpool.Pool, roomId int64) {
+	t.Helper()
+
+	var code string
+	if err := db.QueryRow(ctx, `SELECT room_code FROM rooms WHERE id = $1`, roomId).Scan(&code); err != nil {
+		t.Fatalf("get room: %v", err)
+	}
+
+	if _, _, err := c.CancelEvent(db, ctx, utils.WaitlistRoom, roomId, code, "test", "ADM1"); err != nil {
+		t.Fatalf("CancelEvent: %v", err)
+	}
+}
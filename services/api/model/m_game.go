@@ -364,7 +364,7 @@ func (c *Contract) GetGameByCode(db *pgxpool.Pool, ctx context.Context, code str
 				)
 			)) AS room_available_list
 			FROM rooms r
-			WHERE r.status = 'open'
+			WHERE r.status = 'open' AND r.visibility = 'public'
 			GROUP BY r.game_id
 		) AS game_room_available ON game_room_available.game_id = games.id
 		WHERE games.game_code = $1 AND games.deleted_date IS NULL`
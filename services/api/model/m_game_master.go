@@ -0,0 +1,329 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	// GameMasterSlotEnt is the time a game master would host a room. Start and
+	// End are the WIB wall clock, in UTC like every event date. Rooms with
+	// ExcludeRoomCode or of ExcludeSeriesId are not conflicts, e.g. the room
+	// being updated.
+	GameMasterSlotEnt struct {
+		GameMasterId    int64
+		Start           time.Time
+		End             time.Time
+		ExcludeRoomCode string
+		ExcludeSeriesId int64
+	}
+
+	GameMasterAvailabilityEnt struct {
+		Weekday   int       `db:"weekday"`
+		StartTime time.Time `db:"start_time"`
+		EndTime   time.Time `db:"end_time"`
+	}
+
+	GameMasterDayOffEnt struct {
+		OffDate time.Time `db:"off_date"`
+		Reason  string    `db:"reason"`
+	}
+
+	// GameMasterSessionEnt is a room hosted by a game master.
+	GameMasterSessionEnt struct {
+		RoomCode     string       `db:"room_code"`
+		Name         string       `db:"name"`
+		GameName     string       `db:"game_name"`
+		StartDate    sql.NullTime `db:"start_date"`
+		EndDate      sql.NullTime `db:"end_date"`
+		StartTime    time.Time    `db:"start_time"`
+		EndTime      time.Time    `db:"end_time"`
+		Status       string       `db:"status"`
+		Participants int          `db:"participants"`
+	}
+
+	// GameMasterWorkloadEnt is the rooms a game master hosts in a week starting
+	// on monday.
+	GameMasterWorkloadEnt struct {
+		AdminCode    string    `db:"admin_code"`
+		AdminName    string    `db:"admin_name"`
+		WeekStart    time.Time `db:"week_start"`
+		Sessions     int       `db:"sessions"`
+		Hours        float64   `db:"hours"`
+		Participants int       `db:"participants"`
+	}
+)
+
+// eventStart and eventEnd are the WIB wall clock a room or tournament r starts
+// and ends at. An event without times lasts the whole day.
+const (
+	eventStart = `(r.start_date + COALESCE(r.start_time, '00:00'::time))`
+	eventEnd   = `(COALESCE(r.end_date, r.start_date) + COALESCE(r.end_time, '23:59:59'::time))`
+)
+
+// LockGameMasterTrx locks a game master until tx ends, so the schedule of the
+// game master is checked and a room written for them by one request at a
+// time. CheckGameMasterSchedule and the write of the room follow the lock.
+// The row is locked FOR NO KEY UPDATE, so rooms referencing it can still be
+// written through other connections while tx is open.
+func (c *Contract) LockGameMasterTrx(tx pgx.Tx, ctx context.Context, gameMasterId int64) error {
+	_, err := tx.Exec(ctx, `SELECT id FROM admins WHERE id = $1 FOR NO KEY UPDATE`, gameMasterId)
+	if err != nil {
+		return c.errHandler("model.LockGameMasterTrx", err, utils.ErrCheckingGameMasterSchedule)
+	}
+
+	return nil
+}
+
+// CheckGameMasterSchedule checks that a game master can host a room in slot:
+// they are not off on any of its days, it fits their weekly availability if
+// they have one and they host no other room at the same time. Cancelled rooms
+// and the rooms of slot.ExcludeSeriesId that follow the series are not in the
+// way. The error names what is in the way.
+func (c *Contract) CheckGameMasterSchedule(db *pgxpool.Pool, ctx context.Context, slot GameMasterSlotEnt) error {
+	var (
+		startDay = time.Date(slot.Start.Year(), slot.Start.Month(), slot.Start.Day(), 0, 0, 0, 0, time.UTC)
+		endDay   = time.Date(slot.End.Year(), slot.End.Month(), slot.End.Day(), 0, 0, 0, 0, time.UTC)
+		offDate  time.Time
+	)
+
+	err := db.QueryRow(ctx, `
+		SELECT off_date FROM game_master_days_off
+		WHERE admin_id = $1 AND off_date BETWEEN $2 AND $3
+		ORDER BY off_date LIMIT 1`, slot.GameMasterId, startDay, endDay).Scan(&offDate)
+	if err == nil {
+		return fmt.Errorf(utils.ErrGameMasterDayOff, offDate.Format(utils.DATE_FORMAT))
+	}
+	if err != pgx.ErrNoRows {
+		return c.errHandler("model.CheckGameMasterSchedule", err, utils.ErrCheckingGameMasterSchedule)
+	}
+
+	availabilities, err := c.GetGameMasterAvailabilities(db, ctx, slot.GameMasterId)
+	if err != nil {
+		return err
+	}
+	if len(availabilities) > 0 {
+		for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+			from, to := time.Duration(0), 24*time.Hour-time.Second
+			if day.Equal(startDay) {
+				from = slot.Start.Sub(startDay)
+			}
+			if day.Equal(endDay) {
+				to = slot.End.Sub(endDay)
+			}
+
+			if !fitsAvailability(availabilities, day.Weekday(), from, to) {
+				return fmt.Errorf(utils.ErrGameMasterUnavailable, day.Format(utils.DATE_FORMAT), day.Add(from).Format("15:04"), day.Add(to).Format("15:04"))
+			}
+		}
+	}
+
+	var (
+		roomCode, name string
+		start, end     time.Time
+	)
+	err = db.QueryRow(ctx, `
+		SELECT r.room_code, r.name, `+eventStart+`, `+eventEnd+`
+		FROM rooms r
+		WHERE r.game_master_id = $1 AND r.deleted_date IS NULL AND r.status != 'cancelled' AND r.room_code != $2
+			AND ($3::bigint = 0 OR r.series_id IS DISTINCT FROM $3 OR r.is_detached)
+			AND `+eventStart+` < $5::timestamp AND $4::timestamp < `+eventEnd+`
+		ORDER BY `+eventStart+` LIMIT 1`,
+		slot.GameMasterId, slot.ExcludeRoomCode, slot.ExcludeSeriesId, slot.Start, slot.End,
+	).Scan(&roomCode, &name, &start, &end)
+	if err == nil {
+		return fmt.Errorf(utils.ErrGameMasterRoomConflict, roomCode, name, start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+	}
+	if err != pgx.ErrNoRows {
+		return c.errHandler("model.CheckGameMasterSchedule", err, utils.ErrCheckingGameMasterSchedule)
+	}
+
+	return nil
+}
+
+// fitsAvailability reports whether one availability window of weekday covers
+// from to to, as durations since midnight.
+func fitsAvailability(list []GameMasterAvailabilityEnt, weekday time.Weekday, from, to time.Duration) bool {
+	for _, v := range list {
+		if v.Weekday != int(weekday) {
+			continue
+		}
+
+		start := time.Duration(v.StartTime.Hour())*time.Hour + time.Duration(v.StartTime.Minute())*time.Minute + time.Duration(v.StartTime.Second())*time.Second
+		end := time.Duration(v.EndTime.Hour())*time.Hour + time.Duration(v.EndTime.Minute())*time.Minute + time.Duration(v.EndTime.Second())*time.Second
+		if start <= from && to <= end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetGameMasterAvailabilities returns the weekly availability of a game master.
+func (c *Contract) GetGameMasterAvailabilities(db *pgxpool.Pool, ctx context.Context, adminId int64) ([]GameMasterAvailabilityEnt, error) {
+	var list []GameMasterAvailabilityEnt
+
+	rows, err := db.Query(ctx, `
+		SELECT weekday, start_time, end_time FROM game_master_availabilities
+		WHERE admin_id = $1 ORDER BY weekday, start_time`, adminId)
+	if err != nil {
+		return nil, c.errHandler("model.GetGameMasterAvailabilities", err, utils.ErrGettingGameMasterAvailability)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data GameMasterAvailabilityEnt
+		if err = rows.Scan(&data.Weekday, &data.StartTime, &data.EndTime); err != nil {
+			return nil, c.errHandler("model.GetGameMasterAvailabilities", err, utils.ErrGettingGameMasterAvailability)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// ReplaceGameMasterAvailabilitiesTrx replaces the weekly availability of a
+// game master. An empty list makes them available every day.
+func (c *Contract) ReplaceGameMasterAvailabilitiesTrx(tx pgx.Tx, ctx context.Context, adminId int64, list []GameMasterAvailabilityEnt) error {
+	_, err := tx.Exec(ctx, `DELETE FROM game_master_availabilities WHERE admin_id = $1`, adminId)
+	if err != nil {
+		return c.errHandler("model.ReplaceGameMasterAvailabilitiesTrx", err, utils.ErrUpdatingGameMasterAvailability)
+	}
+
+	for _, v := range list {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO game_master_availabilities (admin_id, weekday, start_time, end_time)
+			VALUES ($1, $2, $3, $4)`, adminId, v.Weekday, v.StartTime, v.EndTime)
+		if err != nil {
+			return c.errHandler("model.ReplaceGameMasterAvailabilitiesTrx", err, utils.ErrUpdatingGameMasterAvailability)
+		}
+	}
+
+	return nil
+}
+
+// GetGameMasterDaysOff returns the days off of a game master between from and
+// to, both included.
+func (c *Contract) GetGameMasterDaysOff(db *pgxpool.Pool, ctx context.Context, adminId int64, from, to time.Time) ([]GameMasterDayOffEnt, error) {
+	var list []GameMasterDayOffEnt
+
+	rows, err := db.Query(ctx, `
+		SELECT off_date, reason FROM game_master_days_off
+		WHERE admin_id = $1 AND off_date BETWEEN $2 AND $3 ORDER BY off_date`, adminId, from, to)
+	if err != nil {
+		return nil, c.errHandler("model.GetGameMasterDaysOff", err, utils.ErrGettingGameMasterDaysOff)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data GameMasterDayOffEnt
+		if err = rows.Scan(&data.OffDate, &data.Reason); err != nil {
+			return nil, c.errHandler("model.GetGameMasterDaysOff", err, utils.ErrGettingGameMasterDaysOff)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// AddGameMasterDayOff marks a day off for a game master, or updates its reason.
+func (c *Contract) AddGameMasterDayOff(db *pgxpool.Pool, ctx context.Context, adminId int64, date time.Time, reason string) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO game_master_days_off (admin_id, off_date, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (admin_id, off_date) DO UPDATE SET reason = EXCLUDED.reason`, adminId, date, reason)
+	if err != nil {
+		return c.errHandler("model.AddGameMasterDayOff", err, utils.ErrAddingGameMasterDayOff)
+	}
+
+	return nil
+}
+
+// DeleteGameMasterDayOff removes a day off of a game master.
+func (c *Contract) DeleteGameMasterDayOff(db *pgxpool.Pool, ctx context.Context, adminId int64, date time.Time) error {
+	tag, err := db.Exec(ctx, `DELETE FROM game_master_days_off WHERE admin_id = $1 AND off_date = $2`, adminId, date)
+	if err != nil {
+		return c.errHandler("model.DeleteGameMasterDayOff", err, utils.ErrDeletingGameMasterDayOff)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrGameMasterDayOffNotFound)
+	}
+
+	return nil
+}
+
+// GetGameMasterSessions returns the rooms a game master hosts between from and
+// to, both included, in order.
+func (c *Contract) GetGameMasterSessions(db *pgxpool.Pool, ctx context.Context, adminId int64, from, to time.Time) ([]GameMasterSessionEnt, error) {
+	var list []GameMasterSessionEnt
+
+	rows, err := db.Query(ctx, `
+		SELECT
+			r.room_code, r.name, COALESCE(g.name, '') AS game_name,
+			r.start_date, r.end_date, COALESCE(r.start_time, '00:00'::time), COALESCE(r.end_time, '23:59:59'::time), r.status,
+			(SELECT COUNT(*) FROM rooms_participants rp WHERE rp.room_id = r.id AND rp.status = 'active') AS participants
+		FROM rooms r
+			LEFT JOIN games g ON g.id = r.game_id
+		WHERE r.game_master_id = $1 AND r.deleted_date IS NULL AND r.start_date BETWEEN $2 AND $3
+		ORDER BY `+eventStart, adminId, from, to)
+	if err != nil {
+		return nil, c.errHandler("model.GetGameMasterSessions", err, utils.ErrGettingGameMasterSchedule)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data GameMasterSessionEnt
+		err = rows.Scan(
+			&data.RoomCode, &data.Name, &data.GameName,
+			&data.StartDate, &data.EndDate, &data.StartTime, &data.EndTime, &data.Status,
+			&data.Participants,
+		)
+		if err != nil {
+			return nil, c.errHandler("model.GetGameMasterSessions", err, utils.ErrGettingGameMasterSchedule)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// GetGameMasterWorkload returns the rooms hosted per game master and week
+// between from and to, both included. An empty adminCode covers every game
+// master.
+func (c *Contract) GetGameMasterWorkload(db *pgxpool.Pool, ctx context.Context, from, to time.Time, adminCode string) ([]GameMasterWorkloadEnt, error) {
+	var list []GameMasterWorkloadEnt
+
+	rows, err := db.Query(ctx, `
+		SELECT
+			a.admin_code, a.name AS admin_name,
+			date_trunc('week', r.start_date)::date AS week_start,
+			COUNT(*) AS sessions,
+			COALESCE(SUM(EXTRACT(EPOCH FROM `+eventEnd+` - `+eventStart+`) / 3600), 0)::float8 AS hours,
+			COALESCE(SUM((SELECT COUNT(*) FROM rooms_participants rp WHERE rp.room_id = r.id AND rp.status = 'active')), 0) AS participants
+		FROM rooms r
+			JOIN admins a ON a.id = r.game_master_id
+		WHERE r.deleted_date IS NULL AND r.start_date BETWEEN $1 AND $2 AND ($3 = '' OR a.admin_code = $3)
+		GROUP BY a.id, week_start
+		ORDER BY a.name, week_start`, from, to, adminCode)
+	if err != nil {
+		return nil, c.errHandler("model.GetGameMasterWorkload", err, utils.ErrGettingGameMasterWorkload)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data GameMasterWorkloadEnt
+		err = rows.Scan(&data.AdminCode, &data.AdminName, &data.WeekStart, &data.Sessions, &data.Hours, &data.Participants)
+		if err != nil {
+			return nil, c.errHandler("model.GetGameMasterWorkload", err, utils.ErrGettingGameMasterWorkload)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"dots-api/services/api/request"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type JobRunEnt struct {
+	JobName       string         `db:"job_name"`
+	ScheduledDate time.Time      `db:"scheduled_date"`
+	Status        string         `db:"status"`
+	ErrorMessage  sql.NullString `db:"error_message"`
+	ItemCount     int            `db:"item_count"`
+	StartedDate   time.Time      `db:"started_date"`
+	FinishedDate  sql.NullTime   `db:"finished_date"`
+}
+
+// GetJobRunList returns the history of the scheduled jobs run by the worker.
+func (c *Contract) GetJobRunList(db *pgxpool.Pool, ctx context.Context, param request.JobRunParam) ([]JobRunEnt, request.JobRunParam, error) {
+	var (
+		err        error
+		list       []JobRunEnt
+		where      []string
+		paramQuery []interface{}
+		totalData  int
+	)
+
+	query := `SELECT job_name, scheduled_date, status, error_message, item_count, started_date, finished_date FROM job_runs`
+
+	if len(param.JobName) > 0 {
+		paramQuery = append(paramQuery, param.JobName)
+		where = append(where, fmt.Sprintf("job_name = $%d", len(paramQuery)))
+	}
+
+	if len(param.Status) > 0 {
+		paramQuery = append(paramQuery, param.Status)
+		where = append(where, fmt.Sprintf("status = $%d", len(paramQuery)))
+	}
+
+	// Append All Where Conditions
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Count Query
+	newQcount := `SELECT COUNT(*) FROM ( ` + query + ` ) AS data`
+	err = db.QueryRow(ctx, newQcount, paramQuery...).Scan(&totalData)
+	if err != nil {
+		return list, param, c.errHandler("model.GetJobRunList", err, utils.ErrCountingJobRuns)
+	}
+	param.Count = totalData
+
+	// Select Max Page
+	if param.Count > param.Limit && param.Page > int(param.Count/param.Limit) {
+		param.MaxPage = int(math.Ceil(float64(param.Count) / float64(param.Limit)))
+	} else {
+		param.MaxPage = int(param.Count / param.Limit)
+	}
+
+	// Limit and Offset
+	param.Offset = (param.Page - 1) * param.Limit
+	query += " ORDER BY " + param.Order + " " + param.Sort + " "
+
+	paramQuery = append(paramQuery, param.Offset)
+	query += fmt.Sprintf("offset $%d ", len(paramQuery))
+
+	paramQuery = append(paramQuery, param.Limit)
+	query += fmt.Sprintf("limit $%d ", len(paramQuery))
+
+	rows, err := db.Query(ctx, query, paramQuery...)
+	if err != nil {
+		return list, param, c.errHandler("model.GetJobRunList", err, utils.ErrGettingJobRuns)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data JobRunEnt
+		err = rows.Scan(&data.JobName, &data.ScheduledDate, &data.Status, &data.ErrorMessage, &data.ItemCount, &data.StartedDate, &data.FinishedDate)
+		if err != nil {
+			return list, param, c.errHandler("model.GetJobRunList", err, utils.ErrScanningJobRuns)
+		}
+		list = append(list, data)
+	}
+
+	return list, param, nil
+}
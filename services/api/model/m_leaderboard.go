@@ -0,0 +1,69 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// LeaderboardEnt is the standing of a member in the ranked rooms of a game.
+type LeaderboardEnt struct {
+	Rank         int            `db:"rank"`
+	UserCode     string         `db:"user_code"`
+	UserName     string         `db:"user_name"`
+	UserImageUrl sql.NullString `db:"user_image_url"`
+	Played       int            `db:"played"`
+	Wins         int            `db:"wins"`
+	Podiums      int            `db:"podiums"`
+	BestScore    sql.NullInt64  `db:"best_score"`
+	AverageScore float64        `db:"average_score"`
+}
+
+// GetGameLeaderboard ranks the members by their results in the rooms of a
+// game: first places, then top three finishes, then average score. Rooms
+// without results are left out.
+func (c *Contract) GetGameLeaderboard(db *pgxpool.Pool, ctx context.Context, gameId int64, limit int) ([]LeaderboardEnt, error) {
+	var list []LeaderboardEnt
+
+	query := `
+		SELECT
+			RANK() OVER (ORDER BY l.wins DESC, l.podiums DESC, l.average_score DESC) AS "rank",
+			l.user_code, l.user_name, l.user_image_url, l.played, l.wins, l.podiums, l.best_score, l.average_score
+		FROM (
+			SELECT
+				u.user_code, COALESCE(u.username, '') AS user_name, u.image_url AS user_image_url,
+				COUNT(*) AS played,
+				COUNT(*) FILTER (WHERE rp."rank" = 1) AS wins,
+				COUNT(*) FILTER (WHERE rp."rank" <= 3) AS podiums,
+				MAX(rp.score) AS best_score,
+				COALESCE(AVG(rp.score), 0)::float8 AS average_score
+			FROM rooms_participants rp
+				JOIN rooms r ON r.id = rp.room_id
+				JOIN users u ON u.id = rp.user_id
+			WHERE r.game_id = $1 AND r.deleted_date IS NULL AND rp.status = 'active' AND rp."rank" IS NOT NULL
+			GROUP BY u.id
+		) l
+		ORDER BY "rank", l.played DESC, l.user_name
+		LIMIT $2`
+	rows, err := db.Query(ctx, query, gameId, limit)
+	if err != nil {
+		return list, c.errHandler("model.GetGameLeaderboard", err, utils.ErrGettingLeaderboard)
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var data LeaderboardEnt
+		err = rows.Scan(
+			&data.Rank, &data.UserCode, &data.UserName, &data.UserImageUrl,
+			&data.Played, &data.Wins, &data.Podiums, &data.BestScore, &data.AverageScore,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetGameLeaderboard", err, utils.ErrScanningLeaderboard)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
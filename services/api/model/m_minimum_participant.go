@@ -0,0 +1,232 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/mail"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	// MinimumEventEnt is an active room or tournament starting soon with
+	// fewer participants than its minimum.
+	MinimumEventEnt struct {
+		EventType          string        `db:"event_type"`
+		EventId            int64         `db:"event_id"`
+		EventCode          string        `db:"event_code"`
+		EventName          string        `db:"event_name"`
+		EventImageUrl      string        `db:"event_image_url"`
+		StartDate          time.Time     `db:"start_date"`
+		StartAt            time.Time     `db:"start_at"`
+		MinimalParticipant int           `db:"minimal_participant"`
+		Participants       int           `db:"participants"`
+		GameMasterId       sql.NullInt64 `db:"game_master_id"`
+		WarnedDate         sql.NullTime  `db:"minimum_warned_date"`
+	}
+
+	// MinimumStaffEnt is an admin told about an event below its minimum.
+	MinimumStaffEnt struct {
+		AdminCode string `db:"admin_code"`
+		Name      string `db:"name"`
+		Email     string `db:"email"`
+	}
+)
+
+// GetEventsBelowMinimum lists the active events of a type starting after from
+// and up to to that have fewer paid participants than their minimum. Events
+// created after their own cutoff are left out, they were opened that late on
+// purpose.
+func (c *Contract) GetEventsBelowMinimum(db *pgxpool.Pool, ctx context.Context, eventType string, from, to time.Time, cutoffHours int) ([]MinimumEventEnt, error) {
+	var list []MinimumEventEnt
+
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return list, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	// Tournaments have no game master
+	gameMaster := "NULL::bigint"
+	if eventType == utils.WaitlistRoom {
+		gameMaster = "e.game_master_id"
+	}
+
+	query := `
+		SELECT event_id, event_code, event_name, event_image_url, start_date, start_at, minimal_participant, participants, game_master_id, minimum_warned_date
+		FROM (
+			SELECT
+				e.id AS event_id, e.` + table.code + ` AS event_code, COALESCE(e.name, '') AS event_name, COALESCE(e.image_url, '') AS event_image_url,
+				e.start_date, (e.start_date + COALESCE(e.start_time, '00:00'::time)) AT TIME ZONE 'Asia/Jakarta' AS start_at,
+				e.minimal_participant, e.created_date, e.minimum_warned_date, ` + gameMaster + ` AS game_master_id,
+				(SELECT COUNT(p.id) FROM ` + table.participants + ` p WHERE p.` + table.participantEvent + ` = e.id AND p.status = 'active') AS participants
+			FROM ` + table.event + ` e
+			WHERE e.deleted_date IS NULL AND e.status = 'active' AND e.minimal_participant > 0 AND e.start_date IS NOT NULL
+		) ev
+		WHERE start_at > $1 AND start_at <= $2 AND participants < minimal_participant
+			AND created_date < start_at - make_interval(hours => $3::int)
+		ORDER BY start_at`
+	rows, err := db.Query(ctx, query, from, to, cutoffHours)
+	if err != nil {
+		return list, c.errHandler("model.GetEventsBelowMinimum", err, utils.ErrGettingMinimumEvents)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data := MinimumEventEnt{EventType: eventType}
+		err = rows.Scan(
+			&data.EventId, &data.EventCode, &data.EventName, &data.EventImageUrl, &data.StartDate, &data.StartAt,
+			&data.MinimalParticipant, &data.Participants, &data.GameMasterId, &data.WarnedDate,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetEventsBelowMinimum", err, utils.ErrGettingMinimumEvents)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+func (c *Contract) MarkMinimumWarned(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) error {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `UPDATE ` + table.event + ` SET minimum_warned_date = $1 WHERE id = $2`
+	if _, err := db.Exec(ctx, query, time.Now().UTC(), eventId); err != nil {
+		return c.errHandler("model.MarkMinimumWarned", err, utils.ErrMarkingMinimumWarned)
+	}
+
+	return nil
+}
+
+// GetMinimumStaff lists the active admins and the game master of an event,
+// who are told when it is short of participants.
+func (c *Contract) GetMinimumStaff(db *pgxpool.Pool, ctx context.Context, gameMasterId sql.NullInt64) ([]MinimumStaffEnt, error) {
+	var list []MinimumStaffEnt
+
+	query := `
+		SELECT admin_code, COALESCE(name, '') AS name, COALESCE(email, '') AS email
+		FROM admins
+		WHERE deleted_date IS NULL AND status = 'active' AND (role_id = $1 OR id = $2)
+		ORDER BY id`
+	rows, err := db.Query(ctx, query, utils.RoleAdminId, gameMasterId)
+	if err != nil {
+		return list, c.errHandler("model.GetMinimumStaff", err, utils.ErrGettingMinimumEvents)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data MinimumStaffEnt
+		if err = rows.Scan(&data.AdminCode, &data.Name, &data.Email); err != nil {
+			return list, c.errHandler("model.GetMinimumStaff", err, utils.ErrGettingMinimumEvents)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// CheckMinimumParticipants enforces the minimum participants of the rooms and
+// tournaments. The game master and the admins of an event short of its
+// minimum are warned once a few hours before the cutoff, and the event is
+// cancelled at the cutoff. An event is only cancelled after it was warned, so
+// one that first falls short inside the cutoff is cancelled a run later. It
+// returns how many events were warned or cancelled. A failure on one event
+// does not stop the others.
+func (c *Contract) CheckMinimumParticipants(db *pgxpool.Pool, ctx context.Context, cutoffHours, warningHours int) (int, error) {
+	var (
+		total  int
+		now    = time.Now().UTC()
+		cutoff = time.Duration(cutoffHours) * time.Hour
+		until  = now.Add(cutoff + time.Duration(warningHours)*time.Hour)
+	)
+
+	for _, eventType := range []string{utils.WaitlistRoom, utils.WaitlistTournament} {
+		list, err := c.GetEventsBelowMinimum(db, ctx, eventType, now, until, cutoffHours)
+		if err != nil {
+			return total, err
+		}
+
+		for _, v := range list {
+			if !v.WarnedDate.Valid {
+				// An event that first falls short inside the cutoff is warned
+				// now and cancelled on the next run
+				cutoffAt := v.StartAt.Add(-cutoff)
+				if cutoffAt.Before(now) {
+					cutoffAt = now
+				}
+
+				cutoffDate := cutoffAt.In(utils.GetTimeLocationWIB()).Format(utils.DATE_TIME_FORMAT)
+				description := fmt.Sprintf(utils.MinimumWarningDescription, v.EventName, v.StartDate.Format(utils.DATE_FORMAT), v.Participants, v.MinimalParticipant, cutoffDate)
+				c.notifyMinimumStaff(db, ctx, v, utils.MinimumWarningType, utils.MinimumWarningTitle, description, false)
+
+				if err = c.MarkMinimumWarned(db, ctx, eventType, v.EventId); err != nil {
+					log.Printf("Error : %s", err)
+				}
+				total++
+				continue
+			}
+
+			if v.StartAt.After(now.Add(cutoff)) {
+				continue
+			}
+
+			_, _, err = c.CancelEvent(db, ctx, eventType, v.EventId, v.EventCode, utils.MinimumCancelReason, utils.MinimumCancelledBy)
+			if err != nil {
+				log.Printf("Error : %s", err)
+				continue
+			}
+
+			description := fmt.Sprintf(utils.MinimumCancelledDescription, v.EventName, v.StartDate.Format(utils.DATE_FORMAT), v.Participants, v.MinimalParticipant)
+			c.notifyMinimumStaff(db, ctx, v, utils.MinimumCancelledType, utils.MinimumCancelledTitle, description, true)
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// notifyMinimumStaff tells the game master and the admins about an event
+// short of participants, in the CMS and by email.
+func (c *Contract) notifyMinimumStaff(db *pgxpool.Pool, ctx context.Context, event MinimumEventEnt, nType, title, description string, cancelled bool) {
+	staff, err := c.GetMinimumStaff(db, ctx, event.GameMasterId)
+	if err != nil {
+		log.Printf("Error : %s", err)
+		return
+	}
+
+	descriptionJSON, err := json.Marshal(description)
+	if err != nil {
+		log.Printf("Error : %s", err)
+		return
+	}
+
+	for _, v := range staff {
+		err = c.AddNotification(db, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "admin", v.AdminCode, event.EventCode, nType, title, descriptionJSON, event.EventImageUrl)
+		if err != nil {
+			log.Printf("Error : %s", err)
+		}
+
+		if v.Email == "" {
+			continue
+		}
+
+		err = mail.New(c.App).SendMail(mail.MinimumParticipant, mail.MailSubj[mail.MinimumParticipant], v.Email, mail.MinimumParticipantData{
+			Name:        v.Name,
+			Title:       title,
+			Description: description,
+			EventType:   event.EventType,
+			EventCode:   event.EventCode,
+			Cancelled:   cancelled,
+		})
+		if err != nil {
+			log.Printf("Error : %s", err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const addOutboxEventQuery = `INSERT INTO outbox_events (queue_name, payload, created_date) VALUES ($1, $2, $3)`
+
+// AddOutboxEventTrx writes a queue message to the outbox within a transaction.
+// The outbox relay publishes it once the transaction is committed.
+func (c *Contract) AddOutboxEventTrx(tx pgx.Tx, ctx context.Context, data rabbit.QueueData) error {
+	payload, err := json.Marshal(data.Data)
+	if err != nil {
+		return c.errHandler("model.AddOutboxEventTrx", err, utils.ErrAddingOutboxEvent)
+	}
+
+	_, err = tx.Exec(ctx, addOutboxEventQuery, data.QueueName, payload, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.AddOutboxEventTrx", err, utils.ErrAddingOutboxEvent)
+	}
+
+	return nil
+}
+
+// AddOutboxEvent writes a queue message to the outbox outside of a transaction.
+func (c *Contract) AddOutboxEvent(db *pgxpool.Pool, ctx context.Context, data rabbit.QueueData) error {
+	payload, err := json.Marshal(data.Data)
+	if err != nil {
+		return c.errHandler("model.AddOutboxEvent", err, utils.ErrAddingOutboxEvent)
+	}
+
+	_, err = db.Exec(ctx, addOutboxEventQuery, data.QueueName, payload, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.AddOutboxEvent", err, utils.ErrAddingOutboxEvent)
+	}
+
+	return nil
+}
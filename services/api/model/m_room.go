@@ -39,12 +39,17 @@ type (
 		StartTime          time.Time       `db:"start_time"`
 		EndTime            time.Time       `db:"end_time"`
 		MaximumParticipant int             `db:"maximum_participant"`
+		MinimalParticipant int             `db:"minimal_participant"`
 		BookingPrice       float64         `db:"booking_price"`
 		RewardPoint        int             `db:"reward_point"`
 		InstagramLink      string          `db:"instagram_link"`
 		Status             string          `db:"status"`
 		DayPastEndDate     sql.NullFloat64 `db:"day_past_end_date"`
 		CurrentUsedSlot    int             `db:"current_used_slot"`
+		Visibility         string          `db:"visibility"`
+		RequiresApproval   bool            `db:"requires_approval"`
+		HostUserCode       sql.NullString  `db:"host_user_code"`
+		HostUserName       sql.NullString  `db:"host_user_name"`
 	}
 
 	RoomListEnt struct {
@@ -74,6 +79,10 @@ type (
 		GameCode           string          `db:"game_code"`
 		GameName           string          `db:"game_name"`
 		GameImgUrl         string          `db:"game_img_url"`
+		Visibility         string          `db:"visibility"`
+		RequiresApproval   bool            `db:"requires_approval"`
+		HostUserCode       sql.NullString  `db:"host_user_code"`
+		HostUserName       sql.NullString  `db:"host_user_name"`
 	}
 )
 
@@ -112,11 +121,16 @@ func (c *Contract) GetRoomList(db *pgxpool.Pool, ctx context.Context, param requ
 				a.image_url AS game_master_image_url,
 				g.game_code,
 				g.name AS game_name,
-				g.image_url AS game_img_url
+				g.image_url AS game_img_url,
+				rooms.visibility,
+				rooms.requires_approval,
+				hu.user_code AS host_user_code,
+				hu.username AS host_user_name
 			FROM rooms
 				JOIN games g ON rooms.game_id = g.id 
 				JOIN cafes c ON c.id = g.cafe_id 
 				left join admins a on rooms.game_master_id = a.id
+				left join users hu on rooms.host_user_id = hu.id
 				left join (
 					select count(rp.user_id) count_participants, rp.room_id
 					from rooms_participants rp
@@ -149,6 +163,15 @@ func (c *Contract) GetRoomList(db *pgxpool.Pool, ctx context.Context, param requ
 		where = append(where, strings.Join(orWhere, " AND "))
 	}
 
+	// ROOM VISIBILITY
+	if !param.IncludePrivate {
+		param.Visibility = utils.RoomVisibilityPublic
+	}
+	if len(param.Visibility) > 0 {
+		paramQuery = append(paramQuery, param.Visibility)
+		where = append(where, fmt.Sprintf("rooms.visibility = $%d", len(paramQuery)))
+	}
+
 	// ROOM STATUS
 	if len(param.Status) > 0 {
 		var orWhere []string
@@ -217,6 +240,8 @@ func (c *Contract) GetRoomList(db *pgxpool.Pool, ctx context.Context, param requ
 			&data.CurrentUsedSlot,
 			&data.GameMasterName, &data.GameMasterImageUrl,
 			&data.GameCode, &data.GameName, &data.GameImgUrl,
+			&data.Visibility, &data.RequiresApproval,
+			&data.HostUserCode, &data.HostUserName,
 		)
 
 		if err != nil {
@@ -256,15 +281,21 @@ func (c *Contract) GetRoomByCode(db *pgxpool.Pool, ctx context.Context, code str
 			r.start_time,
 			r.end_time,
 			r.maximum_participant,
+			r.minimal_participant,
 			r.booking_price,
 			r.reward_point,
 			r.instagram_link,
 			r.status,
 			DATE_PART('day', NOW() - r.end_date) AS days_past_end_date, 
 			COALESCE(r.image_url, '') AS room_banner_url,
-			COALESCE(cp.count_participants, 0) as current_used_slot
+			COALESCE(cp.count_participants, 0) as current_used_slot,
+			r.visibility,
+			r.requires_approval,
+			hu.user_code AS host_user_code,
+			hu.username AS host_user_name
 		FROM rooms r 
 			JOIN admins a ON r.game_master_id = a.id
+			LEFT JOIN users hu ON hu.id = r.host_user_id
 			JOIN games g ON r.game_id = g.id 
 			JOIN cafes c ON c.id = g.cafe_id  
 			left join (
@@ -282,7 +313,8 @@ func (c *Contract) GetRoomByCode(db *pgxpool.Pool, ctx context.Context, code str
 		&data.CafeCode, &data.CafeName, &data.CafeAddress,
 		&data.RoomId, &data.RoomCode, &data.RoomType, &data.Name, &data.Description, &data.SpecialInstruction, &data.Difficulty,
 		&data.StartDate, &data.EndDate, &data.StartTime, &data.EndTime,
-		&data.MaximumParticipant, &data.BookingPrice, &data.RewardPoint, &data.InstagramLink, &data.Status, &data.DayPastEndDate, &data.BannerRoomUrl, &data.CurrentUsedSlot,
+		&data.MaximumParticipant, &data.MinimalParticipant, &data.BookingPrice, &data.RewardPoint, &data.InstagramLink, &data.Status, &data.DayPastEndDate, &data.BannerRoomUrl, &data.CurrentUsedSlot,
+		&data.Visibility, &data.RequiresApproval, &data.HostUserCode, &data.HostUserName,
 	)
 
 	if err != nil {
@@ -292,13 +324,13 @@ func (c *Contract) GetRoomByCode(db *pgxpool.Pool, ctx context.Context, code str
 	return data, nil
 }
 
-func (c *Contract) AddRoom(db *pgxpool.Pool, ctx context.Context, gameMasterId int64, gameId int64, roomCode, roomType, roomName, description string, startDate, endDate, startTime, endTime interface{}, bookingPrice float64, rewardPoint int, intagramLink, status, difficulty, instruction string, maximumParticipant int, imageUrl string, locationCity string) error {
+func (c *Contract) AddRoom(db *pgxpool.Pool, ctx context.Context, gameMasterId int64, gameId int64, roomCode, roomType, roomName, description string, startDate, endDate, startTime, endTime interface{}, bookingPrice float64, rewardPoint int, intagramLink, status, difficulty, instruction string, maximumParticipant, minimalParticipant int, imageUrl string, locationCity string, visibility string, requiresApproval bool, inviteCode string) error {
 	sql := `INSERT INTO rooms(
-		game_master_id, game_id, room_code, room_type, "name", description, start_date, end_date, start_time, end_time, booking_price, reward_point, instagram_link, status, difficulty, instruction, maximum_participant, image_url, created_date, updated_date, location_city
+		game_master_id, game_id, room_code, room_type, "name", description, start_date, end_date, start_time, end_time, booking_price, reward_point, instagram_link, status, difficulty, instruction, maximum_participant, image_url, created_date, updated_date, location_city, visibility, requires_approval, invite_code, minimal_participant
 	)
-	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)`
 
-	_, err := db.Exec(ctx, sql, gameMasterId, gameId, roomCode, roomType, roomName, description, startDate, endDate, startTime, endTime, bookingPrice, rewardPoint, intagramLink, status, difficulty, instruction, maximumParticipant, imageUrl, time.Now().In(time.UTC), time.Now().In(time.UTC), locationCity)
+	_, err := db.Exec(ctx, sql, gameMasterId, gameId, roomCode, roomType, roomName, description, startDate, endDate, startTime, endTime, bookingPrice, rewardPoint, intagramLink, status, difficulty, instruction, maximumParticipant, imageUrl, time.Now().In(time.UTC), time.Now().In(time.UTC), locationCity, visibility, requiresApproval, inviteCode, minimalParticipant)
 	if err != nil {
 		return c.errHandler("model.AddRoom", err, utils.ErrAddingRoom)
 	}
@@ -306,7 +338,7 @@ func (c *Contract) AddRoom(db *pgxpool.Pool, ctx context.Context, gameMasterId i
 	return nil
 }
 
-func (c *Contract) UpdateRoom(db *pgxpool.Pool, ctx context.Context, code string, gameMasterId int64, gameId int64, roomCode, roomType, roomName, description string, startDate, endDate, startTime, endTime interface{}, bookingPrice float64, rewardPoint int, instagramLink, status, difficulty, instruction string, maximumParticipant int, imageUrl string, locationCity string) error {
+func (c *Contract) UpdateRoom(db *pgxpool.Pool, ctx context.Context, code string, gameMasterId int64, gameId int64, roomCode, roomType, roomName, description string, startDate, endDate, startTime, endTime interface{}, bookingPrice float64, rewardPoint int, instagramLink, status, difficulty, instruction string, maximumParticipant, minimalParticipant int, imageUrl string, locationCity string, visibility string, requiresApproval bool, inviteCode string) error {
 	var (
 		err error
 		sql = `
@@ -330,10 +362,16 @@ func (c *Contract) UpdateRoom(db *pgxpool.Pool, ctx context.Context, code string
 		    maximum_participant = $17,
 		    image_url = $18,
 		    updated_date = $19,
-		    location_city = $20
+		    location_city = $20,
+		    is_detached = series_id IS NOT NULL,
+		    visibility = $22,
+		    requires_approval = $23,
+		    invite_code = COALESCE(invite_code, $24),
+		    minimal_participant = $25,
+		    minimum_warned_date = CASE WHEN start_date IS DISTINCT FROM $7 OR start_time IS DISTINCT FROM $9 THEN NULL ELSE minimum_warned_date END
 		WHERE room_code = $21`
 	)
-	_, err = db.Exec(ctx, sql, gameMasterId, gameId, roomCode, roomType, roomName, description, startDate, endDate, startTime, endTime, bookingPrice, rewardPoint, instagramLink, status, difficulty, instruction, maximumParticipant, imageUrl, time.Now().In(time.UTC), locationCity, code)
+	_, err = db.Exec(ctx, sql, gameMasterId, gameId, roomCode, roomType, roomName, description, startDate, endDate, startTime, endTime, bookingPrice, rewardPoint, instagramLink, status, difficulty, instruction, maximumParticipant, imageUrl, time.Now().In(time.UTC), locationCity, code, visibility, requiresApproval, inviteCode, minimalParticipant)
 	if err != nil {
 		return c.errHandler("model.UpdateRoom", err, utils.ErrUpdatingRoom)
 	}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/onesignal"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	// RoomAccessEnt is what a member may do with a room: everyone can see and
+	// book a public room, a private one needs its invite code and, when the
+	// room asks for it, the approval of the host.
+	RoomAccessEnt struct {
+		Visibility       string         `db:"visibility"`
+		RequiresApproval bool           `db:"requires_approval"`
+		RequestStatus    sql.NullString `db:"request_status"`
+		IsParticipant    bool           `db:"is_participant"`
+	}
+
+	RoomInviteEnt struct {
+		RoomId           int64          `db:"room_id"`
+		RoomCode         string         `db:"room_code"`
+		Name             string         `db:"name"`
+		ImageUrl         string         `db:"image_url"`
+		Visibility       string         `db:"visibility"`
+		RequiresApproval bool           `db:"requires_approval"`
+		InviteCode       sql.NullString `db:"invite_code"`
+	}
+
+	RoomJoinRequestEnt struct {
+		UserCode     string         `db:"user_code"`
+		UserName     string         `db:"user_name"`
+		UserImgUrl   string         `db:"user_img_url"`
+		Status       string         `db:"status"`
+		ReviewedBy   sql.NullString `db:"reviewed_by"`
+		ReviewedDate sql.NullTime   `db:"reviewed_date"`
+		CreatedDate  time.Time      `db:"created_date"`
+	}
+)
+
+// CanView reports whether the member may see the room: it is public, or they
+// joined it with its invite code or booked it.
+func (e RoomAccessEnt) CanView() bool {
+	return e.Visibility != utils.RoomVisibilityPrivate || e.IsParticipant || e.RequestStatus.Valid
+}
+
+// CanBook returns why the member may not book the room, if they may not.
+func (e RoomAccessEnt) CanBook() error {
+	if e.Visibility != utils.RoomVisibilityPrivate || e.IsParticipant {
+		return nil
+	}
+
+	switch e.RequestStatus.String {
+	case utils.RoomJoinApproved:
+		return nil
+	case utils.RoomJoinPending:
+		return errors.New(utils.ErrRoomJoinPending)
+	case utils.RoomJoinRejected:
+		return errors.New(utils.ErrRoomJoinRejected)
+	}
+
+	return errors.New(utils.ErrRoomPrivate)
+}
+
+// GetRoomAccess returns the access of a member to a room.
+func (c *Contract) GetRoomAccess(db *pgxpool.Pool, ctx context.Context, roomId, userId int64) (RoomAccessEnt, error) {
+	var data RoomAccessEnt
+
+	query := `
+		SELECT
+			r.visibility, r.requires_approval, jr.status,
+			EXISTS (SELECT 1 FROM rooms_participants rp WHERE rp.room_id = r.id AND rp.user_id = $2 AND rp.status != 'cancel')
+		FROM rooms r
+			LEFT JOIN room_join_requests jr ON jr.room_id = r.id AND jr.user_id = $2
+		WHERE r.id = $1`
+	err := db.QueryRow(ctx, query, roomId, userId).Scan(&data.Visibility, &data.RequiresApproval, &data.RequestStatus, &data.IsParticipant)
+	if err != nil {
+		return data, c.errHandler("model.GetRoomAccess", err, utils.ErrGettingRoomAccess)
+	}
+
+	return data, nil
+}
+
+const roomInviteQuery = `
+	SELECT id, room_code, name, COALESCE(image_url, ''), visibility, requires_approval, invite_code
+	FROM rooms`
+
+func (c *Contract) GetRoomInviteByRoomCode(db *pgxpool.Pool, ctx context.Context, roomCode string) (RoomInviteEnt, error) {
+	var data RoomInviteEnt
+
+	err := db.QueryRow(ctx, roomInviteQuery+` WHERE room_code = $1 AND deleted_date IS NULL`, roomCode).Scan(
+		&data.RoomId, &data.RoomCode, &data.Name, &data.ImageUrl, &data.Visibility, &data.RequiresApproval, &data.InviteCode,
+	)
+	if err != nil {
+		return data, c.errHandler("model.GetRoomInviteByRoomCode", err, utils.ErrGettingRoomInvite)
+	}
+
+	return data, nil
+}
+
+// GetRoomInviteByInviteCode returns the room an invite code joins.
+func (c *Contract) GetRoomInviteByInviteCode(db *pgxpool.Pool, ctx context.Context, inviteCode string) (RoomInviteEnt, error) {
+	var data RoomInviteEnt
+
+	err := db.QueryRow(ctx, roomInviteQuery+` WHERE invite_code = $1 AND deleted_date IS NULL`, inviteCode).Scan(
+		&data.RoomId, &data.RoomCode, &data.Name, &data.ImageUrl, &data.Visibility, &data.RequiresApproval, &data.InviteCode,
+	)
+	if err != nil {
+		if err = c.errHandler("model.GetRoomInviteByInviteCode", err, utils.ErrGettingRoomInvite); err.Error() == utils.EmptyData {
+			return data, errors.New(utils.ErrInvalidInviteCode)
+		}
+		return data, err
+	}
+
+	return data, nil
+}
+
+// ResetRoomInviteCode replaces the invite code of a room, so the old code and
+// link stop working. Members who already joined keep their access.
+func (c *Contract) ResetRoomInviteCode(db *pgxpool.Pool, ctx context.Context, roomId int64, inviteCode string) error {
+	_, err := db.Exec(ctx, `UPDATE rooms SET invite_code = $1, updated_date = $2 WHERE id = $3`, inviteCode, time.Now().UTC(), roomId)
+	if err != nil {
+		return c.errHandler("model.ResetRoomInviteCode", err, utils.ErrResettingRoomInvite)
+	}
+
+	return nil
+}
+
+// JoinRoom records that a member joined a private room with its invite code
+// and returns the status of their request. Joining again keeps the status,
+// so a rejected member cannot ask again.
+func (c *Contract) JoinRoom(db *pgxpool.Pool, ctx context.Context, roomId, userId int64, status string) (string, error) {
+	var current string
+
+	query := `
+		INSERT INTO room_join_requests (room_id, user_id, status, created_date) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (room_id, user_id) DO UPDATE SET status = room_join_requests.status
+		RETURNING status`
+	err := db.QueryRow(ctx, query, roomId, userId, status, time.Now().UTC()).Scan(&current)
+	if err != nil {
+		return "", c.errHandler("model.JoinRoom", err, utils.ErrJoiningRoom)
+	}
+
+	return current, nil
+}
+
+// GetRoomJoinRequests returns the members who joined a private room, oldest
+// first, optionally with one status.
+func (c *Contract) GetRoomJoinRequests(db *pgxpool.Pool, ctx context.Context, roomId int64, status string) ([]RoomJoinRequestEnt, error) {
+	var list []RoomJoinRequestEnt
+
+	query := `
+		SELECT u.user_code, COALESCE(u.username, ''), COALESCE(u.image_url, ''), jr.status, jr.reviewed_by, jr.reviewed_date, jr.created_date
+		FROM room_join_requests jr
+			JOIN users u ON u.id = jr.user_id
+		WHERE jr.room_id = $1 AND ($2 = '' OR jr.status = $2)
+		ORDER BY jr.created_date`
+	rows, err := db.Query(ctx, query, roomId, status)
+	if err != nil {
+		return nil, c.errHandler("model.GetRoomJoinRequests", err, utils.ErrGettingRoomJoinRequests)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data RoomJoinRequestEnt
+		err = rows.Scan(&data.UserCode, &data.UserName, &data.UserImgUrl, &data.Status, &data.ReviewedBy, &data.ReviewedDate, &data.CreatedDate)
+		if err != nil {
+			return nil, c.errHandler("model.GetRoomJoinRequests", err, utils.ErrGettingRoomJoinRequests)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// ReviewRoomJoinRequest approves or rejects the pending request of a member
+// to join a private room.
+func (c *Contract) ReviewRoomJoinRequest(db *pgxpool.Pool, ctx context.Context, roomId, userId int64, status, adminCode string) error {
+	now := time.Now().UTC()
+	tag, err := db.Exec(ctx, `
+		UPDATE room_join_requests SET status = $1, reviewed_by = $2, reviewed_date = $3, updated_date = $3
+		WHERE room_id = $4 AND user_id = $5 AND status = $6`,
+		status, adminCode, now, roomId, userId, utils.RoomJoinPending)
+	if err != nil {
+		return c.errHandler("model.ReviewRoomJoinRequest", err, utils.ErrReviewingRoomJoinRequest)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrRoomJoinRequestNotFound)
+	}
+
+	return nil
+}
+
+// NotifyRoomJoinReviewed tells a member whether the host let them join a
+// private room, in the app and by push notification. A failed notification is
+// only logged.
+func (c *Contract) NotifyRoomJoinReviewed(db *pgxpool.Pool, ctx context.Context, room RoomInviteEnt, userId int64, status string) {
+	var (
+		userCode, xPlayer string
+		nType             = utils.RoomJoinRejectedType
+		title             = utils.RoomJoinRejectedTitle
+		description       = fmt.Sprintf(utils.RoomJoinRejectedDescription, room.Name)
+	)
+
+	if status == utils.RoomJoinApproved {
+		nType = utils.RoomJoinApprovedType
+		title = utils.RoomJoinApprovedTitle
+		description = fmt.Sprintf(utils.RoomJoinApprovedDescription, room.Name)
+	}
+
+	err := db.QueryRow(ctx, `SELECT user_code, COALESCE(x_player, '') FROM users WHERE id = $1`, userId).Scan(&userCode, &xPlayer)
+	if err != nil {
+		log.Printf("Error : %s", c.errHandler("model.NotifyRoomJoinReviewed", err, utils.ErrGettingUserData))
+		return
+	}
+
+	descriptionJSON, err := json.Marshal(description)
+	if err != nil {
+		log.Printf("Error : %s", err)
+		return
+	}
+
+	err = c.AddNotification(db, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "user", userCode, room.RoomCode, nType, title, descriptionJSON, room.ImageUrl)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	}
+
+	_, err = onesignal.New(c.App).CreateOSNotifications(xPlayer, title, description, utils.Room)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	}
+}
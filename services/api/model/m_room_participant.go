@@ -24,21 +24,34 @@ type (
 	}
 
 	RoomParticipantResp struct {
-		UserCode           string         `db:"user_code"`
-		UserName           string         `db:"user_name"`
-		UserImgUrl         string         `db:"user_image_url"`
-		UserXPlayer        string         `db:"user_x_player"`
-		StatusWinner       bool           `db:"status_winner"`
-		Status             string         `db:"status"`
-		TransactionCode    sql.NullString `db:"transaction_code"`
-		AdditionalInfo     sql.NullString `db:"additional_info"`
-		Position           int            `db:"position"`
-		RewardPoint        sql.NullInt64  `db:"reward_point"`
-		RoomId             int64          `db:"room_id"`
-		UserId             int64          `db:"user_id"`
-		ParticipationPoint int            `db:"participation_point"`
-		LatestTier         sql.NullString `db:"latest_tier"`
-		RoomBannerUri      string         `db:"room_banner_uri"`
+		UserCode           string            `db:"user_code"`
+		UserName           string            `db:"user_name"`
+		UserImgUrl         string            `db:"user_image_url"`
+		UserXPlayer        string            `db:"user_x_player"`
+		StatusWinner       bool              `db:"status_winner"`
+		Status             string            `db:"status"`
+		TransactionCode    sql.NullString    `db:"transaction_code"`
+		AdditionalInfo     sql.NullString    `db:"additional_info"`
+		Position           int               `db:"position"`
+		RewardPoint        sql.NullInt64     `db:"reward_point"`
+		RoomId             int64             `db:"room_id"`
+		UserId             int64             `db:"user_id"`
+		ParticipationPoint int               `db:"participation_point"`
+		LatestTier         sql.NullString    `db:"latest_tier"`
+		RoomBannerUri      string            `db:"room_banner_uri"`
+		Score              sql.NullInt64     `db:"score"`
+		Rank               sql.NullInt64     `db:"rank"`
+		IsTied             bool              `db:"is_tied"`
+		Stats              map[string]string `db:"stats"`
+	}
+
+	// RoomResultEnt is the final result of a participant of a room. A zero
+	// Rank leaves the participant unranked.
+	RoomResultEnt struct {
+		Score  sql.NullInt64
+		Rank   int
+		IsTied bool
+		Stats  map[string]string
 	}
 )
 
@@ -56,12 +69,17 @@ func (c *Contract) GetAllParticipantByRoomCode(db *pgxpool.Pool, ctx context.Con
 			rp.position,
 			rp.additional_info,
 			rp.reward_point,
-			tr.name as latest_tier_name 
+			tr.name as latest_tier_name,
+			rp.score,
+			rp."rank",
+			rp.is_tied,
+			rp.stats
 			FROM rooms r
 				JOIN rooms_participants rp ON rp.room_id = r.id
 				JOIN users u ON rp.user_id = u.id
 				LEFT JOIN tiers tr ON tr.id = u.latest_tier_id 
-			WHERE room_code = $1 AND rp.status = 'active' `
+			WHERE room_code = $1 AND rp.status = 'active'
+			ORDER BY rp."rank" NULLS LAST, rp.id`
 	)
 
 	rows, err := db.Query(ctx, query, code)
@@ -77,6 +95,7 @@ func (c *Contract) GetAllParticipantByRoomCode(db *pgxpool.Pool, ctx context.Con
 			&data.StatusWinner, &data.Status,
 			&data.Position, &data.AdditionalInfo, &data.RewardPoint,
 			&data.LatestTier,
+			&data.Score, &data.Rank, &data.IsTied, &data.Stats,
 		)
 		if err != nil {
 			return list, c.errHandler("model.GetAllParticipantByRoomCode", err, utils.ErrScanningAllParticipantByRoomCode)
@@ -215,7 +234,8 @@ func (c *Contract) CountRoomParticipantByUserIdAndStartDateAndEndDate(db *pgxpoo
 	return count, nil
 }
 
-func (c *Contract) CountRoomParticipantByUserIdAndStartDateAndLifeTime(db *pgxpool.Pool, ctx context.Context, userId int64, startDate string, endDate string) (int64, error) {
+// CountRoomParticipantByUserIdAndStartDateAndLifeTime counts active participations starting on or after startDate.
+func (c *Contract) CountRoomParticipantByUserIdAndStartDateAndLifeTime(db *pgxpool.Pool, ctx context.Context, userId int64, startDate string) (int64, error) {
 	var count int64
 	query := `
 		SELECT COUNT(*)
@@ -223,10 +243,10 @@ func (c *Contract) CountRoomParticipantByUserIdAndStartDateAndLifeTime(db *pgxpo
 		LEFT JOIN rooms r ON rp.room_id = r.id
 		LEFT JOIN games g ON g.id = r.game_id
 		LEFT JOIN users u ON rp.user_id = u.id
-		WHERE u.id = $1 AND rp.status = 'active' AND (r.start_date BETWEEN $2 AND $3) AND r.end_date = $4
+		WHERE u.id = $1 AND rp.status = 'active' AND r.start_date >= $2
 	`
 
-	err := db.QueryRow(ctx, query, userId, startDate, startDate, nil).Scan(&count)
+	err := db.QueryRow(ctx, query, userId, startDate).Scan(&count)
 	if err != nil {
 		return 0, c.errHandler("model.CountRoomParticipantByUserIdAndStartDateAndEndDate", err, utils.ErrCountParticipantRoomByStartDateAndEndDate)
 	}
@@ -260,6 +280,28 @@ func (c *Contract) UpdateRoomParticipant(tx pgx.Tx, ctx context.Context, roomId,
 	return nil
 }
 
+// SetRoomParticipantResultTrx records the final result of a participant of a room.
+func (c *Contract) SetRoomParticipantResultTrx(tx pgx.Tx, ctx context.Context, roomId, userId int64, result RoomResultEnt) error {
+	var (
+		rank  sql.NullInt64
+		stats interface{}
+	)
+	if result.Rank > 0 {
+		rank = sql.NullInt64{Int64: int64(result.Rank), Valid: true}
+	}
+	if len(result.Stats) > 0 {
+		stats = result.Stats
+	}
+
+	query := `UPDATE rooms_participants SET score = $1, "rank" = $2, is_tied = $3, stats = $4, updated_date = $5 WHERE room_id = $6 AND user_id = $7`
+	_, err := tx.Exec(ctx, query, result.Score, rank, result.IsTied, stats, time.Now().UTC(), roomId, userId)
+	if err != nil {
+		return c.errHandler("model.SetRoomParticipantResultTrx", err, utils.ErrSettingRoomResult)
+	}
+
+	return nil
+}
+
 func (c *Contract) DeleteRoomParticipant(tx pgx.Tx, ctx context.Context, roomId, userId int64) error {
 	var (
 		err   error
@@ -279,10 +321,11 @@ func (c *Contract) CountParticipantRoomByRoomId(db *pgxpool.Pool, ctx context.Co
 
 		queryGetTotalParticipant = `select count(id) from rooms_participants rp 
 		where rp.status != 'cancel' and room_id = $1
+		and (rp.status != 'pending' or rp.hold_expired_date is null or rp.hold_expired_date > $2)
 		group by room_id `
 	)
 
-	err = db.QueryRow(ctx, queryGetTotalParticipant, id).Scan(
+	err = db.QueryRow(ctx, queryGetTotalParticipant, id, time.Now().UTC()).Scan(
 		&totalParticipant,
 	)
 	if err != nil && err != pgx.ErrNoRows {
@@ -0,0 +1,319 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/onesignal"
+	"dots-api/lib/utils"
+	"dots-api/services/api/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	RoomProposalEnt struct {
+		Id                 int64          `db:"id"`
+		ProposalCode       string         `db:"proposal_code"`
+		UserId             int64          `db:"user_id"`
+		UserCode           string         `db:"user_code"`
+		UserName           string         `db:"user_name"`
+		GameId             int64          `db:"game_id"`
+		GameCode           string         `db:"game_code"`
+		GameName           string         `db:"game_name"`
+		GameImgUrl         string         `db:"game_img_url"`
+		CafeCode           string         `db:"cafe_code"`
+		CafeName           string         `db:"cafe_name"`
+		Name               string         `db:"name"`
+		Description        string         `db:"description"`
+		StartDate          time.Time      `db:"start_date"`
+		StartTime          time.Time      `db:"start_time"`
+		EndTime            time.Time      `db:"end_time"`
+		MaximumParticipant int            `db:"maximum_participant"`
+		Status             string         `db:"status"`
+		RoomCode           sql.NullString `db:"room_code"`
+		RejectReason       sql.NullString `db:"reject_reason"`
+		ReviewedBy         sql.NullString `db:"reviewed_by"`
+		ReviewedDate       sql.NullTime   `db:"reviewed_date"`
+		CreatedDate        time.Time      `db:"created_date"`
+	}
+
+	// ProposedRoomEnt is what the reviewing admin adds to a proposal to open
+	// it as a room.
+	ProposedRoomEnt struct {
+		RoomCode      string
+		GameMasterId  int64
+		RoomType      string
+		Difficulty    string
+		Instruction   string
+		BookingPrice  float64
+		RewardPoint   int
+		InstagramLink string
+		ImageUrl      string
+		LocationCity  string
+		InviteCode    string
+	}
+)
+
+const roomProposalQuery = `
+	SELECT
+		rp.id, rp.proposal_code, rp.user_id, u.user_code, COALESCE(u.username, ''),
+		rp.game_id, g.game_code, g.name, COALESCE(g.image_url, ''), c.cafe_code, c.name,
+		rp.name, rp.description, rp.start_date, rp.start_time, rp.end_time, rp.maximum_participant,
+		rp.status, r.room_code, rp.reject_reason, rp.reviewed_by, rp.reviewed_date, rp.created_date
+	FROM room_proposals rp
+		JOIN users u ON u.id = rp.user_id
+		JOIN games g ON g.id = rp.game_id
+		JOIN cafes c ON c.id = g.cafe_id
+		LEFT JOIN rooms r ON r.id = rp.room_id`
+
+func scanRoomProposal(row pgx.Row, data *RoomProposalEnt) error {
+	return row.Scan(
+		&data.Id, &data.ProposalCode, &data.UserId, &data.UserCode, &data.UserName,
+		&data.GameId, &data.GameCode, &data.GameName, &data.GameImgUrl, &data.CafeCode, &data.CafeName,
+		&data.Name, &data.Description, &data.StartDate, &data.StartTime, &data.EndTime, &data.MaximumParticipant,
+		&data.Status, &data.RoomCode, &data.RejectReason, &data.ReviewedBy, &data.ReviewedDate, &data.CreatedDate,
+	)
+}
+
+// GetRoomProposalList returns the approval queue of the cafes, or the
+// proposals of one member when param.UserCode is set.
+func (c *Contract) GetRoomProposalList(db *pgxpool.Pool, ctx context.Context, param request.RoomProposalParam) ([]RoomProposalEnt, request.RoomProposalParam, error) {
+	var (
+		err        error
+		list       []RoomProposalEnt
+		where      []string
+		paramQuery []interface{}
+		totalData  int
+		query      = roomProposalQuery
+	)
+
+	if len(param.Keyword) > 0 {
+		paramQuery = append(paramQuery, "%"+param.Keyword+"%")
+		where = append(where, fmt.Sprintf("rp.name ILIKE $%d", len(paramQuery)))
+	}
+
+	if len(param.Status) > 0 {
+		paramQuery = append(paramQuery, param.Status)
+		where = append(where, fmt.Sprintf("rp.status = $%d", len(paramQuery)))
+	}
+
+	if len(param.CafeCode) > 0 {
+		paramQuery = append(paramQuery, param.CafeCode)
+		where = append(where, fmt.Sprintf("c.cafe_code = $%d", len(paramQuery)))
+	}
+
+	if len(param.UserCode) > 0 {
+		paramQuery = append(paramQuery, param.UserCode)
+		where = append(where, fmt.Sprintf("u.user_code = $%d", len(paramQuery)))
+	}
+
+	// Append All Where Conditions
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Count Query
+	newQcount := `SELECT COUNT(*) FROM ( ` + query + ` ) AS data`
+	err = db.QueryRow(ctx, newQcount, paramQuery...).Scan(&totalData)
+	if err != nil {
+		return list, param, c.errHandler("model.GetRoomProposalList", err, utils.ErrCountingRoomProposals)
+	}
+	param.Count = totalData
+
+	// Select Max Page
+	if param.Count > param.Limit && param.Page > int(param.Count/param.Limit) {
+		param.MaxPage = int(math.Ceil(float64(param.Count) / float64(param.Limit)))
+	} else {
+		param.MaxPage = int(param.Count / param.Limit)
+	}
+
+	// Limit and Offset
+	param.Offset = (param.Page - 1) * param.Limit
+	query += " ORDER BY " + param.Order + " " + param.Sort + " "
+
+	paramQuery = append(paramQuery, param.Offset)
+	query += fmt.Sprintf("offset $%d ", len(paramQuery))
+
+	paramQuery = append(paramQuery, param.Limit)
+	query += fmt.Sprintf("limit $%d ", len(paramQuery))
+
+	rows, err := db.Query(ctx, query, paramQuery...)
+	if err != nil {
+		return list, param, c.errHandler("model.GetRoomProposalList", err, utils.ErrGettingRoomProposals)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data RoomProposalEnt
+		if err = scanRoomProposal(rows, &data); err != nil {
+			return list, param, c.errHandler("model.GetRoomProposalList", err, utils.ErrGettingRoomProposals)
+		}
+		list = append(list, data)
+	}
+
+	return list, param, nil
+}
+
+func (c *Contract) GetRoomProposalByCode(db *pgxpool.Pool, ctx context.Context, code string) (RoomProposalEnt, error) {
+	var data RoomProposalEnt
+
+	err := scanRoomProposal(db.QueryRow(ctx, roomProposalQuery+` WHERE rp.proposal_code = $1`, code), &data)
+	if err != nil {
+		return data, c.errHandler("model.GetRoomProposalByCode", err, utils.ErrGettingRoomProposal)
+	}
+
+	return data, nil
+}
+
+// GetProposableGameId returns the ID of an active game of the cafe library
+// members can propose a session of.
+func (c *Contract) GetProposableGameId(db *pgxpool.Pool, ctx context.Context, code string) (int64, error) {
+	var id int64
+
+	err := db.QueryRow(ctx, `SELECT id FROM games WHERE game_code = $1 AND status = 'active' AND deleted_date IS NULL`, code).Scan(&id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return id, errors.New(utils.ErrRoomProposalGameInactive)
+		}
+		return id, c.errHandler("model.GetProposableGameId", err, utils.ErrGettingGameByCode)
+	}
+
+	return id, nil
+}
+
+func (c *Contract) AddRoomProposal(db *pgxpool.Pool, ctx context.Context, data RoomProposalEnt) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO room_proposals (proposal_code, user_id, game_id, "name", description, start_date, start_time, end_time, maximum_participant, status, created_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		data.ProposalCode, data.UserId, data.GameId, data.Name, data.Description, data.StartDate, data.StartTime, data.EndTime,
+		data.MaximumParticipant, utils.RoomProposalPending, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.AddRoomProposal", err, utils.ErrAddingRoomProposal)
+	}
+
+	return nil
+}
+
+// CancelRoomProposal withdraws a proposal still waiting for review.
+func (c *Contract) CancelRoomProposal(db *pgxpool.Pool, ctx context.Context, id int64) error {
+	tag, err := db.Exec(ctx, `UPDATE room_proposals SET status = $1, updated_date = $2 WHERE id = $3 AND status = $4`,
+		utils.RoomProposalCancelled, time.Now().UTC(), id, utils.RoomProposalPending)
+	if err != nil {
+		return c.errHandler("model.CancelRoomProposal", err, utils.ErrCancellingRoomProposal)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrRoomProposalNotPending)
+	}
+
+	return nil
+}
+
+// ApproveRoomProposalTrx opens a pending proposal as an active public room
+// hosted by the proposing member.
+func (c *Contract) ApproveRoomProposalTrx(tx pgx.Tx, ctx context.Context, data RoomProposalEnt, room ProposedRoomEnt, adminCode string) error {
+	now := time.Now().UTC()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE room_proposals SET status = $1, reviewed_by = $2, reviewed_date = $3, updated_date = $3
+		WHERE id = $4 AND status = $5`,
+		utils.RoomProposalApproved, adminCode, now, data.Id, utils.RoomProposalPending)
+	if err != nil {
+		return c.errHandler("model.ApproveRoomProposalTrx", err, utils.ErrReviewingRoomProposal)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrRoomProposalNotPending)
+	}
+
+	var roomId int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO rooms (
+			game_master_id, game_id, room_code, room_type, "name", description, instruction, difficulty,
+			start_date, end_date, start_time, end_time, maximum_participant, booking_price, reward_point,
+			instagram_link, image_url, location_city, status, created_date, updated_date,
+			visibility, invite_code, host_user_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $10, $11, $12, $13, $14, $15, $16, $17, 'active', $18, $18, $19, $20, $21)
+		RETURNING id`,
+		room.GameMasterId, data.GameId, room.RoomCode, room.RoomType, data.Name, data.Description, room.Instruction, room.Difficulty,
+		data.StartDate, data.StartTime, data.EndTime, data.MaximumParticipant, room.BookingPrice, room.RewardPoint,
+		room.InstagramLink, room.ImageUrl, room.LocationCity, now,
+		utils.RoomVisibilityPublic, room.InviteCode, data.UserId,
+	).Scan(&roomId)
+	if err != nil {
+		return c.errHandler("model.ApproveRoomProposalTrx", err, utils.ErrAddingRoom)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE room_proposals SET room_id = $1 WHERE id = $2`, roomId, data.Id)
+	if err != nil {
+		return c.errHandler("model.ApproveRoomProposalTrx", err, utils.ErrReviewingRoomProposal)
+	}
+
+	return nil
+}
+
+// RejectRoomProposal declines a pending proposal with the reason shown to the
+// member.
+func (c *Contract) RejectRoomProposal(db *pgxpool.Pool, ctx context.Context, id int64, reason, adminCode string) error {
+	now := time.Now().UTC()
+	tag, err := db.Exec(ctx, `
+		UPDATE room_proposals SET status = $1, reject_reason = $2, reviewed_by = $3, reviewed_date = $4, updated_date = $4
+		WHERE id = $5 AND status = $6`,
+		utils.RoomProposalRejected, reason, adminCode, now, id, utils.RoomProposalPending)
+	if err != nil {
+		return c.errHandler("model.RejectRoomProposal", err, utils.ErrReviewingRoomProposal)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrRoomProposalNotPending)
+	}
+
+	return nil
+}
+
+// NotifyRoomProposalReviewed tells a member their proposal was approved, with
+// the code of its room, or rejected with its reason, in the app and by push
+// notification. A failed notification is only logged.
+func (c *Contract) NotifyRoomProposalReviewed(db *pgxpool.Pool, ctx context.Context, data RoomProposalEnt, roomCode, reason string) {
+	var (
+		xPlayer     string
+		sourceCode  = data.ProposalCode
+		nType       = utils.RoomProposalRejectedType
+		title       = utils.RoomProposalRejectedTitle
+		description = fmt.Sprintf(utils.RoomProposalRejectedDescription, data.Name, reason)
+	)
+
+	if roomCode != "" {
+		sourceCode = roomCode
+		nType = utils.RoomProposalApprovedType
+		title = utils.RoomProposalApprovedTitle
+		description = fmt.Sprintf(utils.RoomProposalApprovedDescription, data.Name)
+	}
+
+	err := db.QueryRow(ctx, `SELECT COALESCE(x_player, '') FROM users WHERE id = $1`, data.UserId).Scan(&xPlayer)
+	if err != nil {
+		log.Printf("Error : %s", c.errHandler("model.NotifyRoomProposalReviewed", err, utils.ErrGettingUserData))
+		return
+	}
+
+	descriptionJSON, err := json.Marshal(description)
+	if err != nil {
+		log.Printf("Error : %s", err)
+		return
+	}
+
+	err = c.AddNotification(db, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "user", data.UserCode, sourceCode, nType, title, descriptionJSON, data.GameImgUrl)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	}
+
+	_, err = onesignal.New(c.App).CreateOSNotifications(xPlayer, title, description, utils.Room)
+	if err != nil {
+		log.Printf("Error : %s", err)
+	}
+}
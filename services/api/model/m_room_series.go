@@ -0,0 +1,489 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/onesignal"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/request"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	RoomSeriesEnt struct {
+		Id                 int64          `db:"id"`
+		SeriesCode         string         `db:"series_code"`
+		GameMasterId       int64          `db:"game_master_id"`
+		GameMasterCode     sql.NullString `db:"game_master_code"`
+		GameMasterName     sql.NullString `db:"game_master_name"`
+		GameId             int64          `db:"game_id"`
+		GameCode           string         `db:"game_code"`
+		GameName           string         `db:"game_name"`
+		RoomType           string         `db:"room_type"`
+		Name               string         `db:"name"`
+		Description        string         `db:"description"`
+		Instruction        string         `db:"instruction"`
+		Difficulty         string         `db:"difficulty"`
+		StartTime          time.Time      `db:"start_time"`
+		EndTime            time.Time      `db:"end_time"`
+		MaximumParticipant int            `db:"maximum_participant"`
+		BookingPrice       float64        `db:"booking_price"`
+		RewardPoint        int            `db:"reward_point"`
+		InstagramLink      string         `db:"instagram_link"`
+		ImageUrl           string         `db:"image_url"`
+		LocationCity       string         `db:"location_city"`
+		Frequency          string         `db:"frequency"`
+		Weekdays           []int32        `db:"weekdays"`
+		StartDate          time.Time      `db:"start_date"`
+		UntilDate          sql.NullTime   `db:"until_date"`
+		OccurrenceCount    int            `db:"occurrence_count"`
+		SkipDates          []time.Time    `db:"skip_dates"`
+		GeneratedUntil     sql.NullTime   `db:"generated_until"`
+		Status             string         `db:"status"`
+		SubscriberCount    int            `db:"subscriber_count"`
+		CreatedDate        time.Time      `db:"created_date"`
+	}
+
+	// RoomSeriesOccurrenceEnt is a room created for one date of a series.
+	RoomSeriesOccurrenceEnt struct {
+		RoomCode       string    `db:"room_code"`
+		OccurrenceDate time.Time `db:"occurrence_date"`
+		Status         string    `db:"status"`
+		IsDetached     bool      `db:"is_detached"`
+	}
+)
+
+const roomSeriesQuery = `
+	SELECT
+		rs.id, rs.series_code, rs.game_master_id, a.admin_code, a.name, rs.game_id, g.game_code, g.name,
+		rs.room_type, rs.name, COALESCE(rs.description, ''), COALESCE(rs.instruction, ''), COALESCE(rs.difficulty, ''),
+		rs.start_time, rs.end_time, rs.maximum_participant, rs.booking_price, rs.reward_point,
+		COALESCE(rs.instagram_link, ''), COALESCE(rs.image_url, ''), COALESCE(rs.location_city, ''),
+		rs.frequency, rs.weekdays, rs.start_date, rs.until_date, rs.occurrence_count, rs.skip_dates, rs.generated_until,
+		rs.status,
+		(SELECT COUNT(*) FROM room_series_subscriptions rss WHERE rss.series_id = rs.id) AS subscriber_count,
+		rs.created_date
+	FROM room_series rs
+		LEFT JOIN admins a ON a.id = rs.game_master_id
+		JOIN games g ON g.id = rs.game_id`
+
+// Rule returns the recurrence rule of the series.
+func (e RoomSeriesEnt) Rule() recurrence.Rule {
+	rule := recurrence.Rule{
+		Frequency: e.Frequency,
+		Start:     e.StartDate,
+		Until:     e.UntilDate.Time,
+		Count:     e.OccurrenceCount,
+		Skip:      e.SkipDates,
+	}
+	for _, d := range e.Weekdays {
+		rule.Weekdays = append(rule.Weekdays, time.Weekday(d))
+	}
+
+	return rule
+}
+
+// Slot returns the time the room of the series on date takes its game master.
+func (e RoomSeriesEnt) Slot(date time.Time) GameMasterSlotEnt {
+	return GameMasterSlotEnt{
+		GameMasterId:    e.GameMasterId,
+		Start:           date.Add(timeOfDay(e.StartTime)),
+		End:             date.Add(timeOfDay(e.EndTime)),
+		ExcludeSeriesId: e.Id,
+	}
+}
+
+// timeOfDay returns the time passed since the start of the day of t.
+func timeOfDay(t time.Time) time.Duration {
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+func scanRoomSeries(row pgx.Row, data *RoomSeriesEnt) error {
+	return row.Scan(
+		&data.Id, &data.SeriesCode, &data.GameMasterId, &data.GameMasterCode, &data.GameMasterName, &data.GameId, &data.GameCode, &data.GameName,
+		&data.RoomType, &data.Name, &data.Description, &data.Instruction, &data.Difficulty,
+		&data.StartTime, &data.EndTime, &data.MaximumParticipant, &data.BookingPrice, &data.RewardPoint,
+		&data.InstagramLink, &data.ImageUrl, &data.LocationCity,
+		&data.Frequency, &data.Weekdays, &data.StartDate, &data.UntilDate, &data.OccurrenceCount, &data.SkipDates, &data.GeneratedUntil,
+		&data.Status, &data.SubscriberCount, &data.CreatedDate,
+	)
+}
+
+func (c *Contract) GetRoomSeriesList(db *pgxpool.Pool, ctx context.Context, param request.RoomSeriesParam) ([]RoomSeriesEnt, request.RoomSeriesParam, error) {
+	var (
+		err        error
+		list       []RoomSeriesEnt
+		where      []string
+		paramQuery []interface{}
+		totalData  int
+		query      = roomSeriesQuery
+	)
+
+	if len(param.Keyword) > 0 {
+		paramQuery = append(paramQuery, "%"+param.Keyword+"%")
+		where = append(where, fmt.Sprintf("rs.name ILIKE $%d", len(paramQuery)))
+	}
+
+	if len(param.Status) > 0 {
+		paramQuery = append(paramQuery, param.Status)
+		where = append(where, fmt.Sprintf("rs.status = $%d", len(paramQuery)))
+	}
+
+	// Append All Where Conditions
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Count Query
+	newQcount := `SELECT COUNT(*) FROM ( ` + query + ` ) AS data`
+	err = db.QueryRow(ctx, newQcount, paramQuery...).Scan(&totalData)
+	if err != nil {
+		return list, param, c.errHandler("model.GetRoomSeriesList", err, utils.ErrCountingRoomSeries)
+	}
+	param.Count = totalData
+
+	// Select Max Page
+	if param.Count > param.Limit && param.Page > int(param.Count/param.Limit) {
+		param.MaxPage = int(math.Ceil(float64(param.Count) / float64(param.Limit)))
+	} else {
+		param.MaxPage = int(param.Count / param.Limit)
+	}
+
+	// Limit and Offset
+	param.Offset = (param.Page - 1) * param.Limit
+	query += " ORDER BY " + param.Order + " " + param.Sort + " "
+
+	paramQuery = append(paramQuery, param.Offset)
+	query += fmt.Sprintf("offset $%d ", len(paramQuery))
+
+	paramQuery = append(paramQuery, param.Limit)
+	query += fmt.Sprintf("limit $%d ", len(paramQuery))
+
+	rows, err := db.Query(ctx, query, paramQuery...)
+	if err != nil {
+		return list, param, c.errHandler("model.GetRoomSeriesList", err, utils.ErrGettingRoomSeries)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data RoomSeriesEnt
+		if err = scanRoomSeries(rows, &data); err != nil {
+			return list, param, c.errHandler("model.GetRoomSeriesList", err, utils.ErrScanningRoomSeries)
+		}
+		list = append(list, data)
+	}
+
+	return list, param, nil
+}
+
+func (c *Contract) GetRoomSeriesByCode(db *pgxpool.Pool, ctx context.Context, code string) (RoomSeriesEnt, error) {
+	var data RoomSeriesEnt
+
+	err := scanRoomSeries(db.QueryRow(ctx, roomSeriesQuery+` WHERE rs.series_code = $1`, code), &data)
+	if err != nil {
+		return data, c.errHandler("model.GetRoomSeriesByCode", err, utils.ErrGettingRoomSeries)
+	}
+
+	return data, nil
+}
+
+// GetRoomSeriesToGenerate returns the active series whose rooms are not yet
+// created up to upTo.
+func (c *Contract) GetRoomSeriesToGenerate(db *pgxpool.Pool, ctx context.Context, upTo time.Time) ([]RoomSeriesEnt, error) {
+	var list []RoomSeriesEnt
+
+	rows, err := db.Query(ctx, roomSeriesQuery+` WHERE rs.status = 'active' AND (rs.generated_until IS NULL OR rs.generated_until < $1) ORDER BY rs.id`, upTo)
+	if err != nil {
+		return nil, c.errHandler("model.GetRoomSeriesToGenerate", err, utils.ErrGettingRoomSeries)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data RoomSeriesEnt
+		if err = scanRoomSeries(rows, &data); err != nil {
+			return nil, c.errHandler("model.GetRoomSeriesToGenerate", err, utils.ErrScanningRoomSeries)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+func (c *Contract) AddRoomSeriesTrx(tx pgx.Tx, ctx context.Context, data RoomSeriesEnt) (int64, error) {
+	var id int64
+
+	query := `
+		INSERT INTO room_series (
+			series_code, game_master_id, game_id, room_type, "name", description, instruction, difficulty,
+			start_time, end_time, maximum_participant, booking_price, reward_point, instagram_link, image_url, location_city,
+			frequency, weekdays, start_date, until_date, occurrence_count, skip_dates, status, created_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+		RETURNING id`
+	err := tx.QueryRow(ctx, query,
+		data.SeriesCode, data.GameMasterId, data.GameId, data.RoomType, data.Name, data.Description, data.Instruction, data.Difficulty,
+		data.StartTime, data.EndTime, data.MaximumParticipant, data.BookingPrice, data.RewardPoint, data.InstagramLink, data.ImageUrl, data.LocationCity,
+		data.Frequency, data.Weekdays, data.StartDate, data.UntilDate, data.OccurrenceCount, data.SkipDates, data.Status, time.Now().UTC(),
+	).Scan(&id)
+	if err != nil {
+		return 0, c.errHandler("model.AddRoomSeriesTrx", err, utils.ErrAddingRoomSeries)
+	}
+
+	return id, nil
+}
+
+// UpdateRoomSeriesTrx updates the room template and the recurrence rule of a
+// series.
+func (c *Contract) UpdateRoomSeriesTrx(tx pgx.Tx, ctx context.Context, data RoomSeriesEnt) error {
+	query := `
+		UPDATE room_series SET
+			game_master_id = $1, game_id = $2, room_type = $3, "name" = $4, description = $5, instruction = $6, difficulty = $7,
+			start_time = $8, end_time = $9, maximum_participant = $10, booking_price = $11, reward_point = $12,
+			instagram_link = $13, image_url = $14, location_city = $15,
+			frequency = $16, weekdays = $17, start_date = $18, until_date = $19, occurrence_count = $20, skip_dates = $21,
+			status = $22, updated_date = $23
+		WHERE id = $24`
+	_, err := tx.Exec(ctx, query,
+		data.GameMasterId, data.GameId, data.RoomType, data.Name, data.Description, data.Instruction, data.Difficulty,
+		data.StartTime, data.EndTime, data.MaximumParticipant, data.BookingPrice, data.RewardPoint,
+		data.InstagramLink, data.ImageUrl, data.LocationCity,
+		data.Frequency, data.Weekdays, data.StartDate, data.UntilDate, data.OccurrenceCount, data.SkipDates,
+		data.Status, time.Now().UTC(), data.Id,
+	)
+	if err != nil {
+		return c.errHandler("model.UpdateRoomSeriesTrx", err, utils.ErrUpdatingRoomSeries)
+	}
+
+	return nil
+}
+
+// UpdateFutureSeriesRoomsTrx copies the room template of a series to its rooms
+// taking place from from on. Closed or cancelled rooms and rooms edited on
+// their own keep their values.
+func (c *Contract) UpdateFutureSeriesRoomsTrx(tx pgx.Tx, ctx context.Context, data RoomSeriesEnt, from time.Time) error {
+	query := `
+		UPDATE rooms SET
+			game_master_id = $1, game_id = $2, room_type = $3, "name" = $4, description = $5, instruction = $6, difficulty = $7,
+			start_time = $8, end_time = $9, maximum_participant = $10, booking_price = $11, reward_point = $12,
+			instagram_link = $13, image_url = $14, location_city = $15, status = $16, updated_date = $17
+		WHERE series_id = $18 AND occurrence_date >= $19 AND NOT is_detached AND deleted_date IS NULL AND status NOT IN ('closed', 'cancelled')`
+	_, err := tx.Exec(ctx, query,
+		data.GameMasterId, data.GameId, data.RoomType, data.Name, data.Description, data.Instruction, data.Difficulty,
+		data.StartTime, data.EndTime, data.MaximumParticipant, data.BookingPrice, data.RewardPoint,
+		data.InstagramLink, data.ImageUrl, data.LocationCity, data.Status, time.Now().UTC(),
+		data.Id, from,
+	)
+	if err != nil {
+		return c.errHandler("model.UpdateFutureSeriesRoomsTrx", err, utils.ErrUpdatingRoomSeries)
+	}
+
+	return nil
+}
+
+// RemoveSeriesRoomsTrx removes the rooms of a series taking place from from
+// on whose date is not in dates anymore, e.g. a skipped holiday. The rooms are
+// soft deleted, so their history stays. Rooms edited on their own are kept.
+// It fails when one of them already has bookings.
+func (c *Contract) RemoveSeriesRoomsTrx(tx pgx.Tx, ctx context.Context, seriesId int64, from time.Time, dates []time.Time) error {
+	var booked int
+
+	where := `r.series_id = $1 AND r.occurrence_date >= $2 AND NOT r.is_detached AND r.deleted_date IS NULL AND NOT (r.occurrence_date = ANY($3::date[]))`
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM rooms r
+			JOIN rooms_participants rp ON rp.room_id = r.id AND rp.status != 'cancel'
+		WHERE `+where, seriesId, from, dates).Scan(&booked)
+	if err != nil {
+		return c.errHandler("model.RemoveSeriesRoomsTrx", err, utils.ErrUpdatingRoomSeries)
+	}
+	if booked > 0 {
+		return errors.New(utils.ErrRoomSeriesOccurrenceBooked)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE rooms r SET deleted_date = $4 WHERE `+where, seriesId, from, dates, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.RemoveSeriesRoomsTrx", err, utils.ErrUpdatingRoomSeries)
+	}
+
+	return nil
+}
+
+// GenerateRoomSeriesTrx creates the rooms of a series for its dates from from
+// up to upTo, skipping the dates up to its generated_until, and returns the
+// new rooms. A date that already has a room is left alone. When check is set,
+// a date it fails for gets no room and the error is logged.
+func (c *Contract) GenerateRoomSeriesTrx(tx pgx.Tx, ctx context.Context, data RoomSeriesEnt, from, upTo time.Time, check func(date time.Time) error) ([]RoomSeriesOccurrenceEnt, error) {
+	var (
+		list []RoomSeriesOccurrenceEnt
+		now  = time.Now().UTC()
+	)
+
+	if data.GeneratedUntil.Valid && !data.GeneratedUntil.Time.Before(from) {
+		from = data.GeneratedUntil.Time.AddDate(0, 0, 1)
+	}
+
+	query := `
+		INSERT INTO rooms (
+			game_master_id, game_id, room_code, room_type, "name", description, instruction, difficulty,
+			start_date, end_date, start_time, end_time, maximum_participant, booking_price, reward_point,
+			instagram_link, image_url, location_city, status, created_date, updated_date, series_id, occurrence_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $19, $20, $9)
+		ON CONFLICT (series_id, occurrence_date) WHERE series_id IS NOT NULL AND deleted_date IS NULL DO NOTHING`
+	for _, date := range data.Rule().Dates(upTo) {
+		if date.Before(from) {
+			continue
+		}
+
+		if check != nil {
+			if err := check(date); err != nil {
+				log.Printf("Error : skipping the %s room of series %s: %s", date.Format(utils.DATE_FORMAT), data.SeriesCode, err)
+				continue
+			}
+		}
+
+		code := utils.GeneratePrefixCode(utils.RoomPrefix)
+		tag, err := tx.Exec(ctx, query,
+			data.GameMasterId, data.GameId, code, data.RoomType, data.Name, data.Description, data.Instruction, data.Difficulty,
+			date, data.StartTime, data.EndTime, data.MaximumParticipant, data.BookingPrice, data.RewardPoint,
+			data.InstagramLink, data.ImageUrl, data.LocationCity, data.Status, now, data.Id,
+		)
+		if err != nil {
+			return nil, c.errHandler("model.GenerateRoomSeriesTrx", err, utils.ErrGeneratingRoomSeries)
+		}
+		if tag.RowsAffected() > 0 {
+			list = append(list, RoomSeriesOccurrenceEnt{RoomCode: code, OccurrenceDate: date, Status: data.Status})
+		}
+	}
+
+	_, err := tx.Exec(ctx, `UPDATE room_series SET generated_until = $1 WHERE id = $2`, upTo, data.Id)
+	if err != nil {
+		return nil, c.errHandler("model.GenerateRoomSeriesTrx", err, utils.ErrGeneratingRoomSeries)
+	}
+
+	return list, nil
+}
+
+// GetRoomSeriesOccurrences returns the rooms of a series taking place from
+// from on.
+func (c *Contract) GetRoomSeriesOccurrences(db *pgxpool.Pool, ctx context.Context, seriesId int64, from time.Time) ([]RoomSeriesOccurrenceEnt, error) {
+	var list []RoomSeriesOccurrenceEnt
+
+	query := `
+		SELECT room_code, occurrence_date, status, is_detached FROM rooms
+		WHERE series_id = $1 AND occurrence_date >= $2 AND deleted_date IS NULL
+		ORDER BY occurrence_date`
+	rows, err := db.Query(ctx, query, seriesId, from)
+	if err != nil {
+		return nil, c.errHandler("model.GetRoomSeriesOccurrences", err, utils.ErrGettingRoomSeries)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data RoomSeriesOccurrenceEnt
+		if err = rows.Scan(&data.RoomCode, &data.OccurrenceDate, &data.Status, &data.IsDetached); err != nil {
+			return nil, c.errHandler("model.GetRoomSeriesOccurrences", err, utils.ErrScanningRoomSeries)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+func (c *Contract) SubscribeRoomSeries(db *pgxpool.Pool, ctx context.Context, seriesId, userId int64) error {
+	query := `INSERT INTO room_series_subscriptions (series_id, user_id, created_date) VALUES ($1, $2, $3) ON CONFLICT (series_id, user_id) DO NOTHING`
+	_, err := db.Exec(ctx, query, seriesId, userId, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.SubscribeRoomSeries", err, utils.ErrSubscribingRoomSeries)
+	}
+
+	return nil
+}
+
+func (c *Contract) UnsubscribeRoomSeries(db *pgxpool.Pool, ctx context.Context, seriesId, userId int64) error {
+	_, err := db.Exec(ctx, `DELETE FROM room_series_subscriptions WHERE series_id = $1 AND user_id = $2`, seriesId, userId)
+	if err != nil {
+		return c.errHandler("model.UnsubscribeRoomSeries", err, utils.ErrSubscribingRoomSeries)
+	}
+
+	return nil
+}
+
+// IsRoomSeriesSubscriber reports whether a member is subscribed to a series.
+func (c *Contract) IsRoomSeriesSubscriber(db *pgxpool.Pool, ctx context.Context, seriesId int64, userCode string) (bool, error) {
+	var exists bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM room_series_subscriptions rss
+				JOIN users u ON u.id = rss.user_id
+			WHERE rss.series_id = $1 AND u.user_code = $2
+		)`
+	err := db.QueryRow(ctx, query, seriesId, userCode).Scan(&exists)
+	if err != nil {
+		return false, c.errHandler("model.IsRoomSeriesSubscriber", err, utils.ErrGettingRoomSeries)
+	}
+
+	return exists, nil
+}
+
+// NotifyRoomSeriesSubscribers tells the subscribers of a series that its new
+// rooms are open for booking, in the app and by push notification. A failed
+// notification is only logged.
+func (c *Contract) NotifyRoomSeriesSubscribers(db *pgxpool.Pool, ctx context.Context, data RoomSeriesEnt, occurrences []RoomSeriesOccurrenceEnt) {
+	if len(occurrences) == 0 || data.Status != utils.RoomStatus["ACTIVE"] {
+		return
+	}
+
+	query := `
+		SELECT u.user_code, COALESCE(u.x_player, '') FROM room_series_subscriptions rss
+			JOIN users u ON u.id = rss.user_id
+		WHERE rss.series_id = $1`
+	rows, err := db.Query(ctx, query, data.Id)
+	if err != nil {
+		log.Printf("Error : %s", c.errHandler("model.NotifyRoomSeriesSubscribers", err, utils.ErrGettingRoomSeries))
+		return
+	}
+
+	type subscriber struct{ userCode, xPlayer string }
+	var subscribers []subscriber
+	for rows.Next() {
+		var s subscriber
+		if err = rows.Scan(&s.userCode, &s.xPlayer); err != nil {
+			rows.Close()
+			log.Printf("Error : %s", c.errHandler("model.NotifyRoomSeriesSubscribers", err, utils.ErrScanningRoomSeries))
+			return
+		}
+		subscribers = append(subscribers, s)
+	}
+	rows.Close()
+
+	push := onesignal.New(c.App)
+	for _, occurrence := range occurrences {
+		description := fmt.Sprintf(utils.RoomSeriesOccurrenceDescription, data.Name, occurrence.OccurrenceDate.Format(utils.DATE_FORMAT))
+		descriptionJSON, err := json.Marshal(description)
+		if err != nil {
+			log.Printf("Error : %s", err)
+			continue
+		}
+
+		for _, s := range subscribers {
+			err = c.AddNotification(db, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "user", s.userCode, occurrence.RoomCode, utils.RoomSeriesOccurrenceType, utils.RoomSeriesOccurrenceTitle, descriptionJSON, data.ImageUrl)
+			if err != nil {
+				log.Printf("Error : %s", err)
+			}
+
+			_, err = push.CreateOSNotifications(s.xPlayer, utils.RoomSeriesOccurrenceTitle, description, utils.Room)
+			if err != nil {
+				log.Printf("Error : %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var (
+	fullyBookedErrors = map[string]string{
+		utils.WaitlistRoom:       utils.ErrRoomFullyBooked,
+		utils.WaitlistTournament: utils.ErrTournamentFullyBooked,
+	}
+
+	// takenSeatCondition matches the participants that take a seat: every
+	// booking that is not cancelled, except unpaid ones whose hold expired at $3.
+	takenSeatCondition = `status != 'cancel' AND (status != 'pending' OR hold_expired_date IS NULL OR hold_expired_date > $3)`
+)
+
+// countTakenSeatsTrx counts the seats of an event taken by other participants
+// than userId.
+func (c *Contract) countTakenSeatsTrx(tx pgx.Tx, ctx context.Context, table waitlistTable, eventId, userId int64, now time.Time) (int, error) {
+	var total int
+
+	query := `SELECT COUNT(*) FROM ` + table.participants + ` WHERE ` + table.participantEvent + ` = $1 AND user_id != $2 AND ` + takenSeatCondition
+	err := tx.QueryRow(ctx, query, eventId, userId, now).Scan(&total)
+	if err != nil {
+		return 0, c.errHandler("model.countTakenSeatsTrx", err, utils.ErrReservingSeat)
+	}
+
+	return total, nil
+}
+
+// ReserveSeatTrx checks that an event still has a seat for userId and keeps it
+// for them until tx ends. The event is locked, so concurrent bookings of the
+// last seat are served one after the other and only the first one gets it.
+// Seats taken by other participants and held by open waitlist offers to other
+// members are not available.
+func (c *Contract) ReserveSeatTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64) error {
+	var (
+		table, ok = waitlistTables[eventType]
+		now       = time.Now().UTC()
+		seats     int
+		held      int
+	)
+	if !ok {
+		return fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	err := tx.QueryRow(ctx, `SELECT `+table.seats+` FROM `+table.event+` WHERE id = $1 FOR UPDATE`, eventId).Scan(&seats)
+	if err != nil {
+		return c.errHandler("model.ReserveSeatTrx", err, utils.ErrReservingSeat)
+	}
+
+	taken, err := c.countTakenSeatsTrx(tx, ctx, table, eventId, userId, now)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM event_waitlists
+		WHERE event_type = $1 AND event_id = $2 AND user_id != $3 AND status = 'offered' AND offer_expired_date > $4`, eventType, eventId, userId, now).Scan(&held)
+	if err != nil {
+		return c.errHandler("model.ReserveSeatTrx", err, utils.ErrReservingSeat)
+	}
+
+	if taken+held >= seats {
+		return errors.New(fullyBookedErrors[eventType])
+	}
+
+	return nil
+}
+
+// HoldSeat reserves a seat of an event for userId and books it as pending with
+// book, in a transaction of its own. The seat is held for
+// utils.SeatHoldGraceMinutes, long enough to create the invoice of the booking
+// once the event is no longer locked. ConfirmSeatHoldTrx then keeps the seat
+// until the invoice expires.
+func (c *Contract) HoldSeat(db *pgxpool.Pool, ctx context.Context, eventType string, eventId, userId int64, book func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return c.errHandler("model.HoldSeat", err, utils.ErrReservingSeat)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = c.ReserveSeatTrx(tx, ctx, eventType, eventId, userId); err != nil {
+		return err
+	}
+
+	if err = book(tx); err != nil {
+		return err
+	}
+
+	if err = c.holdSeatTrx(tx, ctx, eventType, eventId, userId, "", time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return c.errHandler("model.HoldSeat", err, utils.ErrReservingSeat)
+	}
+
+	return nil
+}
+
+// ConfirmSeatHoldTrx links the seat held with HoldSeat to the transaction of
+// its invoice and keeps it until the invoice expires, plus
+// utils.SeatHoldGraceMinutes.
+func (c *Contract) ConfirmSeatHoldTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64, transactionCode string, invoiceExpiredDate time.Time) error {
+	return c.holdSeatTrx(tx, ctx, eventType, eventId, userId, transactionCode, invoiceExpiredDate)
+}
+
+// holdSeatTrx keeps the seat of the pending booking of userId until
+// invoiceExpiredDate plus utils.SeatHoldGraceMinutes. It fails when the hold
+// expired and the booking was released in the meantime.
+func (c *Contract) holdSeatTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64, transactionCode string, invoiceExpiredDate time.Time) error {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	holdExpiredDate := invoiceExpiredDate.UTC().Add(time.Duration(utils.SeatHoldGraceMinutes) * time.Minute)
+	query := `
+		UPDATE ` + table.participants + ` SET transaction_code = $1, hold_expired_date = $2
+		WHERE ` + table.participantEvent + ` = $3 AND user_id = $4 AND status = 'pending'`
+	result, err := tx.Exec(ctx, query, transactionCode, holdExpiredDate, eventId, userId)
+	if err != nil {
+		return c.errHandler("model.holdSeatTrx", err, utils.ErrReservingSeat)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New(utils.ErrSeatHoldExpired)
+	}
+
+	return nil
+}
+
+// ReleaseExpiredSeatHolds cancels the unpaid bookings whose hold expired and
+// offers their seats to the waitlists. It returns the number of bookings
+// released and the offers, which still need PushWaitlistOffers.
+func (c *Contract) ReleaseExpiredSeatHolds(db *pgxpool.Pool, ctx context.Context) (int, []WaitlistEnt, error) {
+	var (
+		now    = time.Now().UTC()
+		total  int
+		offers []WaitlistEnt
+	)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, nil, c.errHandler("model.ReleaseExpiredSeatHolds", err, utils.ErrReleasingSeatHolds)
+	}
+	defer tx.Rollback(ctx)
+
+	for eventType, table := range waitlistTables {
+		query := `
+			UPDATE ` + table.participants + ` SET status = 'cancel', updated_date = $1
+			WHERE status = 'pending' AND hold_expired_date <= $1
+			RETURNING ` + table.participantEvent
+		rows, err := tx.Query(ctx, query, now)
+		if err != nil {
+			return 0, nil, c.errHandler("model.ReleaseExpiredSeatHolds", err, utils.ErrReleasingSeatHolds)
+		}
+
+		events := map[int64]bool{}
+		for rows.Next() {
+			var eventId int64
+			if err = rows.Scan(&eventId); err != nil {
+				rows.Close()
+				return 0, nil, c.errHandler("model.ReleaseExpiredSeatHolds", err, utils.ErrReleasingSeatHolds)
+			}
+			events[eventId] = true
+			total++
+		}
+		rows.Close()
+
+		for eventId := range events {
+			list, err := c.OfferWaitlistSeatsTrx(tx, ctx, eventType, eventId)
+			if err != nil {
+				return 0, nil, err
+			}
+			offers = append(offers, list...)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, nil, c.errHandler("model.ReleaseExpiredSeatHolds", err, utils.ErrReleasingSeatHolds)
+	}
+
+	return total, offers, nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// TestHoldSeatDoesNotOverbook books a room from many members at once.
+func TestHoldSeatDoesNotOverbook(t *testing.T) {
+	const (
+		members = 20
+		seats   = 5
+	)
+
+	ctx := context.Background()
+	c, db := testContract(t, ctx)
+	roomId := testRoom(t, ctx, db, "ROOM1", seats)
+
+	users := make([]int64, members)
+	for i := range users {
+		users[i] = testUser(t, ctx, db, i+1)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		booked int
+		full   int
+	)
+	for _, userId := range users {
+		wg.Add(1)
+		go func(userId int64) {
+			defer wg.Done()
+
+			err := c.HoldSeat(db, ctx, utils.WaitlistRoom, roomId, userId, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `INSERT INTO rooms_participants (room_id, user_id, status) VALUES ($1, $2, 'pending')`, roomId, userId)
+				return err
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				booked++
+			case err.Error() == utils.ErrRoomFullyBooked:
+				full++
+			default:
+				t.Errorf("HoldSeat(user %d): %v", userId, err)
+			}
+		}(userId)
+	}
+	wg.Wait()
+
+	if booked != seats || full != members-seats {
+		t.Errorf("booked %d and turned away %d, want %d and %d", booked, full, seats, members-seats)
+	}
+
+	var taken int
+	err := db.QueryRow(ctx, `SELECT COUNT(*) FROM rooms_participants WHERE room_id = $1 AND status = 'pending' AND hold_expired_date IS NOT NULL`, roomId).Scan(&taken)
+	if err != nil {
+		t.Fatalf("count participants: %v", err)
+	}
+	if taken != seats {
+		t.Errorf("%d seats held, want %d", taken, seats)
+	}
+}
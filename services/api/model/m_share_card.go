@@ -0,0 +1,160 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ShareCardEnt is a rendered share card cached in the upload storage. A card
+// is rendered again when its content hash changes.
+type ShareCardEnt struct {
+	Id            int64        `db:"id"`
+	UserId        int64        `db:"user_id"`
+	CardType      string       `db:"card_type"`
+	ReferenceCode string       `db:"reference_code"`
+	ContentHash   string       `db:"content_hash"`
+	ImageURL      string       `db:"image_url"`
+	CreatedDate   time.Time    `db:"created_date"`
+	UpdatedDate   sql.NullTime `db:"updated_date"`
+}
+
+// ShareCardContentEnt is the achievement shown on a share card.
+type ShareCardContentEnt struct {
+	UserId        int64
+	ReferenceCode string
+	MemberName    string
+	Title         string
+	ImageURL      string
+	CafeName      string
+	Position      int
+	Date          sql.NullTime
+}
+
+// GetShareCardContent retrieves the achievement of a member for a card type.
+// Only earned achievements can be shared: an owned badge, the current tier or
+// a tournament placing. A badge card names the cafe of its tournament or of
+// its cafe visit rule, or else the cafe the member last played at before
+// earning it.
+func (c *Contract) GetShareCardContent(db *pgxpool.Pool, ctx context.Context, userCode, cardType, referenceCode string) (ShareCardContentEnt, error) {
+	var (
+		data  ShareCardContentEnt
+		query string
+		args  = []interface{}{userCode}
+	)
+
+	switch cardType {
+	case utils.ShareCardTypeBadge:
+		query = `
+			SELECT u.id, b.badge_code, u.fullname, b.name, b.image_url, COALESCE(cf.name, rc.name, pc.name, ''), 0, ub.created_date
+			FROM users_badges ub
+			JOIN users u ON u.id = ub.user_id
+			JOIN badges b ON b.id = ub.badge_id
+			LEFT JOIN LATERAL (
+				SELECT c.name
+				FROM tournament_badges tb
+				JOIN tournaments t ON t.id = tb.tournament_id
+				JOIN games g ON g.id = t.game_id
+				JOIN cafes c ON c.id = g.cafe_id
+				WHERE tb.badge_id = b.id
+				LIMIT 1
+			) cf ON true
+			LEFT JOIN LATERAL (
+				SELECT c.name
+				FROM badges_rules br
+				JOIN cafes c ON c.cafe_code = br.value->>'cafe_code'
+				WHERE br.badge_id = b.id AND br.key_condition = $3
+				LIMIT 1
+			) rc ON true
+			LEFT JOIN LATERAL (
+				SELECT c.name
+				FROM rooms_participants rp
+				JOIN rooms r ON r.id = rp.room_id
+				JOIN games g ON g.id = r.game_id
+				JOIN cafes c ON c.id = g.cafe_id
+				WHERE rp.user_id = u.id AND rp.status = 'active' AND r.start_date <= ub.created_date
+				ORDER BY r.start_date DESC
+				LIMIT 1
+			) pc ON true
+			WHERE u.user_code = $1 AND b.badge_code = $2`
+		args = append(args, referenceCode, utils.CafeVisit)
+	case utils.ShareCardTypeTier:
+		query = `
+			SELECT u.id, t.tier_code, u.fullname, t.name, '', '', 0, NULL::timestamp
+			FROM users u
+			JOIN tiers t ON t.id = u.latest_tier_id
+			WHERE u.user_code = $1 AND ($2 = '' OR t.tier_code = $2)`
+		args = append(args, referenceCode)
+	case utils.ShareCardTypeTournament:
+		query = `
+			SELECT u.id, t.tournament_code, u.fullname, t.name, COALESCE(t.image_url, ''), COALESCE(c.name, ''), tp.position, t.start_date::timestamp
+			FROM tournament_participants tp
+			JOIN users u ON u.id = tp.user_id
+			JOIN tournaments t ON t.id = tp.tournament_id
+			JOIN games g ON g.id = t.game_id
+			LEFT JOIN cafes c ON c.id = g.cafe_id
+			WHERE u.user_code = $1 AND t.tournament_code = $2 AND tp.position > 0`
+		args = append(args, referenceCode)
+	default:
+		return data, errors.New(utils.ErrInvalidShareCardType)
+	}
+
+	err := db.QueryRow(ctx, query, args...).Scan(
+		&data.UserId, &data.ReferenceCode, &data.MemberName, &data.Title, &data.ImageURL,
+		&data.CafeName, &data.Position, &data.Date,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return data, errors.New(utils.ErrShareCardNotEarned)
+	}
+	if err != nil {
+		return data, c.errHandler("model.GetShareCardContent", err, utils.ErrGettingShareCard)
+	}
+
+	return data, nil
+}
+
+// GetShareCard retrieves the cached card of an achievement. The returned
+// card has a zero Id when nothing has been rendered yet.
+func (c *Contract) GetShareCard(db *pgxpool.Pool, ctx context.Context, userId int64, cardType, referenceCode string) (ShareCardEnt, error) {
+	var data ShareCardEnt
+
+	query := `SELECT id, user_id, card_type, reference_code, content_hash, image_url, created_date, updated_date
+	          FROM share_cards
+	          WHERE user_id = $1 AND card_type = $2 AND reference_code = $3`
+	err := db.QueryRow(ctx, query, userId, cardType, referenceCode).Scan(
+		&data.Id, &data.UserId, &data.CardType, &data.ReferenceCode, &data.ContentHash, &data.ImageURL,
+		&data.CreatedDate, &data.UpdatedDate,
+	)
+	if err != nil && err != pgx.ErrNoRows {
+		return data, c.errHandler("model.GetShareCard", err, utils.ErrGettingShareCard)
+	}
+
+	return data, nil
+}
+
+// SaveShareCard stores the rendered card of an achievement, replacing the
+// previous render.
+func (c *Contract) SaveShareCard(db *pgxpool.Pool, ctx context.Context, userId int64, cardType, referenceCode, contentHash, imageURL string) (ShareCardEnt, error) {
+	var data ShareCardEnt
+
+	query := `
+		INSERT INTO share_cards (user_id, card_type, reference_code, content_hash, image_url, created_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, card_type, reference_code)
+		DO UPDATE SET content_hash = EXCLUDED.content_hash, image_url = EXCLUDED.image_url, updated_date = EXCLUDED.created_date
+		RETURNING id, user_id, card_type, reference_code, content_hash, image_url, created_date, updated_date`
+	err := db.QueryRow(ctx, query, userId, cardType, referenceCode, contentHash, imageURL, time.Now().UTC()).Scan(
+		&data.Id, &data.UserId, &data.CardType, &data.ReferenceCode, &data.ContentHash, &data.ImageURL,
+		&data.CreatedDate, &data.UpdatedDate,
+	)
+	if err != nil {
+		return data, c.errHandler("model.SaveShareCard", err, utils.ErrSavingShareCard)
+	}
+
+	return data, nil
+}
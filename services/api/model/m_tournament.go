@@ -16,35 +16,36 @@ import (
 
 type (
 	TournamentsEnt struct {
-		TournamentId    int64           `db:"tournament_id"`
-		GameId          int64           `db:"game_id"`
-		GameCode        string          `db:"game_code"`
-		GameName        string          `db:"game_name"`
-		GameType        string          `db:"game_type"`
-		GameImgUrl      string          `db:"game_img_url"`
-		CafeCode        string          `db:"cafe_code"`
-		CafeName        string          `db:"cafe_name"`
-		CafeAddress     string          `db:"cafe_address"`
-		TournamentCode  string          `db:"tournament_code"`
-		ImageUrl        sql.NullString  `db:"image_url"`
-		PrizesImgUrl    sql.NullString  `db:"prizes_img_url"`
-		Name            sql.NullString  `db:"name"`
-		TournamentRules string          `db:"tournament_rules"`
-		Level           string          `db:"level"`
-		StartDate       sql.NullTime    `db:"start_date"`
-		EndDate         sql.NullTime    `db:"end_date"`
-		StartTime       time.Time       `db:"start_time"`
-		EndTime         time.Time       `db:"end_time"`
-		ParticipantVP   int64           `db:"participant_vp"`
-		BookingPrice    float64         `db:"booking_price"`
-		PlayerSlot      int64           `db:"player_slot"`
-		CurrentUsedSlot int64           `db:"current_used_slot"`
-		Status          string          `db:"status"`
-		DayPastEndDate  sql.NullFloat64 `db:"days_past_end_date"`
-		CreatedDate     time.Time       `db:"created_date"`
-		UpdatedDate     sql.NullTime    `db:"updated_date"`
-		DeletedDate     sql.NullTime    `db:"deleted_date"`
-		LocationCity    string          `db:"location_city"`
+		TournamentId       int64           `db:"tournament_id"`
+		GameId             int64           `db:"game_id"`
+		GameCode           string          `db:"game_code"`
+		GameName           string          `db:"game_name"`
+		GameType           string          `db:"game_type"`
+		GameImgUrl         string          `db:"game_img_url"`
+		CafeCode           string          `db:"cafe_code"`
+		CafeName           string          `db:"cafe_name"`
+		CafeAddress        string          `db:"cafe_address"`
+		TournamentCode     string          `db:"tournament_code"`
+		ImageUrl           sql.NullString  `db:"image_url"`
+		PrizesImgUrl       sql.NullString  `db:"prizes_img_url"`
+		Name               sql.NullString  `db:"name"`
+		TournamentRules    string          `db:"tournament_rules"`
+		Level              string          `db:"level"`
+		StartDate          sql.NullTime    `db:"start_date"`
+		EndDate            sql.NullTime    `db:"end_date"`
+		StartTime          time.Time       `db:"start_time"`
+		EndTime            time.Time       `db:"end_time"`
+		ParticipantVP      int64           `db:"participant_vp"`
+		BookingPrice       float64         `db:"booking_price"`
+		PlayerSlot         int64           `db:"player_slot"`
+		MinimalParticipant int64           `db:"minimal_participant"`
+		CurrentUsedSlot    int64           `db:"current_used_slot"`
+		Status             string          `db:"status"`
+		DayPastEndDate     sql.NullFloat64 `db:"days_past_end_date"`
+		CreatedDate        time.Time       `db:"created_date"`
+		UpdatedDate        sql.NullTime    `db:"updated_date"`
+		DeletedDate        sql.NullTime    `db:"deleted_date"`
+		LocationCity       string          `db:"location_city"`
 	}
 
 	NonWinnerEntity struct {
@@ -208,7 +209,7 @@ func (c *Contract) GetTournamentByCode(db *pgxpool.Pool, ctx context.Context, co
 		tournaments.id as tournament_id,tournaments.tournament_code, tournaments.image_url, tournaments.name, tournaments.prizes_img_url, 
 		tournaments.tournament_rules, tournaments.level, tournaments.start_date, tournaments.end_date, tournaments.booking_price, 
 		tournaments.start_time, tournaments.end_time, tournaments.booking_price, 
-		tournaments.player_slot, tournaments.participant_vp, tournaments.status,  DATE_PART('day', NOW() - tournaments.end_date) AS days_past_end_date, 
+		tournaments.player_slot, tournaments.minimal_participant, tournaments.participant_vp, tournaments.status,  DATE_PART('day', NOW() - tournaments.end_date) AS days_past_end_date, 
 		tournaments.created_date, tournaments.updated_date, tournaments.deleted_date, 
 		COALESCE(tp.count_participants, 0) AS current_used_slot
 		FROM tournaments
@@ -232,7 +233,7 @@ func (c *Contract) GetTournamentByCode(db *pgxpool.Pool, ctx context.Context, co
 		&data.TournamentId, &data.TournamentCode, &data.ImageUrl, &data.Name, &data.PrizesImgUrl,
 		&data.TournamentRules, &data.Level, &data.StartDate, &data.EndDate, &data.BookingPrice,
 		&data.StartTime, &data.EndTime, &data.BookingPrice,
-		&data.PlayerSlot, &data.ParticipantVP, &data.Status, &data.DayPastEndDate,
+		&data.PlayerSlot, &data.MinimalParticipant, &data.ParticipantVP, &data.Status, &data.DayPastEndDate,
 		&data.CreatedDate, &data.UpdatedDate, &data.DeletedDate, &data.CurrentUsedSlot,
 	)
 
@@ -243,15 +244,15 @@ func (c *Contract) GetTournamentByCode(db *pgxpool.Pool, ctx context.Context, co
 	return data, nil
 }
 
-func (c *Contract) AddTournament(tx pgx.Tx, ctx context.Context, gameId int64, tournamentCode, imageUrl, name, tournamentRules, level, prizeImageUrl string, bookingPrice float64, startDate, endDate, startTime, endTime interface{}, playerSlot, participantVP int64, status string, locationCity string) (int64, error) {
+func (c *Contract) AddTournament(tx pgx.Tx, ctx context.Context, gameId int64, tournamentCode, imageUrl, name, tournamentRules, level, prizeImageUrl string, bookingPrice float64, startDate, endDate, startTime, endTime interface{}, playerSlot, minimalParticipant, participantVP int64, status string, locationCity string) (int64, error) {
 	sql := `INSERT INTO tournaments(
-		game_id, tournament_code, image_url, prizes_img_url, name, tournament_rules, level, start_date, end_date, start_time, end_time, player_slot, booking_price, participant_vp, status, created_date, location_city
+		game_id, tournament_code, image_url, prizes_img_url, name, tournament_rules, level, start_date, end_date, start_time, end_time, player_slot, booking_price, participant_vp, status, created_date, location_city, minimal_participant
 	)
-	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	RETURNING id`
 
 	var id int64
-	err := tx.QueryRow(ctx, sql, gameId, tournamentCode, imageUrl, prizeImageUrl, name, tournamentRules, level, startDate, endDate, startTime, endTime, playerSlot, bookingPrice, participantVP, status, time.Now().In(time.UTC), locationCity).Scan(&id)
+	err := tx.QueryRow(ctx, sql, gameId, tournamentCode, imageUrl, prizeImageUrl, name, tournamentRules, level, startDate, endDate, startTime, endTime, playerSlot, bookingPrice, participantVP, status, time.Now().In(time.UTC), locationCity, minimalParticipant).Scan(&id)
 	if err != nil {
 		return 0, c.errHandler("model.AddTournament", err, utils.ErrAddingTournament)
 	}
@@ -259,15 +260,17 @@ func (c *Contract) AddTournament(tx pgx.Tx, ctx context.Context, gameId int64, t
 	return id, nil
 }
 
-func (c *Contract) UpdateTournamentByCode(tx pgx.Tx, ctx context.Context, gameId int64, code, imageUrl, name, tournament_rules, level, status, prizeImageUrl string, bookingPrice float64, startDate, endDate, startTime, endTime interface{}, playerSlot, participantVP int64, locationCity string) error {
+func (c *Contract) UpdateTournamentByCode(tx pgx.Tx, ctx context.Context, gameId int64, code, imageUrl, name, tournament_rules, level, status, prizeImageUrl string, bookingPrice float64, startDate, endDate, startTime, endTime interface{}, playerSlot, minimalParticipant, participantVP int64, locationCity string) error {
 	var (
 		err error
 		sql = `
 		UPDATE tournaments 
-		SET game_id = $1, name = $2, image_url = $3, tournament_rules = $4, level = $5, status = $6, prizes_img_url = $7, start_date = $8, end_date = $9, start_time = $10, end_time = $11, player_slot = $12, booking_price = $13, participant_vp = $14, updated_date = $15, location_city = $16
+		SET game_id = $1, name = $2, image_url = $3, tournament_rules = $4, level = $5, status = $6, prizes_img_url = $7, start_date = $8, end_date = $9, start_time = $10, end_time = $11, player_slot = $12, booking_price = $13, participant_vp = $14, updated_date = $15, location_city = $16,
+			minimal_participant = $18,
+			minimum_warned_date = CASE WHEN start_date IS DISTINCT FROM $8 OR start_time IS DISTINCT FROM $10 THEN NULL ELSE minimum_warned_date END
 		WHERE tournament_code = $17`
 	)
-	_, err = tx.Exec(ctx, sql, gameId, name, imageUrl, tournament_rules, level, status, prizeImageUrl, startDate, endDate, startTime, endTime, playerSlot, bookingPrice, participantVP, time.Now().UTC(), locationCity, code)
+	_, err = tx.Exec(ctx, sql, gameId, name, imageUrl, tournament_rules, level, status, prizeImageUrl, startDate, endDate, startTime, endTime, playerSlot, bookingPrice, participantVP, time.Now().UTC(), locationCity, code, minimalParticipant)
 	if err != nil {
 		return c.errHandler("model.UpdateTournament", err, utils.ErrUpdatingTournament)
 	}
@@ -298,10 +301,11 @@ func (c *Contract) CountParticipantTournamentByTournamentId(db *pgxpool.Pool, ct
 
 		queryGetTotalParticipant = `select count(id)  from tournament_participants tp 
 		where tp.status!= 'cancel' and tournament_id  = $1
+		and (tp.status != 'pending' or tp.hold_expired_date is null or tp.hold_expired_date > $2)
 		group by tournament_id `
 	)
 
-	err = db.QueryRow(ctx, queryGetTotalParticipant, id).Scan(
+	err = db.QueryRow(ctx, queryGetTotalParticipant, id, time.Now().UTC()).Scan(
 		&totalParticipant,
 	)
 	if err != nil && err != pgx.ErrNoRows {
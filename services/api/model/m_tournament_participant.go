@@ -178,7 +178,8 @@ func (c *Contract) CountTournamentParticipantByUserIdAndStartDateAndEndDate(db *
 	return count, nil
 }
 
-func (c *Contract) CountTournamentParticipantByUserIdAndStartDateAndLifeTime(db *pgxpool.Pool, ctx context.Context, userId int64, startDate string, endDate string) (int64, error) {
+// CountTournamentParticipantByUserIdAndStartDateAndLifeTime counts active participations starting on or after startDate.
+func (c *Contract) CountTournamentParticipantByUserIdAndStartDateAndLifeTime(db *pgxpool.Pool, ctx context.Context, userId int64, startDate string) (int64, error) {
 	var count int64
 	query := `
 		SELECT COUNT(*)
@@ -186,10 +187,10 @@ func (c *Contract) CountTournamentParticipantByUserIdAndStartDateAndLifeTime(db
 		LEFT JOIN tournaments t ON tp.tournament_id = t.id
 		LEFT JOIN games g ON g.id = t.game_id
 		LEFT JOIN users u ON tp.user_id = u.id
-		WHERE u.id = $1 AND tp.status = 'active' AND (t.start_date BETWEEN $2 AND $3)  AND t.end_date = $4
+		WHERE u.id = $1 AND tp.status = 'active' AND t.start_date >= $2
 	`
 
-	err := db.QueryRow(ctx, query, userId, startDate, startDate, nil).Scan(&count)
+	err := db.QueryRow(ctx, query, userId, startDate).Scan(&count)
 	if err != nil {
 		return 0, c.errHandler("model.CountRoomParticipantByUserIdAndStartDateAndEndDate", err, utils.ErrCountParticipantRoomByStartDateAndEndDate)
 	}
@@ -692,6 +692,34 @@ func (c *Contract) GetListUsersByUserId(db *pgxpool.Pool, ctx context.Context) (
 	return list, nil
 }
 
+// GetUsersByIds returns the public profile of the given users ordered by ID.
+func (c *Contract) GetUsersByIds(db *pgxpool.Pool, ctx context.Context, ids []int64) ([]UserEnt, error) {
+	var users []UserEnt
+
+	query := `SELECT id, user_code, username, fullname, image_url FROM users WHERE id = ANY($1) ORDER BY id`
+
+	rows, err := db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, c.errHandler("model.GetUsersByIds", err, utils.ErrGettingListUser)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user UserEnt
+		err := rows.Scan(&user.ID, &user.UserCode, &user.UserName, &user.FullName, &user.ImageURL)
+		if err != nil {
+			return nil, c.errHandler("model.GetUsersByIds", err, utils.ErrScanningListUser)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, c.errHandler("model.GetUsersByIds", err, utils.ErrGettingListUser)
+	}
+
+	return users, nil
+}
+
 func (c *Contract) CheckIfUsernameExists(db *pgxpool.Pool, ctx context.Context, username string) error {
 	var exists bool
 
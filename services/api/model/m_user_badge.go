@@ -35,6 +35,8 @@ type UserBadgeResp struct {
 	CreatedDate   time.Time      `db:"created_date"`
 	IsBadgeOwned  sql.NullBool   `db:"is_badge_owned"`
 	NeedToClaim   sql.NullBool   `db:"need_to_claim"`
+	ExpiredDate   sql.NullTime   `db:"expired_date"`
+	IsArchived    bool           `db:"is_archived"`
 }
 
 func (c *Contract) GetUserBadgeList(db *pgxpool.Pool, ctx context.Context, userCode string, param request.UserBadgeParam) ([]UserBadgeResp, request.UserBadgeParam, error) {
@@ -63,7 +65,9 @@ func (c *Contract) GetUserBadgeList(db *pgxpool.Pool, ctx context.Context, userC
 					CASE
 							WHEN ub.user_id is not NULL and ub.is_claim = false THEN true
 							ELSE false
-					END AS need_to_claim
+					END AS need_to_claim,
+					b.expired_date,
+					COALESCE(b.expired_date < $2, false) AS is_archived
 					FROM
 							badges b
 					LEFT JOIN 
@@ -77,7 +81,25 @@ func (c *Contract) GetUserBadgeList(db *pgxpool.Pool, ctx context.Context, userC
 						b.id = ub.badge_id`
 	)
 
-	paramQuery = append(paramQuery, userCode)
+	paramQuery = append(paramQuery, userCode, time.Now().UTC())
+
+	// Badges outside their earning window are only listed once owned
+	where = append(where, "(ub.user_id IS NOT NULL OR "+badgeAvailableCondition("b", 2)+")")
+
+	// Series levels are superseded by a higher level owned in the same series
+	where = append(where, `NOT EXISTS (
+		SELECT 1 FROM users_badges hub
+		JOIN badges hb ON hb.id = hub.badge_id
+		JOIN users hu ON hu.id = hub.user_id
+		WHERE hu.user_code = $1 AND hb.parent_code = b.parent_code AND hb.series_level > b.series_level
+	)`)
+
+	switch param.Archived {
+	case "true":
+		where = append(where, "ub.user_id IS NOT NULL AND b.expired_date < $2")
+	case "false":
+		where = append(where, "(b.expired_date IS NULL OR b.expired_date >= $2)")
+	}
 
 	if len(param.IsClaim) > 0 {
 		var orWhere []string
@@ -127,7 +149,7 @@ func (c *Contract) GetUserBadgeList(db *pgxpool.Pool, ctx context.Context, userC
 	defer rows.Close()
 	for rows.Next() {
 		var data UserBadgeResp
-		err = rows.Scan(&data.BadgeId, &data.UserId, &data.BadgeName, &data.BadgeImageURL, &data.BadgeCode, &data.BadgeCategory, &data.VPPoint, &data.Description, &data.IsClaim, &data.CreatedDate, &data.IsBadgeOwned, &data.NeedToClaim)
+		err = rows.Scan(&data.BadgeId, &data.UserId, &data.BadgeName, &data.BadgeImageURL, &data.BadgeCode, &data.BadgeCategory, &data.VPPoint, &data.Description, &data.IsClaim, &data.CreatedDate, &data.IsBadgeOwned, &data.NeedToClaim, &data.ExpiredDate, &data.IsArchived)
 		if err != nil {
 			return list, param, c.errHandler("model.GetUserBadgeList", err, utils.ErrScanningListUserBadge)
 		}
@@ -160,7 +182,9 @@ func (c *Contract) GetUserBadgeByBadgeCode(db *pgxpool.Pool, ctx context.Context
 					CASE
 							WHEN ub.user_id is not NULL and ub.is_claim = false THEN true
 							ELSE false
-					END AS need_to_claim
+					END AS need_to_claim,
+					b.expired_date,
+					COALESCE(b.expired_date < $3, false) AS is_archived
 				FROM
 						badges b
 				LEFT JOIN 
@@ -175,7 +199,7 @@ func (c *Contract) GetUserBadgeByBadgeCode(db *pgxpool.Pool, ctx context.Context
 				WHERE   b.badge_code=$2 `
 	)
 
-	err = db.QueryRow(ctx, query, userCode, badgeCode).Scan(&data.BadgeId, &data.UserId, &data.BadgeName, &data.BadgeImageURL, &data.BadgeCode, &data.BadgeCategory, &data.VPPoint, &data.Description, &data.IsClaim, &data.CreatedDate, &data.IsBadgeOwned, &data.NeedToClaim)
+	err = db.QueryRow(ctx, query, userCode, badgeCode, time.Now().UTC()).Scan(&data.BadgeId, &data.UserId, &data.BadgeName, &data.BadgeImageURL, &data.BadgeCode, &data.BadgeCategory, &data.VPPoint, &data.Description, &data.IsClaim, &data.CreatedDate, &data.IsBadgeOwned, &data.NeedToClaim, &data.ExpiredDate, &data.IsArchived)
 	if err != nil {
 		return data, c.errHandler("model.GetUserBadgeByBadgeCode", err, utils.ErrGettingtUserBadgeByBadgeCode)
 	}
@@ -241,6 +265,34 @@ func (c *Contract) AddUserBadge(db *pgxpool.Pool, ctx context.Context, userId, b
 	return nil
 }
 
+// AddUserBadges awards a badge to many users in one statement, skipping users
+// who already own it or, for a series level, the same or a higher level. It
+// returns the number of badges inserted.
+func (c *Contract) AddUserBadges(db *pgxpool.Pool, ctx context.Context, badgeId int64, userIds []int64) (int64, error) {
+	query := `
+		INSERT INTO users_badges (user_id, badge_id, is_claim, created_date)
+		SELECT u.id, $2, false, $3
+		FROM unnest($1::bigint[]) AS u(id)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM users_badges ub WHERE ub.user_id = u.id AND ub.badge_id = $2
+		) AND NOT EXISTS (
+			SELECT 1
+			FROM users_badges ub
+			JOIN badges owned ON owned.id = ub.badge_id
+			JOIN badges b ON b.id = $2
+			WHERE ub.user_id = u.id AND owned.parent_code = b.parent_code
+				AND owned.deleted_date IS NULL AND owned.series_level >= b.series_level
+		)
+	`
+
+	tag, err := db.Exec(ctx, query, userIds, badgeId, time.Now())
+	if err != nil {
+		return 0, c.errHandler("model.AddUserBadges", err, utils.ErrorAddingUserBadge)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
 func (c *Contract) UpdateUserBadge(tx pgx.Tx, ctx context.Context, userId, badgeId int64, isClaim bool) error {
 	query := `
         UPDATE users_badges 
@@ -286,3 +338,52 @@ func (c *Contract) IsUserBadgeExists(tx pgx.Tx, ctx context.Context, userId, bad
 	}
 	return exists, nil
 }
+
+// UserBadgeProgressEnt is an active badge together with whether the user owns it.
+type UserBadgeProgressEnt struct {
+	BadgeEnt
+	IsOwned bool `db:"is_owned"`
+}
+
+// GetUserBadgeProgressList returns every active badge a user can earn through
+// badge rules, marking the ones the user already owns.
+func (c *Contract) GetUserBadgeProgressList(db *pgxpool.Pool, ctx context.Context, userId int64) ([]UserBadgeProgressEnt, error) {
+	var (
+		list  []UserBadgeProgressEnt
+		query = `
+		SELECT
+			b.id, b.badge_code, b.badge_category, b.description, b.vp_point, b.name, b.image_url, b.status, b.rule_operator, b.created_date,
+			b.available_start_date, b.available_end_date,
+			EXISTS (SELECT 1 FROM users_badges ub WHERE ub.badge_id = b.id AND ub.user_id = $1) AS is_owned
+		FROM badges b
+		WHERE b.status = 'active' AND b.deleted_date IS NULL AND b.badge_category NOT IN ($2, $3)
+			AND ` + badgeAvailableCondition("b", 4) + `
+			AND (b.expired_date IS NULL OR b.expired_date > $4)
+		ORDER BY b.created_date DESC`
+	)
+
+	rows, err := db.Query(ctx, query, userId, utils.BadgeCategoryGift.String(), utils.BadgeCategoryTournament.String(), time.Now().UTC())
+	if err != nil {
+		return list, c.errHandler("model.GetUserBadgeProgressList", err, utils.ErrGettingListBadge)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data UserBadgeProgressEnt
+		err = rows.Scan(
+			&data.Id, &data.BadgeCode, &data.BadgeCategory, &data.Description, &data.VPPoint, &data.Name,
+			&data.ImageURL, &data.Status, &data.RuleOperator, &data.CreatedDate,
+			&data.AvailableStartDate, &data.AvailableEndDate, &data.IsOwned,
+		)
+		if err != nil {
+			return list, c.errHandler("model.GetUserBadgeProgressList", err, utils.ErrScanningListBadge)
+		}
+		list = append(list, data)
+	}
+
+	if err = rows.Err(); err != nil {
+		return list, c.errHandler("model.GetUserBadgeProgressList", err, utils.ErrScanningListBadge)
+	}
+
+	return list, nil
+}
@@ -13,20 +13,25 @@ import (
 )
 
 type UserGameHistoryResp struct {
-	UserId             sql.NullInt64  `db:"user_id"`
-	UserCode           sql.NullString `db:"user_code"`
-	GameId             sql.NullInt64  `db:"game_id"`
-	GameName           sql.NullString `db:"game_name"`
-	GameImageUrl       sql.NullString `db:"game_image_url"`
-	GameDuration       sql.NullInt64  `db:"game_duration"`
-	GameDifficulty     sql.NullString `db:"game_difficulty"`
-	GameType           sql.NullString `db:"game_type"`
-	GamePlayerSlot     sql.NullInt64  `db:"player_slot"`
-	GameMasterId       sql.NullInt64  `db:"game_master_id"`
-	GameMasterCode     sql.NullString `db:"game_master_code"`
-	GameMasterName     sql.NullString `db:"game_master_name"`
-	GameMasterImageUrl sql.NullString `db:"game_master_image_url"`
-	GamePlayType       sql.NullString `db:"game_play_type"`
+	UserId             sql.NullInt64     `db:"user_id"`
+	UserCode           sql.NullString    `db:"user_code"`
+	GameId             sql.NullInt64     `db:"game_id"`
+	GameName           sql.NullString    `db:"game_name"`
+	GameImageUrl       sql.NullString    `db:"game_image_url"`
+	GameDuration       sql.NullInt64     `db:"game_duration"`
+	GameDifficulty     sql.NullString    `db:"game_difficulty"`
+	GameType           sql.NullString    `db:"game_type"`
+	GamePlayerSlot     sql.NullInt64     `db:"player_slot"`
+	GameMasterId       sql.NullInt64     `db:"game_master_id"`
+	GameMasterCode     sql.NullString    `db:"game_master_code"`
+	GameMasterName     sql.NullString    `db:"game_master_name"`
+	GameMasterImageUrl sql.NullString    `db:"game_master_image_url"`
+	GamePlayType       sql.NullString    `db:"game_play_type"`
+	EventCode          sql.NullString    `db:"event_code"`
+	Score              sql.NullInt64     `db:"score"`
+	Rank               sql.NullInt64     `db:"rank"`
+	IsTied             bool              `db:"is_tied"`
+	Stats              map[string]string `db:"stats"`
 }
 
 type UsersHavePlayedGameHistoryEnt struct {
@@ -59,13 +64,20 @@ func (c *Contract) GetUserGameHistories(db *pgxpool.Pool, ctx context.Context, u
 					a.admin_code as game_master_code,
 					a."name" as game_master_name,
 					a.image_url as game_master_image_url,
-					gdata.game_play_type
+					gdata.game_play_type,
+					gdata.event_code,
+					gdata.score,
+					gdata."rank",
+					gdata.is_tied,
+					gdata.stats
 				from (
-					select rp.user_id, r.game_id, r.game_master_id, r.maximum_participant as player_slot,  'non-tournament' as game_play_type
+					select rp.user_id, r.game_id, r.game_master_id, r.maximum_participant as player_slot,  'non-tournament' as game_play_type,
+						r.room_code as event_code, rp.score, rp."rank", COALESCE(rp.is_tied, false) as is_tied, rp.stats
 					from rooms  r
 					left join rooms_participants rp on rp.room_id = r.id  
 					union all
-					select tp.user_id, t.game_id, null as game_master_id, t.player_slot, 'tournament' as game_play_type
+					select tp.user_id, t.game_id, null as game_master_id, t.player_slot, 'tournament' as game_play_type,
+						t.tournament_code as event_code, null as score, null as "rank", false as is_tied, null as stats
 					from tournaments  t 
 					left join tournament_participants tp on tp.tournament_id = t.id 
 				) as gdata
@@ -108,7 +120,8 @@ func (c *Contract) GetUserGameHistories(db *pgxpool.Pool, ctx context.Context, u
 	defer rows.Close()
 	for rows.Next() {
 		var data UserGameHistoryResp
-		err = rows.Scan(&data.UserId, &data.UserCode, &data.GameId, &data.GameName, &data.GameImageUrl, &data.GameDuration, &data.GameDifficulty, &data.GameType, &data.GamePlayerSlot, &data.GameMasterId, &data.GameMasterCode, &data.GameMasterName, &data.GameMasterImageUrl, &data.GamePlayType)
+		err = rows.Scan(&data.UserId, &data.UserCode, &data.GameId, &data.GameName, &data.GameImageUrl, &data.GameDuration, &data.GameDifficulty, &data.GameType, &data.GamePlayerSlot, &data.GameMasterId, &data.GameMasterCode, &data.GameMasterName, &data.GameMasterImageUrl, &data.GamePlayType,
+			&data.EventCode, &data.Score, &data.Rank, &data.IsTied, &data.Stats)
 		if err != nil {
 			return list, param, c.errHandler("model.GetUserGameHistories", err, utils.ErrScanningListUserGameHistory)
 		}
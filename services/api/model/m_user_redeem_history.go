@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"database/sql"
+	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/request"
 	"encoding/json"
@@ -325,6 +326,18 @@ func (c *Contract) RedeemInvoice(db *pgxpool.Pool, ctx context.Context, userId i
 		return fail(err)
 	}
 
+	// Publisher badge
+	err = c.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
+		rabbit.QueueUserBadge,
+		rabbit.QueueUserBadgeReq(
+			utils.TotalSpend,
+			userId,
+		),
+	))
+	if err != nil {
+		return fail(err)
+	}
+
 	// Commit transaction
 	if err = tx.Commit(ctx); err != nil {
 		return fail(err)
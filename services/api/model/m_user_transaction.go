@@ -154,6 +154,7 @@ func (c *Contract) GetTransactionByCode(db *pgxpool.Pool, ctx context.Context, u
 		data  UserTransactionEnt
 		query = `SELECT users_transactions.id AS id,
 			transaction_code,
+			users_transactions.aggregator_code,
 			users_transactions.data_source,
 			games.game_code AS game_code,
 			games.name AS game_name,
@@ -177,6 +178,7 @@ func (c *Contract) GetTransactionByCode(db *pgxpool.Pool, ctx context.Context, u
 	err = db.QueryRow(ctx, query, userCode, trxCode).Scan(
 		&data.Id,
 		&data.TransactionCode,
+		&data.AggregatorCode,
 		&data.DataSource,
 		&data.GameCode,
 		&data.GameName,
@@ -262,6 +264,17 @@ func (c *Contract) CreateOneTimeInvoice(tx pgx.Tx, ctx context.Context, userId i
 	return lastInsertId, orderCode, invoiceUrl, resp.ExpiryDate, nil
 }
 
+// ExpireInvoice closes the Xendit invoice of an unpaid transaction. Xendit
+// then sends the EXPIRED callback of the transaction.
+func (c *Contract) ExpireInvoice(aggregatorCode string) error {
+	_, errX := payment.XenditClient{Key: c.Config.GetString("xendit.api_key")}.ExpireInvoice(aggregatorCode)
+	if errX != nil {
+		return c.errHandler("model.ExpireInvoice", errX, utils.ErrCancellingBooking)
+	}
+
+	return nil
+}
+
 func (c *Contract) GetInvoiceTrxByCode(db *pgxpool.Pool, ctx context.Context, aggregatorCode string) (OriginUserTransactionEnt, error) {
 	var (
 		err  error
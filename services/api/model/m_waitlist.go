@@ -0,0 +1,439 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/onesignal"
+	"dots-api/lib/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type (
+	WaitlistEnt struct {
+		Id               int64        `db:"id"`
+		EventType        string       `db:"event_type"`
+		EventId          int64        `db:"event_id"`
+		EventCode        string       `db:"event_code"`
+		EventName        string       `db:"event_name"`
+		EventImageUrl    string       `db:"event_image_url"`
+		UserId           int64        `db:"user_id"`
+		UserCode         string       `db:"user_code"`
+		UserName         string       `db:"user_name"`
+		UserXPlayer      string       `db:"user_x_player"`
+		Status           string       `db:"status"`
+		Position         int          `db:"position"`
+		OfferedDate      sql.NullTime `db:"offered_date"`
+		OfferExpiredDate sql.NullTime `db:"offer_expired_date"`
+		CreatedDate      time.Time    `db:"created_date"`
+	}
+
+	// EventParticipantEnt is the booking of a member for a room or tournament.
+	EventParticipantEnt struct {
+		Status           string         `db:"status"`
+		TransactionCode  sql.NullString `db:"transaction_code"`
+		AttendanceStatus string         `db:"attendance_status"`
+		CheckedInDate    sql.NullTime   `db:"checked_in_date"`
+	}
+
+	// WaitlistEventEnt is a room or tournament that has a waitlist.
+	WaitlistEventEnt struct {
+		EventType string `db:"event_type"`
+		EventId   int64  `db:"event_id"`
+	}
+
+	waitlistTable struct {
+		event, code, seats, participants, participantEvent string
+	}
+)
+
+var (
+	waitlistTables = map[string]waitlistTable{
+		utils.WaitlistRoom:       {"rooms", "room_code", "maximum_participant", "rooms_participants", "room_id"},
+		utils.WaitlistTournament: {"tournaments", "tournament_code", "player_slot", "tournament_participants", "tournament_id"},
+	}
+
+	// waitlistQuery lists the waitlist entries with the event and member, and
+	// the position of the members still waiting for a seat.
+	waitlistQuery = `
+		SELECT * FROM (
+			SELECT
+				w.id, w.event_type, w.event_id,
+				COALESCE(r.room_code, t.tournament_code, '') AS event_code,
+				COALESCE(r.name, t.name, '') AS event_name,
+				COALESCE(r.image_url, t.image_url, '') AS event_image_url,
+				w.user_id, u.user_code, COALESCE(u.username, '') AS user_name, COALESCE(u.x_player, '') AS user_x_player,
+				w.status,
+				ROW_NUMBER() OVER (PARTITION BY w.event_type, w.event_id ORDER BY w.id) AS position,
+				w.offered_date, w.offer_expired_date, w.created_date
+			FROM event_waitlists w
+				JOIN users u ON u.id = w.user_id
+				LEFT JOIN rooms r ON w.event_type = 'room' AND r.id = w.event_id
+				LEFT JOIN tournaments t ON w.event_type = 'tournament' AND t.id = w.event_id
+			WHERE w.status IN ('waiting', 'offered')
+		) AS waitlist`
+)
+
+func (c *Contract) scanWaitlist(rows pgx.Rows, funcName string) ([]WaitlistEnt, error) {
+	var list []WaitlistEnt
+
+	defer rows.Close()
+	for rows.Next() {
+		var data WaitlistEnt
+		err := rows.Scan(
+			&data.Id, &data.EventType, &data.EventId, &data.EventCode, &data.EventName, &data.EventImageUrl,
+			&data.UserId, &data.UserCode, &data.UserName, &data.UserXPlayer,
+			&data.Status, &data.Position, &data.OfferedDate, &data.OfferExpiredDate, &data.CreatedDate,
+		)
+		if err != nil {
+			return list, c.errHandler(funcName, err, utils.ErrScanningWaitlist)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+// GetWaitlistByEvent returns the members waiting for a seat of an event in order.
+func (c *Contract) GetWaitlistByEvent(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) ([]WaitlistEnt, error) {
+	rows, err := db.Query(ctx, waitlistQuery+` WHERE event_type = $1 AND event_id = $2 ORDER BY position`, eventType, eventId)
+	if err != nil {
+		return nil, c.errHandler("model.GetWaitlistByEvent", err, utils.ErrGettingWaitlist)
+	}
+
+	return c.scanWaitlist(rows, "model.GetWaitlistByEvent")
+}
+
+// GetWaitlistByUserId returns the waitlists a member is on.
+func (c *Contract) GetWaitlistByUserId(db *pgxpool.Pool, ctx context.Context, userId int64) ([]WaitlistEnt, error) {
+	rows, err := db.Query(ctx, waitlistQuery+` WHERE user_id = $1 ORDER BY created_date DESC`, userId)
+	if err != nil {
+		return nil, c.errHandler("model.GetWaitlistByUserId", err, utils.ErrGettingWaitlist)
+	}
+
+	return c.scanWaitlist(rows, "model.GetWaitlistByUserId")
+}
+
+// GetWaitlistEntry returns the waitlist entry of a member for an event.
+func (c *Contract) GetWaitlistEntry(db *pgxpool.Pool, ctx context.Context, eventType string, eventId, userId int64) (WaitlistEnt, error) {
+	rows, err := db.Query(ctx, waitlistQuery+` WHERE event_type = $1 AND event_id = $2 AND user_id = $3`, eventType, eventId, userId)
+	if err != nil {
+		return WaitlistEnt{}, c.errHandler("model.GetWaitlistEntry", err, utils.ErrGettingWaitlist)
+	}
+
+	list, err := c.scanWaitlist(rows, "model.GetWaitlistEntry")
+	if err != nil {
+		return WaitlistEnt{}, err
+	}
+	if len(list) == 0 {
+		return WaitlistEnt{}, errors.New(utils.ErrNotOnWaitlist)
+	}
+
+	return list[0], nil
+}
+
+// JoinWaitlist puts a member at the end of the waitlist of an event.
+func (c *Contract) JoinWaitlist(db *pgxpool.Pool, ctx context.Context, eventType string, eventId, userId int64) error {
+	query := `
+		INSERT INTO event_waitlists (event_type, event_id, user_id, status, created_date)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING`
+	tag, err := db.Exec(ctx, query, eventType, eventId, userId, utils.WaitlistWaiting, time.Now().UTC())
+	if err != nil {
+		return c.errHandler("model.JoinWaitlist", err, utils.ErrJoiningWaitlist)
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New(utils.ErrAlreadyOnWaitlist)
+	}
+
+	return nil
+}
+
+// UpdateWaitlistStatusTrx moves the waiting or offered entry of a member to
+// another status, e.g. when they book or leave. It returns false when the
+// member is not on the waitlist.
+func (c *Contract) UpdateWaitlistStatusTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64, status string) (bool, error) {
+	query := `
+		UPDATE event_waitlists SET status = $1, updated_date = $2
+		WHERE event_type = $3 AND event_id = $4 AND user_id = $5 AND status IN ('waiting', 'offered')`
+	tag, err := tx.Exec(ctx, query, status, time.Now().UTC(), eventType, eventId, userId)
+	if err != nil {
+		return false, c.errHandler("model.UpdateWaitlistStatusTrx", err, utils.ErrUpdatingWaitlist)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetEventParticipant returns the booking of a member for an event. Its
+// status is empty when they never booked it.
+func (c *Contract) GetEventParticipant(db *pgxpool.Pool, ctx context.Context, eventType string, eventId, userId int64) (EventParticipantEnt, error) {
+	var (
+		table, ok = waitlistTables[eventType]
+		data      EventParticipantEnt
+	)
+	if !ok {
+		return data, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `SELECT status, transaction_code, attendance_status, checked_in_date FROM ` + table.participants + ` WHERE ` + table.participantEvent + ` = $1 AND user_id = $2`
+	err := db.QueryRow(ctx, query, eventId, userId).Scan(&data.Status, &data.TransactionCode, &data.AttendanceStatus, &data.CheckedInDate)
+	if err != nil && err != pgx.ErrNoRows {
+		return data, c.errHandler("model.GetEventParticipant", err, utils.ErrGettingWaitlist)
+	}
+
+	return data, nil
+}
+
+// GetEventParticipantStatusTrx returns the status of the booking of a member
+// for an event and locks it until the transaction ends.
+func (c *Contract) GetEventParticipantStatusTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64) (string, error) {
+	var status string
+
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return status, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `SELECT status FROM ` + table.participants + ` WHERE ` + table.participantEvent + ` = $1 AND user_id = $2 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, eventId, userId).Scan(&status)
+	if err != nil && err != pgx.ErrNoRows {
+		return status, c.errHandler("model.GetEventParticipantStatusTrx", err, utils.ErrGettingWaitlist)
+	}
+
+	return status, nil
+}
+
+// CancelEventParticipantTrx cancels the booking of a member for an event.
+func (c *Contract) CancelEventParticipantTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId, userId int64) error {
+	table, ok := waitlistTables[eventType]
+	if !ok {
+		return fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	query := `UPDATE ` + table.participants + ` SET status = 'cancel', updated_date = $1 WHERE ` + table.participantEvent + ` = $2 AND user_id = $3`
+	_, err := tx.Exec(ctx, query, time.Now().UTC(), eventId, userId)
+	if err != nil {
+		return c.errHandler("model.CancelEventParticipantTrx", err, utils.ErrCancellingBooking)
+	}
+
+	return nil
+}
+
+// CountHeldWaitlistSeats counts the seats of an event held by open offers to
+// other members than userId. Those seats cannot be booked by anyone else.
+func (c *Contract) CountHeldWaitlistSeats(db *pgxpool.Pool, ctx context.Context, eventType string, eventId, userId int64) (int, error) {
+	var total int
+
+	query := `
+		SELECT COUNT(*) FROM event_waitlists
+		WHERE event_type = $1 AND event_id = $2 AND user_id != $3 AND status = 'offered' AND offer_expired_date > $4`
+	err := db.QueryRow(ctx, query, eventType, eventId, userId, time.Now().UTC()).Scan(&total)
+	if err != nil {
+		return 0, c.errHandler("model.CountHeldWaitlistSeats", err, utils.ErrGettingWaitlist)
+	}
+
+	return total, nil
+}
+
+// OfferWaitlistSeats offers the free seats of an event to the next members on
+// its waitlist. See OfferWaitlistSeatsTrx.
+func (c *Contract) OfferWaitlistSeats(db *pgxpool.Pool, ctx context.Context, eventType string, eventId int64) ([]WaitlistEnt, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeats", err, utils.ErrOfferingWaitlistSeats)
+	}
+	defer tx.Rollback(ctx)
+
+	offers, err := c.OfferWaitlistSeatsTrx(tx, ctx, eventType, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeats", err, utils.ErrOfferingWaitlistSeats)
+	}
+
+	return offers, nil
+}
+
+// OfferWaitlistSeatsTrx offers every seat of an event that is neither booked
+// nor held by an open offer to the next waiting members, in the order they
+// joined. Each offer gets an in-app notification and is held for
+// utils.WaitlistOfferMinutes. The event is locked, so concurrent calls never
+// offer the same seat twice. Nothing is offered once the event is no longer
+// active or has started. The returned offers still need PushWaitlistOffers
+// once the transaction is committed.
+func (c *Contract) OfferWaitlistSeatsTrx(tx pgx.Tx, ctx context.Context, eventType string, eventId int64) ([]WaitlistEnt, error) {
+	var (
+		table, ok = waitlistTables[eventType]
+		now       = time.Now().UTC()
+		seats     int
+		taken     int
+		held      int
+	)
+	if !ok {
+		return nil, fmt.Errorf("unknown waitlist event type: %s", eventType)
+	}
+
+	// Only an active event that has not started yet offers its seats
+	query := `
+		SELECT ` + table.seats + ` FROM ` + table.event + `
+		WHERE id = $1 AND status = $2 AND (start_date + COALESCE(start_time, '00:00'::time)) AT TIME ZONE 'Asia/Jakarta' > $3
+		FOR UPDATE`
+	err := tx.QueryRow(ctx, query, eventId, utils.RoomStatus["ACTIVE"], now).Scan(&seats)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeatsTrx", err, utils.ErrOfferingWaitlistSeats)
+	}
+
+	taken, err = c.countTakenSeatsTrx(tx, ctx, table, eventId, 0, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM event_waitlists
+		WHERE event_type = $1 AND event_id = $2 AND status = 'offered' AND offer_expired_date > $3`, eventType, eventId, now).Scan(&held)
+	if err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeatsTrx", err, utils.ErrOfferingWaitlistSeats)
+	}
+
+	free := seats - taken - held
+	if free <= 0 {
+		return nil, nil
+	}
+
+	query = `
+		UPDATE event_waitlists SET status = $1, offered_date = $2, offer_expired_date = $3, updated_date = $2
+		WHERE id IN (
+			SELECT id FROM event_waitlists
+			WHERE event_type = $4 AND event_id = $5 AND status = 'waiting'
+			ORDER BY id
+			LIMIT $6
+		)
+		RETURNING id`
+	rows, err := tx.Query(ctx, query, utils.WaitlistOffered, now, now.Add(time.Duration(utils.WaitlistOfferMinutes)*time.Minute), eventType, eventId, free)
+	if err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeatsTrx", err, utils.ErrOfferingWaitlistSeats)
+	}
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, c.errHandler("model.OfferWaitlistSeatsTrx", err, utils.ErrOfferingWaitlistSeats)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err = tx.Query(ctx, waitlistQuery+` WHERE id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return nil, c.errHandler("model.OfferWaitlistSeatsTrx", err, utils.ErrGettingWaitlist)
+	}
+	offers, err := c.scanWaitlist(rows, "model.OfferWaitlistSeatsTrx")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, offer := range offers {
+		descriptionJSON, err := json.Marshal(waitlistOfferDescription(offer))
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.AddNotificationWithTx(tx, ctx, utils.GeneratePrefixCode(utils.NotifPrefix), "user", offer.UserCode, offer.EventCode, utils.WaitlistOfferType, utils.WaitlistOfferTitle, descriptionJSON, offer.EventImageUrl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return offers, nil
+}
+
+// PushWaitlistOffers sends the push notification of each offer. A failed push
+// is only logged, the member still has the in-app notification.
+func (c *Contract) PushWaitlistOffers(offers []WaitlistEnt) {
+	if len(offers) == 0 {
+		return
+	}
+
+	push := onesignal.New(c.App)
+	for _, offer := range offers {
+		_, err := push.CreateOSNotifications(offer.UserXPlayer, utils.WaitlistOfferTitle, waitlistOfferDescription(offer), offer.EventType)
+		if err != nil {
+			log.Printf("Error : %s", err)
+		}
+	}
+}
+
+// ExpireWaitlistOffers closes the offers that were not booked in time and
+// returns the events they belong to, so their seats can roll to the next
+// members.
+func (c *Contract) ExpireWaitlistOffers(db *pgxpool.Pool, ctx context.Context) ([]WaitlistEventEnt, error) {
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE event_waitlists SET status = $1, updated_date = $2
+		WHERE status = 'offered' AND offer_expired_date <= $2
+		RETURNING event_type, event_id`
+	rows, err := db.Query(ctx, query, utils.WaitlistExpired, now)
+	if err != nil {
+		return nil, c.errHandler("model.ExpireWaitlistOffers", err, utils.ErrUpdatingWaitlist)
+	}
+	defer rows.Close()
+
+	var (
+		list []WaitlistEventEnt
+		seen = map[WaitlistEventEnt]bool{}
+	)
+	for rows.Next() {
+		var data WaitlistEventEnt
+		if err = rows.Scan(&data.EventType, &data.EventId); err != nil {
+			return nil, c.errHandler("model.ExpireWaitlistOffers", err, utils.ErrScanningWaitlist)
+		}
+		if !seen[data] {
+			seen[data] = true
+			list = append(list, data)
+		}
+	}
+
+	return list, nil
+}
+
+// GetWaitingWaitlistEvents returns the events that still have members waiting
+// for a seat.
+func (c *Contract) GetWaitingWaitlistEvents(db *pgxpool.Pool, ctx context.Context) ([]WaitlistEventEnt, error) {
+	rows, err := db.Query(ctx, `SELECT DISTINCT event_type, event_id FROM event_waitlists WHERE status = 'waiting'`)
+	if err != nil {
+		return nil, c.errHandler("model.GetWaitingWaitlistEvents", err, utils.ErrGettingWaitlist)
+	}
+	defer rows.Close()
+
+	var list []WaitlistEventEnt
+	for rows.Next() {
+		var data WaitlistEventEnt
+		if err = rows.Scan(&data.EventType, &data.EventId); err != nil {
+			return nil, c.errHandler("model.GetWaitingWaitlistEvents", err, utils.ErrScanningWaitlist)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
+
+func waitlistOfferDescription(offer WaitlistEnt) string {
+	return fmt.Sprintf(utils.WaitlistOfferDescription, offer.EventName, offer.OfferExpiredDate.Time.In(utils.GetTimeLocationWIB()).Format(utils.DATE_TIME_FORMAT))
+}
@@ -0,0 +1,124 @@
+-- Tables the model tests run against. Every column has the type it has in
+-- resources/migrations, except rooms.maximum_participant and
+-- rooms_participants.transaction_code, which the migrations do not create and
+-- are typed like their tournament counterparts. Only the columns the tests
+-- read or write are kept.
+
+CREATE TABLE users (
+	id serial PRIMARY KEY UNIQUE,
+	user_code varchar(50) NOT NULL UNIQUE,
+	email varchar(100) NOT NULL,
+	username varchar(100) NULL,
+	phone_number varchar(15) NOT NULL,
+	fullname varchar(100) NULL DEFAULT '',
+	latest_point int NOT NULL DEFAULT 0,
+	"password" varchar(100) NOT NULL,
+	x_player varchar(255) NULL DEFAULT '',
+	"status" varchar(50) NOT NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	deleted_date timestamptz(0) NULL
+);
+
+CREATE TABLE rooms (
+	id serial PRIMARY KEY UNIQUE,
+	room_code varchar(50) NOT NULL UNIQUE,
+	room_type varchar(50) NOT NULL,
+	"name" varchar(100) NOT NULL DEFAULT '',
+	image_url varchar(100) NULL DEFAULT '',
+	"start_date" date NOT NULL,
+	end_date date NOT NULL,
+	start_time time NULL,
+	end_time time NULL,
+	maximum_participant int NOT NULL DEFAULT 0,
+	minimal_participant int NOT NULL DEFAULT 0,
+	booking_price numeric(22,2) NOT NULL DEFAULT 0,
+	"status" varchar(50) NOT NULL,
+	table_id bigint NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	deleted_date timestamptz(0) NULL
+);
+
+CREATE TABLE rooms_participants (
+	id serial PRIMARY KEY,
+	room_id bigint REFERENCES rooms(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	user_id bigint REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	status_winner boolean DEFAULT false,
+	reward_point int NOT NULL DEFAULT 0,
+	"status" varchar(50) NOT NULL,
+	additional_info varchar(50) NULL,
+	"position" int DEFAULT 0,
+	transaction_code varchar(50) NULL,
+	hold_expired_date timestamp NULL,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	updated_date timestamptz(0) NULL,
+	CONSTRAINT room_id_and_user_id_in_rooms_participants UNIQUE (room_id, user_id)
+);
+
+CREATE TABLE event_waitlists (
+	id bigserial PRIMARY KEY,
+	event_type varchar(20) NOT NULL,
+	event_id bigint NOT NULL,
+	user_id bigint NOT NULL REFERENCES users(id),
+	status varchar(20) NOT NULL DEFAULT 'waiting',
+	offered_date timestamp NULL,
+	offer_expired_date timestamp NULL,
+	created_date timestamp NOT NULL DEFAULT NOW(),
+	updated_date timestamp NULL
+);
+
+CREATE TABLE users_transactions (
+	id bigserial PRIMARY KEY,
+	user_id bigint REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	"data_source" varchar(100) NOT NULL DEFAULT '',
+	source_code varchar(100) NULL DEFAULT '',
+	transaction_code varchar(50) NOT NULL UNIQUE,
+	aggregator_code varchar(100) NULL UNIQUE,
+	price numeric(22,2) NOT NULL DEFAULT 0,
+	payment_method varchar(100) NOT NULL DEFAULT '',
+	"status" varchar(20) NOT NULL DEFAULT '',
+	resp_payload text NULL DEFAULT '',
+	created_date timestamp NULL DEFAULT NOW(),
+	updated_date timestamp NULL,
+	expired_date timestamptz(0) NULL
+);
+
+CREATE TABLE users_points (
+	id serial PRIMARY KEY,
+	user_id bigint REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	"data_source" varchar(100) NOT NULL DEFAULT '',
+	source_code varchar(100) NULL DEFAULT '',
+	"point" bigint NULL DEFAULT 0,
+	created_date timestamptz(0) NOT NULL
+);
+
+CREATE TABLE event_cancellations (
+	id bigserial PRIMARY KEY,
+	event_type varchar(20) NOT NULL,
+	event_id bigint NOT NULL,
+	reason varchar(500) NOT NULL DEFAULT '',
+	cancelled_by varchar(50) NOT NULL DEFAULT '',
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	CONSTRAINT event_type_and_event_id_in_event_cancellations UNIQUE (event_type, event_id)
+);
+
+CREATE TABLE event_cancellation_participants (
+	id bigserial PRIMARY KEY,
+	cancellation_id bigint NOT NULL REFERENCES event_cancellations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	participant_status varchar(50) NOT NULL,
+	transaction_code varchar(50) NOT NULL DEFAULT '',
+	amount bigint NOT NULL DEFAULT 0,
+	refund_status varchar(30) NOT NULL,
+	refund_id varchar(100) NOT NULL DEFAULT '',
+	refund_error text NOT NULL DEFAULT '',
+	reversed_point int NOT NULL DEFAULT 0,
+	push_sent boolean NOT NULL DEFAULT false,
+	email_sent boolean NOT NULL DEFAULT false,
+	created_date timestamptz(0) NOT NULL DEFAULT NOW(),
+	settle_attempts int NOT NULL DEFAULT 0,
+	notified_date timestamptz(0) NULL,
+	settled_date timestamptz(0) NULL,
+	CONSTRAINT cancellation_id_and_user_id_in_event_cancellation_participants UNIQUE (cancellation_id, user_id)
+);
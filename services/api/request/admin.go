@@ -19,6 +19,7 @@ type (
 		Role        string `json:"role" validate:"required,eq=admin|eq=cashier"`
 		PhoneNumber string `json:"phone_number" validate:"max=15"`
 		ImageUrl    string `json:"image_url"`
+		UserCode    string `json:"user_code"`
 	}
 
 	AdminUpdateReq struct {
@@ -30,6 +31,7 @@ type (
 		Role        string `json:"role" validate:"omitempty,eq=admin|eq=cashier"`
 		PhoneNumber string `json:"phone_number" validate:"max=15"`
 		ImageUrl    string `json:"image_url"`
+		UserCode    string `json:"user_code"`
 	}
 
 	UpdateStatusAdminReq struct {
@@ -0,0 +1,25 @@
+package request
+
+import (
+	"dots-api/lib/utils"
+	"errors"
+	"strings"
+)
+
+type CheckInReq struct {
+	QrCode string `json:"qr_code" validate:"required"`
+}
+
+// Parse splits the content of a check-in QR code into the event type, event
+// code and check-in token.
+func (req CheckInReq) Parse() (eventType, eventCode, token string, err error) {
+	parts := strings.Split(req.QrCode, ":")
+	if len(parts) != 4 || parts[0] != utils.CheckInQRPrefix {
+		return "", "", "", errors.New(utils.ErrInvalidCheckInQrCode)
+	}
+	if parts[1] != utils.WaitlistRoom && parts[1] != utils.WaitlistTournament {
+		return "", "", "", errors.New(utils.ErrInvalidCheckInQrCode)
+	}
+
+	return parts[1], parts[2], parts[3], nil
+}
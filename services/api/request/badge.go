@@ -8,13 +8,44 @@ import (
 )
 
 type BadgeReq struct {
-	BadgeCategory string         `json:"badge_category" validate:"required,max=100"`
-	Name          string         `json:"name"`
-	ImageURL      string         `json:"image_url"`
-	Status        string         `json:"status"`
-	VPPoint       int64          `json:"vp_point"`
-	Description   string         `json:"description"`
-	BadgeRule     []BadgeRuleReq `json:"badge_rule"`
+	BadgeCategory      string         `json:"badge_category" validate:"required,max=100"`
+	Name               string         `json:"name"`
+	ImageURL           string         `json:"image_url"`
+	Status             string         `json:"status"`
+	VPPoint            int64          `json:"vp_point"`
+	Description        string         `json:"description"`
+	RuleOperator       string         `json:"rule_operator" validate:"omitempty,oneof=all any"`
+	AvailableStartDate string         `json:"available_start_date"`
+	AvailableEndDate   string         `json:"available_end_date"`
+	ExpiredDate        string         `json:"expired_date"`
+	BadgeRule          []BadgeRuleReq `json:"badge_rule"`
+}
+
+type BadgePreviewReq struct {
+	RuleOperator     string         `json:"rule_operator" validate:"omitempty,oneof=all any"`
+	BadgeRule        []BadgeRuleReq `json:"badge_rule" validate:"required,min=1"`
+	CompareBadgeCode string         `json:"compare_badge_code"`
+}
+
+type BadgeSeriesReq struct {
+	Name          string                `json:"name" validate:"required,max=100"`
+	Description   string                `json:"description"`
+	ImageURL      string                `json:"image_url"`
+	Status        string                `json:"status"`
+	BadgeCategory string                `json:"badge_category" validate:"required,max=100"`
+	Levels        []BadgeSeriesLevelReq `json:"levels" validate:"required,min=2,dive"`
+}
+
+// BadgeSeriesLevelReq is one level of a badge series. BadgeCode is only read
+// on update, to keep an existing level.
+type BadgeSeriesLevelReq struct {
+	BadgeCode    string         `json:"badge_code"`
+	Name         string         `json:"name" validate:"required"`
+	ImageURL     string         `json:"image_url"`
+	Description  string         `json:"description"`
+	VPPoint      int64          `json:"vp_point"`
+	RuleOperator string         `json:"rule_operator" validate:"omitempty,oneof=all any"`
+	BadgeRule    []BadgeRuleReq `json:"badge_rule" validate:"required,min=1"`
 }
 
 type TournamentBadgeListReq struct {
@@ -33,13 +64,17 @@ type TournamentBadgeReq struct {
 }
 
 type UpdateBadgeReq struct {
-	BadgeCategory string               `json:"badge_category" validate:"required,max=100"`
-	Name          string               `json:"name"`
-	ImageURL      string               `json:"image_url"`
-	Status        string               `json:"status"`
-	VPPoint       int64                `json:"vp_point"`
-	Description   string               `json:"description"`
-	BadgeRule     []UpdateBadgeRuleReq `json:"badge_rule"`
+	BadgeCategory      string               `json:"badge_category" validate:"required,max=100"`
+	Name               string               `json:"name"`
+	ImageURL           string               `json:"image_url"`
+	Status             string               `json:"status"`
+	VPPoint            int64                `json:"vp_point"`
+	Description        string               `json:"description"`
+	RuleOperator       string               `json:"rule_operator" validate:"omitempty,oneof=all any"`
+	AvailableStartDate string               `json:"available_start_date"`
+	AvailableEndDate   string               `json:"available_end_date"`
+	ExpiredDate        string               `json:"expired_date"`
+	BadgeRule          []UpdateBadgeRuleReq `json:"badge_rule"`
 }
 
 type IsClaimBadgeReq struct {
@@ -15,20 +15,6 @@ type UpdateBadgeRuleReq struct {
 	Value             interface{} `json:"value"`
 }
 
-type TimeLimitCategory struct {
-	Category  string `json:"category"`
-	Name      string `json:"name"`
-	StartDate string `json:"start_date"`
-	EndDate   string `json:"end_date"`
-}
-
-type SpesificBoardGameCategory struct {
-	GameCode     []string `json:"game_code"`
-	NeedGM       bool     `json:"need_gm"`
-	TotalPlayed  int64    `json:"total_played"`
-	BookingPrice int64    `json:"booking_price"`
-}
-
 type TournamentCategory struct {
 	Position int `json:"position"`
 }
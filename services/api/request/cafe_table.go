@@ -0,0 +1,16 @@
+package request
+
+type (
+	CafeTableReq struct {
+		Name      string `json:"name" validate:"required,max=100"`
+		TableType string `json:"table_type" validate:"required,max=50"`
+		Seats     int    `json:"seats" validate:"required,min=1"`
+		Status    string `json:"status" validate:"required,max=50"`
+	}
+
+	// EventTableReq assigns a room or tournament to a table. An empty
+	// TableCode unassigns it.
+	EventTableReq struct {
+		TableCode string `json:"table_code" validate:"max=50"`
+	}
+)
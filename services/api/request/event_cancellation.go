@@ -0,0 +1,5 @@
+package request
+
+type CancelEventReq struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,71 @@
+package request
+
+import (
+	"dots-api/lib/utils"
+	"errors"
+	"net/url"
+	"time"
+)
+
+type (
+	GameMasterAvailabilityReq struct {
+		Availabilities []GameMasterAvailability `json:"availabilities" validate:"dive"`
+	}
+
+	// GameMasterAvailability is a window of a weekday, 0 (sunday) to 6
+	// (saturday), a game master can host rooms in.
+	GameMasterAvailability struct {
+		Weekday   int    `json:"weekday" validate:"min=0,max=6"`
+		StartTime string `json:"start_time" validate:"required"`
+		EndTime   string `json:"end_time" validate:"required"`
+	}
+
+	GameMasterDayOffReq struct {
+		Date   string `json:"date" validate:"required"`
+		Reason string `json:"reason" validate:"max=255"`
+	}
+
+	// GameMasterScheduleParam is the range of days of a schedule or workload
+	// view, both included.
+	GameMasterScheduleParam struct {
+		StartDate time.Time `json:"start_date"`
+		EndDate   time.Time `json:"end_date"`
+		AdminCode string    `json:"admin_code"`
+	}
+)
+
+// ParseGameMasterSchedule defaults the range to utils.GameMasterScheduleDays
+// from the monday of the current week in WIB.
+func (param *GameMasterScheduleParam) ParseGameMasterSchedule(values url.Values) error {
+	var err error
+
+	now := time.Now().In(utils.GetTimeLocationWIB())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	param.StartDate = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	param.AdminCode = ""
+
+	if startDate, ok := values["start_date"]; ok && len(startDate) > 0 {
+		param.StartDate, err = time.Parse(time.DateOnly, startDate[0])
+		if err != nil {
+			return errors.New(utils.ErrInvalidScheduleRange)
+		}
+	}
+
+	param.EndDate = param.StartDate.AddDate(0, 0, utils.GameMasterScheduleDays-1)
+	if endDate, ok := values["end_date"]; ok && len(endDate) > 0 {
+		param.EndDate, err = time.Parse(time.DateOnly, endDate[0])
+		if err != nil {
+			return errors.New(utils.ErrInvalidScheduleRange)
+		}
+	}
+
+	if param.EndDate.Before(param.StartDate) || param.EndDate.Sub(param.StartDate) >= time.Duration(utils.GameMasterScheduleMaxDays)*24*time.Hour {
+		return errors.New(utils.ErrInvalidScheduleRange)
+	}
+
+	if adminCode, ok := values["admin_code"]; ok && len(adminCode) > 0 {
+		param.AdminCode = adminCode[0]
+	}
+
+	return nil
+}
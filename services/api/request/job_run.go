@@ -0,0 +1,72 @@
+package request
+
+import (
+	"dots-api/lib/array"
+	"dots-api/lib/utils"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	JobRunParam struct {
+		Page    int    `json:"page"`
+		Limit   int    `json:"limit"`
+		Offset  int    `json:"offset"`
+		Count   int    `json:"count"`
+		MaxPage int    `json:"max_page"`
+		Sort    string `json:"sort"`
+		Order   string `json:"order"`
+		JobName string `json:"job_name"`
+		Status  string `json:"status"`
+	}
+)
+
+func (param *JobRunParam) ParseJobRun(values url.Values) error {
+	param.Page = 1
+	param.Limit = 10
+	param.Sort = "desc"
+	param.Order = "started_date"
+	param.JobName = ""
+	param.Status = ""
+	param.Offset = 0
+
+	if page, ok := values["page"]; ok && len(page) > 0 {
+		if p, err := strconv.Atoi(page[0]); err == nil && p > 1 {
+			param.Page = p
+		}
+	}
+
+	if sort, ok := values["sort"]; ok && len(sort) > 0 && strings.ToLower(sort[0]) == "asc" {
+		param.Sort = "asc"
+	}
+
+	if order, ok := values["order"]; ok && len(order) > 0 {
+		arrStr := new(array.ArrStr)
+		if exist, _ := arrStr.InArray(order[0], []string{"job_name", "status", "item_count", "scheduled_date", "started_date", "finished_date"}); exist {
+			param.Order = order[0]
+		}
+	}
+
+	if jobName, ok := values["job_name"]; ok && len(jobName) > 0 {
+		param.JobName = jobName[0]
+	}
+
+	if status, ok := values["status"]; ok && len(status) > 0 {
+		if !utils.Contains(utils.StatusJobRun, status[0]) {
+			return fmt.Errorf("%s", "wrong status value for Job Run(running|success|failed)")
+		}
+		param.Status = status[0]
+	}
+
+	if limit, ok := values["limit"]; ok && len(limit) > 0 {
+		if l, err := strconv.Atoi(limit[0]); err == nil {
+			param.Limit = l
+		}
+	}
+
+	param.Offset = (param.Page - 1) * param.Limit
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package request
+
+import (
+	"dots-api/lib/utils"
+	"net/url"
+	"strconv"
+)
+
+type LeaderboardParam struct {
+	Limit int `json:"limit"`
+}
+
+func (param *LeaderboardParam) ParseLeaderboard(values url.Values) error {
+	param.Limit = utils.LeaderboardLimit
+
+	if limit, ok := values["limit"]; ok && len(limit) > 0 {
+		if l, err := strconv.Atoi(limit[0]); err == nil && l > 0 {
+			param.Limit = l
+		}
+	}
+
+	if param.Limit > utils.LeaderboardMaxLimit {
+		param.Limit = utils.LeaderboardMaxLimit
+	}
+
+	return nil
+}
@@ -24,11 +24,14 @@ type (
 		StartTime          string  `json:"start_time" validate:"required"`
 		EndTime            string  `json:"end_time" validate:"required"`
 		MaximumParticipant int     `json:"maximum_participant" validate:"required"`
+		MinimalParticipant int     `json:"minimal_participant" validate:"min=0"`
 		BookingPrice       float64 `json:"booking_price" validate:"required,min=10000"`
 		RewardPoint        int     `json:"reward_point"`
 		InstagramLink      string  `json:"instagram_link" validate:"max=500"`
 		ImageURL           string  `json:"image_url" validate:"max=500"`
 		Status             string  `json:"status" validate:"required,max=50"`
+		Visibility         string  `json:"visibility" validate:"max=20"`
+		RequiresApproval   *bool   `json:"requires_approval"`
 	}
 
 	BookingRoomReq struct {
@@ -38,12 +41,17 @@ type (
 	}
 
 	SetWinnerRoomReq struct {
-		RoomParticipant []RoomParticipant `json:"room_participant"`
+		RoomParticipant []RoomParticipant `json:"room_participant" validate:"dive"`
 	}
 
+	// RoomParticipant is the result of a participant. Rank is derived from
+	// the scores, or else from Position, when no participant has one.
 	RoomParticipant struct {
-		Position int    `json:"position"`
-		UserCode string `json:"user_code"`
+		Position int               `json:"position"`
+		UserCode string            `json:"user_code"`
+		Score    *int64            `json:"score"`
+		Rank     int               `json:"rank" validate:"min=0"`
+		Stats    map[string]string `json:"stats"`
 	}
 
 	UpdateStatusRoomReq struct {
@@ -62,6 +70,20 @@ type (
 		Status   string   `json:"status"`
 		CafeCity string   `json:"location"`
 		RoomType string   `json:"room_type"`
+		// Visibility filters the rooms of the CMS. Members only list public
+		// rooms.
+		Visibility     string `json:"visibility"`
+		IncludePrivate bool   `json:"include_private"`
+	}
+
+	// JoinRoomReq joins a private room with its invite code.
+	JoinRoomReq struct {
+		InviteCode string `json:"invite_code" validate:"required,max=50"`
+	}
+
+	// ReviewRoomJoinReq approves or rejects a request to join a private room.
+	ReviewRoomJoinReq struct {
+		Status string `json:"status" validate:"required"`
 	}
 )
 
@@ -116,6 +138,13 @@ func (param *RoomParam) ParseRoom(values url.Values) error {
 		param.CafeCity = cafeCity[0]
 	}
 
+	if visibility, ok := values["visibility"]; ok && len(visibility) > 0 {
+		if !utils.Contains(utils.RoomVisibility, visibility[0]) {
+			return fmt.Errorf("%s", "wrong visibility value for room(public|private)")
+		}
+		param.Visibility = visibility[0]
+	}
+
 	if keyword, ok := values["keyword"]; ok && len(keyword) > 0 {
 		param.Keyword = keyword[0]
 	}
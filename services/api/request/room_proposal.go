@@ -0,0 +1,109 @@
+package request
+
+import (
+	"dots-api/lib/array"
+	"dots-api/lib/utils"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	// RoomProposalReq is a session a member proposes at a game of a cafe.
+	RoomProposalReq struct {
+		GameCode           string `json:"game_code" validate:"required,max=50"`
+		Name               string `json:"name" validate:"required,max=100"`
+		Description        string `json:"description" validate:"max=2000"`
+		StartDate          string `json:"start_date" validate:"required"`
+		StartTime          string `json:"start_time" validate:"required"`
+		EndTime            string `json:"end_time" validate:"required"`
+		MaximumParticipant int    `json:"maximum_participant" validate:"required,min=2"`
+	}
+
+	// ApproveRoomProposalReq completes a proposal into a room. The reviewing
+	// admin is the game master unless another one is given.
+	ApproveRoomProposalReq struct {
+		GameMasterCode string  `json:"game_master_code" validate:"max=50"`
+		RoomType       string  `json:"room_type" validate:"max=50"`
+		Difficulty     string  `json:"difficulty" validate:"max=50"`
+		Instruction    string  `json:"instruction" validate:"max=500"`
+		BookingPrice   float64 `json:"booking_price" validate:"required,min=10000"`
+		RewardPoint    int     `json:"reward_point"`
+		InstagramLink  string  `json:"instagram_link" validate:"max=500"`
+		ImageURL       string  `json:"image_url" validate:"max=500"`
+	}
+
+	RejectRoomProposalReq struct {
+		Reason string `json:"reason" validate:"required,max=500"`
+	}
+
+	RoomProposalParam struct {
+		Page     int    `json:"page"`
+		MaxPage  int    `json:"max_page"`
+		Limit    int    `json:"limit"`
+		Offset   int    `json:"offset"`
+		Count    int    `json:"count"`
+		Sort     string `json:"sort"`
+		Order    string `json:"order"`
+		Keyword  string `json:"keyword"`
+		Status   string `json:"status"`
+		CafeCode string `json:"cafe_code"`
+		// UserCode limits the list to the proposals of one member, set for
+		// members listing their own proposals.
+		UserCode string `json:"-"`
+	}
+)
+
+func (param *RoomProposalParam) ParseRoomProposal(values url.Values) error {
+	param.Keyword = ""
+	param.Page = 1
+	param.Limit = 10
+	param.Sort = "asc"
+	param.Order = "rp.created_date"
+	param.Status = ""
+	param.CafeCode = ""
+	param.Offset = 0
+
+	if page, ok := values["page"]; ok && len(page) > 0 {
+		if p, err := strconv.Atoi(page[0]); err == nil && p > 1 {
+			param.Page = p
+		}
+	}
+
+	if sort, ok := values["sort"]; ok && len(sort) > 0 && strings.ToLower(sort[0]) == "desc" {
+		param.Sort = "desc"
+	}
+
+	if order, ok := values["order"]; ok && len(order) > 0 {
+		arrStr := new(array.ArrStr)
+		if exist, _ := arrStr.InArray(order[0], []string{"rp.name", "rp.status", "rp.start_date", "rp.created_date"}); exist {
+			param.Order = order[0]
+		}
+	}
+
+	if status, ok := values["status"]; ok && len(status) > 0 {
+		if !utils.Contains(utils.StatusRoomProposal, status[0]) {
+			return fmt.Errorf("%s", "wrong status value for room proposals(pending|approved|rejected|cancelled)")
+		}
+		param.Status = status[0]
+	}
+
+	if cafeCode, ok := values["cafe_code"]; ok && len(cafeCode) > 0 {
+		param.CafeCode = cafeCode[0]
+	}
+
+	if keyword, ok := values["keyword"]; ok && len(keyword) > 0 {
+		param.Keyword = keyword[0]
+	}
+
+	if limit, ok := values["limit"]; ok && len(limit) > 0 {
+		if l, err := strconv.Atoi(limit[0]); err == nil {
+			param.Limit = l
+		}
+	}
+
+	param.Offset = (param.Page - 1) * param.Limit
+
+	return nil
+}
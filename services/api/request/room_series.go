@@ -0,0 +1,97 @@
+package request
+
+import (
+	"dots-api/lib/array"
+	"dots-api/lib/utils"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	RoomSeriesReq struct {
+		GameMasterCode     string   `json:"game_master_code" validate:"required,max=50"`
+		GameCode           string   `json:"game_code" validate:"required,max=50"`
+		LocationCode       string   `json:"location_code" validate:"required,max=50"`
+		RoomType           string   `json:"room_type" validate:"required,max=50"`
+		Name               string   `json:"name" validate:"required,max=100"`
+		Description        string   `json:"description"`
+		Instruction        string   `json:"instruction" validate:"max=500"`
+		Difficulty         string   `json:"difficulty" validate:"max=50"`
+		StartTime          string   `json:"start_time" validate:"required"`
+		EndTime            string   `json:"end_time" validate:"required"`
+		MaximumParticipant int      `json:"maximum_participant" validate:"required"`
+		BookingPrice       float64  `json:"booking_price" validate:"required,min=10000"`
+		RewardPoint        int      `json:"reward_point"`
+		InstagramLink      string   `json:"instagram_link" validate:"max=500"`
+		ImageURL           string   `json:"image_url" validate:"max=500"`
+		Status             string   `json:"status" validate:"required,max=50"`
+		Frequency          string   `json:"frequency" validate:"required"`
+		Weekdays           []int    `json:"weekdays" validate:"required,min=1"`
+		StartDate          string   `json:"start_date" validate:"required"`
+		UntilDate          string   `json:"until_date"`
+		OccurrenceCount    int      `json:"occurrence_count" validate:"min=0"`
+		SkipDates          []string `json:"skip_dates"`
+	}
+
+	RoomSeriesParam struct {
+		Page    int    `json:"page"`
+		MaxPage int    `json:"max_page"`
+		Limit   int    `json:"limit"`
+		Offset  int    `json:"offset"`
+		Count   int    `json:"count"`
+		Sort    string `json:"sort"`
+		Order   string `json:"order"`
+		Keyword string `json:"keyword"`
+		Status  string `json:"status"`
+	}
+)
+
+func (param *RoomSeriesParam) ParseRoomSeries(values url.Values) error {
+	param.Keyword = ""
+	param.Page = 1
+	param.Limit = 10
+	param.Sort = "desc"
+	param.Order = "rs.created_date"
+	param.Status = ""
+	param.Offset = 0
+
+	if page, ok := values["page"]; ok && len(page) > 0 {
+		if p, err := strconv.Atoi(page[0]); err == nil && p > 1 {
+			param.Page = p
+		}
+	}
+
+	if sort, ok := values["sort"]; ok && len(sort) > 0 && strings.ToLower(sort[0]) == "asc" {
+		param.Sort = "asc"
+	}
+
+	if order, ok := values["order"]; ok && len(order) > 0 {
+		arrStr := new(array.ArrStr)
+		if exist, _ := arrStr.InArray(order[0], []string{"rs.name", "rs.status", "rs.start_date", "rs.created_date"}); exist {
+			param.Order = order[0]
+		}
+	}
+
+	if status, ok := values["status"]; ok && len(status) > 0 {
+		if !utils.Contains(utils.StatusRoomSeries, status[0]) {
+			return fmt.Errorf("%s", "wrong status value for room series(active|inactive)")
+		}
+		param.Status = status[0]
+	}
+
+	if keyword, ok := values["keyword"]; ok && len(keyword) > 0 {
+		param.Keyword = keyword[0]
+	}
+
+	if limit, ok := values["limit"]; ok && len(limit) > 0 {
+		if l, err := strconv.Atoi(limit[0]); err == nil {
+			param.Limit = l
+		}
+	}
+
+	param.Offset = (param.Page - 1) * param.Limit
+
+	return nil
+}
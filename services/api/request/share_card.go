@@ -0,0 +1,6 @@
+package request
+
+type ShareCardReq struct {
+	CardType      string `json:"card_type" validate:"required,oneof=badge tier tournament"`
+	ReferenceCode string `json:"reference_code" validate:"required_unless=CardType tier"`
+}
@@ -8,22 +8,23 @@ import (
 )
 
 type TournamentReq struct {
-	GameCode        string   `json:"game_code"`
-	ImageUrl        string   `json:"image_url"`
-	Name            string   `json:"name" validate:"required,max=100"`
-	TournamentRules string   `json:"tournament_rules"`
-	Level           string   `json:"level"`
-	StartDate       string   `json:"start_date"`
-	EndDate         string   `json:"end_date"`
-	StartTime       string   `json:"start_time"`
-	EndTime         string   `json:"end_time"`
-	PlayerSlot      int64    `json:"player_slot"`
-	BookingPrice    float64  `json:"booking_price"`
-	BadgeCodes      []string `json:"badge_codes"`
-	PrizesImgUrl    string   `json:"prizes_img_url"`
-	Status          string   `json:"status" validate:"max=10"`
-	ParticipantVP   int64    `json:"participant_vp"`
-	LocationCode    string   `json:"location_code" validate:"max=50"`
+	GameCode           string   `json:"game_code"`
+	ImageUrl           string   `json:"image_url"`
+	Name               string   `json:"name" validate:"required,max=100"`
+	TournamentRules    string   `json:"tournament_rules"`
+	Level              string   `json:"level"`
+	StartDate          string   `json:"start_date"`
+	EndDate            string   `json:"end_date"`
+	StartTime          string   `json:"start_time"`
+	EndTime            string   `json:"end_time"`
+	PlayerSlot         int64    `json:"player_slot"`
+	MinimalParticipant int64    `json:"minimal_participant" validate:"min=0"`
+	BookingPrice       float64  `json:"booking_price"`
+	BadgeCodes         []string `json:"badge_codes"`
+	PrizesImgUrl       string   `json:"prizes_img_url"`
+	Status             string   `json:"status" validate:"max=10"`
+	ParticipantVP      int64    `json:"participant_vp"`
+	LocationCode       string   `json:"location_code" validate:"max=50"`
 }
 
 type SetWinnerTournamentReq struct {
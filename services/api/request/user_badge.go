@@ -9,14 +9,15 @@ import (
 
 type (
 	UserBadgeParam struct {
-		Page    int    `json:"page"`
-		Limit   int    `json:"limit"`
-		Offset  int    `json:"offset"`
-		Count   int    `json:"count"`
-		Sort    string `json:"sort"`
-		MaxPage int    `json:"max_page"`
-		Order   string `json:"order"`
-		IsClaim string `json:"is_claim"`
+		Page     int    `json:"page"`
+		Limit    int    `json:"limit"`
+		Offset   int    `json:"offset"`
+		Count    int    `json:"count"`
+		Sort     string `json:"sort"`
+		MaxPage  int    `json:"max_page"`
+		Order    string `json:"order"`
+		IsClaim  string `json:"is_claim"`
+		Archived string `json:"archived"`
 	}
 )
 
@@ -28,6 +29,7 @@ func (param *UserBadgeParam) ParseUserBadge(values url.Values) error {
 	param.Order = "ub.is_claim,ub.created_date,b.created_date"
 	param.Offset = 0
 	param.IsClaim = ""
+	param.Archived = ""
 
 	if page, ok := values["page"]; ok && len(page) > 0 {
 		if p, err := strconv.Atoi(page[0]); err == nil && p > 1 {
@@ -53,6 +55,13 @@ func (param *UserBadgeParam) ParseUserBadge(values url.Values) error {
 		}
 	}
 
+	if archived, ok := values["archived"]; ok && len(archived) > 0 {
+		arrStr := new(array.ArrStr)
+		if exist, _ := arrStr.InArray(archived[0], []string{"true", "false"}); exist {
+			param.Archived = archived[0]
+		}
+	}
+
 	if limit, ok := values["limit"]; ok && len(limit) > 0 {
 		if l, err := strconv.Atoi(limit[0]); err == nil {
 			param.Limit = l
@@ -32,7 +32,7 @@ func (param *UserTransactionParam) ParseUserTransactionParam(values url.Values)
 
 	if status, ok := values["status"]; ok && len(status) > 0 {
 		if !utils.Contains(utils.XenditTransactionStatus, status[0]) {
-			return fmt.Errorf("%s", "wrong status value for transaction(PENDING|PAID|SETTLED|EXPIRED")
+			return fmt.Errorf("%s", "wrong status value for transaction(PENDING|PAID|SETTLED|EXPIRED|REFUNDED)")
 		}
 		param.Status = status[0]
 	}
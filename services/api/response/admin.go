@@ -9,4 +9,5 @@ type AdminRes struct {
 	Status      string `json:"status"`
 	ImageURL    string `json:"image_url"`
 	PhoneNumber string `json:"phone_number"`
+	UserCode    string `json:"user_code,omitempty"`
 }
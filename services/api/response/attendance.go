@@ -0,0 +1,34 @@
+package response
+
+type (
+	AttendanceRes struct {
+		EventType        string `json:"event_type"`
+		EventCode        string `json:"event_code"`
+		EventName        string `json:"event_name"`
+		EventStartDate   string `json:"event_start_date"`
+		UserCode         string `json:"user_code"`
+		UserName         string `json:"user_name"`
+		AttendanceStatus string `json:"attendance_status"`
+		CheckedInDate    string `json:"checked_in_date"`
+		CheckInMethod    string `json:"check_in_method"`
+		CheckedInBy      string `json:"checked_in_by"`
+	}
+
+	AttendanceSummaryRes struct {
+		Booked         int `json:"booked"`
+		Attended       int `json:"attended"`
+		NoShow         int `json:"no_show"`
+		Expected       int `json:"expected"`
+		AttendanceRate int `json:"attendance_rate"`
+	}
+
+	EventAttendanceRes struct {
+		Summary      AttendanceSummaryRes `json:"summary"`
+		Participants []AttendanceRes      `json:"participants"`
+	}
+
+	UserAttendanceRes struct {
+		Summary AttendanceSummaryRes `json:"summary"`
+		Events  []AttendanceRes      `json:"events"`
+	}
+)
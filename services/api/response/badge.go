@@ -1,16 +1,52 @@
 package response
 
 type BadgeRes struct {
-	BadgeCode     string         `json:"badge_code"`
-	BadgeCategory string         `json:"badge_category"`
-	Name          string         `json:"name"`
-	ImageURL      string         `json:"image_url"`
-	BadgeRules    []BadgeRuleRes `json:"badge_rules"`
-	VPPoint       int64          `json:"vp_point"`
-	Description   string         `json:"description"`
-	Status        string         `json:"status"`
-	ParentCode    string         `json:"parent_code"`
-	CreatedDate   string         `json:"created_date"`
-	UpdatedDate   string         `json:"updated_date"`
-	DeletedDate   string         `json:"deleted_date"`
+	BadgeCode          string         `json:"badge_code"`
+	BadgeCategory      string         `json:"badge_category"`
+	Name               string         `json:"name"`
+	ImageURL           string         `json:"image_url"`
+	BadgeRules         []BadgeRuleRes `json:"badge_rules"`
+	VPPoint            int64          `json:"vp_point"`
+	Description        string         `json:"description"`
+	Status             string         `json:"status"`
+	ParentCode         string         `json:"parent_code"`
+	RuleOperator       string         `json:"rule_operator"`
+	SeriesLevel        int64          `json:"series_level"`
+	AvailableStartDate string         `json:"available_start_date"`
+	AvailableEndDate   string         `json:"available_end_date"`
+	ExpiredDate        string         `json:"expired_date"`
+	CreatedDate        string         `json:"created_date"`
+	UpdatedDate        string         `json:"updated_date"`
+	DeletedDate        string         `json:"deleted_date"`
+}
+
+type BadgePreviewRes struct {
+	QualifiedCount int                     `json:"qualified_count"`
+	SampleUsers    []BadgePreviewUserRes   `json:"sample_users"`
+	Compare        *BadgePreviewCompareRes `json:"compare,omitempty"`
+}
+
+type BadgePreviewUserRes struct {
+	UserCode string `json:"user_code"`
+	UserName string `json:"username"`
+	FullName string `json:"fullname"`
+	ImageURL string `json:"image_url"`
+}
+
+type BadgePreviewCompareRes struct {
+	BadgeCode         string `json:"badge_code"`
+	QualifiedCount    int    `json:"qualified_count"`
+	BothCount         int    `json:"both_count"`
+	OnlyDraftCount    int    `json:"only_draft_count"`
+	OnlyExistingCount int    `json:"only_existing_count"`
+}
+
+type BadgeSeriesRes struct {
+	SeriesCode  string     `json:"series_code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ImageURL    string     `json:"image_url"`
+	Status      string     `json:"status"`
+	Levels      []BadgeRes `json:"levels"`
+	CreatedDate string     `json:"created_date"`
 }
@@ -0,0 +1,42 @@
+package response
+
+type (
+	CafeTableRes struct {
+		TableCode string `json:"table_code"`
+		Name      string `json:"name"`
+		TableType string `json:"table_type"`
+		Seats     int    `json:"seats"`
+		Status    string `json:"status"`
+	}
+
+	CafeTableBookingRes struct {
+		EventType    string `json:"event_type"`
+		EventCode    string `json:"event_code"`
+		EventName    string `json:"event_name"`
+		StartDate    string `json:"start_date"`
+		EndDate      string `json:"end_date"`
+		Seats        int    `json:"seats"`
+		Participants int    `json:"participants"`
+		Status       string `json:"status"`
+	}
+
+	FloorPlanTableRes struct {
+		TableCode string                `json:"table_code"`
+		Name      string                `json:"name"`
+		TableType string                `json:"table_type"`
+		Seats     int                   `json:"seats"`
+		Status    string                `json:"status"`
+		Bookings  []CafeTableBookingRes `json:"bookings"`
+	}
+
+	FloorPlanRes struct {
+		CafeCode    string              `json:"cafe_code"`
+		CafeName    string              `json:"cafe_name"`
+		Date        string              `json:"date"`
+		TotalTables int                 `json:"total_tables"`
+		TablesInUse int                 `json:"tables_in_use"`
+		TotalSeats  int                 `json:"total_seats"`
+		BookedSeats int                 `json:"booked_seats"`
+		Tables      []FloorPlanTableRes `json:"tables"`
+	}
+)
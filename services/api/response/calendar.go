@@ -0,0 +1,6 @@
+package response
+
+type CalendarFeedRes struct {
+	FeedUrl   string `json:"feed_url"`
+	WebcalUrl string `json:"webcal_url"`
+}
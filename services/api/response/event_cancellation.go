@@ -0,0 +1,30 @@
+package response
+
+type (
+	EventCancellationRes struct {
+		EventType    string                            `json:"event_type"`
+		EventCode    string                            `json:"event_code"`
+		Reason       string                            `json:"reason"`
+		CancelledBy  string                            `json:"cancelled_by"`
+		CreatedDate  string                            `json:"created_date"`
+		Total        int                               `json:"total"`
+		Pending      int                               `json:"pending"`
+		Refunded     int                               `json:"refunded"`
+		Failed       int                               `json:"failed"`
+		Participants []EventCancellationParticipantRes `json:"participants"`
+	}
+
+	EventCancellationParticipantRes struct {
+		UserCode          string `json:"user_code"`
+		UserName          string `json:"user_name"`
+		ParticipantStatus string `json:"participant_status"`
+		TransactionCode   string `json:"transaction_code"`
+		Amount            int64  `json:"amount"`
+		RefundStatus      string `json:"refund_status"`
+		RefundId          string `json:"refund_id"`
+		RefundError       string `json:"refund_error"`
+		ReversedPoint     int    `json:"reversed_point"`
+		PushSent          bool   `json:"push_sent"`
+		EmailSent         bool   `json:"email_sent"`
+	}
+)
@@ -0,0 +1,50 @@
+package response
+
+type (
+	GameMasterAvailabilityRes struct {
+		Weekday   int    `json:"weekday"`
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+	}
+
+	GameMasterDayOffRes struct {
+		Date   string `json:"date"`
+		Reason string `json:"reason"`
+	}
+
+	GameMasterAvailabilityDetailRes struct {
+		Availabilities []GameMasterAvailabilityRes `json:"availabilities"`
+		DaysOff        []GameMasterDayOffRes       `json:"days_off"`
+	}
+
+	GameMasterSessionRes struct {
+		RoomCode     string `json:"room_code"`
+		Name         string `json:"name"`
+		GameName     string `json:"game_name"`
+		StartDate    string `json:"start_date"`
+		EndDate      string `json:"end_date"`
+		StartTime    string `json:"start_time"`
+		EndTime      string `json:"end_time"`
+		Status       string `json:"status"`
+		Participants int    `json:"participants"`
+	}
+
+	GameMasterScheduleRes struct {
+		GameMasterCode string                      `json:"game_master_code"`
+		GameMasterName string                      `json:"game_master_name"`
+		StartDate      string                      `json:"start_date"`
+		EndDate        string                      `json:"end_date"`
+		Sessions       []GameMasterSessionRes      `json:"sessions"`
+		Availabilities []GameMasterAvailabilityRes `json:"availabilities"`
+		DaysOff        []GameMasterDayOffRes       `json:"days_off"`
+	}
+
+	GameMasterWorkloadRes struct {
+		GameMasterCode string  `json:"game_master_code"`
+		GameMasterName string  `json:"game_master_name"`
+		WeekStart      string  `json:"week_start"`
+		Sessions       int     `json:"sessions"`
+		Hours          float64 `json:"hours"`
+		Participants   int     `json:"participants"`
+	}
+)
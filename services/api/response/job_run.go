@@ -0,0 +1,11 @@
+package response
+
+type JobRunRes struct {
+	JobName       string `json:"job_name"`
+	Status        string `json:"status"`
+	ItemCount     int    `json:"item_count"`
+	ErrorMessage  string `json:"error_message"`
+	ScheduledDate string `json:"scheduled_date"`
+	StartedDate   string `json:"started_date"`
+	FinishedDate  string `json:"finished_date"`
+}
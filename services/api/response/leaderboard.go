@@ -0,0 +1,13 @@
+package response
+
+type LeaderboardRes struct {
+	Rank         int     `json:"rank"`
+	UserCode     string  `json:"user_code"`
+	UserName     string  `json:"user_name"`
+	UserImageUrl string  `json:"user_image_url"`
+	Played       int     `json:"played"`
+	Wins         int     `json:"wins"`
+	Podiums      int     `json:"podiums"`
+	BestScore    int64   `json:"best_score"`
+	AverageScore float64 `json:"average_score"`
+}
@@ -21,6 +21,7 @@ type RoomRes struct {
 	StartTime          string               `json:"start_time"`
 	EndTime            string               `json:"end_time"`
 	MaximumParticipant int                  `json:"maximum_participant"`
+	MinimalParticipant int                  `json:"minimal_participant"`
 	DayPastEndDate     float64              `json:"day_past_end_date"`
 	BookingPrice       float64              `json:"booking_price"`
 	RewardPoint        int                  `json:"reward_point"`
@@ -29,6 +30,10 @@ type RoomRes struct {
 	CurrentUsedSlot    int                  `json:"current_used_slot"`
 	RoomParticipant    []RoomParticipantRes `json:"room_participants"`
 	HaveJoined         bool                 `json:"have_joined"`
+	Visibility         string               `json:"visibility"`
+	RequiresApproval   bool                 `json:"requires_approval"`
+	HostUserCode       string               `json:"host_user_code"`
+	HostUserName       string               `json:"host_user_name"`
 }
 
 type RoomListRes struct {
@@ -58,9 +63,37 @@ type RoomListRes struct {
 	GameCode           string  `json:"game_code"`
 	GameName           string  `json:"game_name"`
 	GameImgUrl         string  `json:"game_img_url"`
+	Visibility         string  `json:"visibility"`
+	RequiresApproval   bool    `json:"requires_approval"`
+	HostUserCode       string  `json:"host_user_code"`
+	HostUserName       string  `json:"host_user_name"`
 }
 
 type BookingRes struct {
 	InvoiceUrl string `json:"invoice_url"`
 	ExpiredAt  string `json:"expired_at"`
 }
+
+type RoomInviteRes struct {
+	RoomCode         string `json:"room_code"`
+	Visibility       string `json:"visibility"`
+	RequiresApproval bool   `json:"requires_approval"`
+	InviteCode       string `json:"invite_code"`
+	InviteLink       string `json:"invite_link"`
+}
+
+type JoinRoomRes struct {
+	RoomCode string `json:"room_code"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+}
+
+type RoomJoinRequestRes struct {
+	UserCode     string `json:"user_code"`
+	UserName     string `json:"user_name"`
+	UserImgUrl   string `json:"user_img_url"`
+	Status       string `json:"status"`
+	ReviewedBy   string `json:"reviewed_by"`
+	ReviewedDate string `json:"reviewed_date"`
+	CreatedDate  string `json:"created_date"`
+}
@@ -0,0 +1,24 @@
+package response
+
+type RoomProposalRes struct {
+	ProposalCode       string `json:"proposal_code"`
+	UserCode           string `json:"user_code"`
+	UserName           string `json:"user_name"`
+	CafeCode           string `json:"cafe_code"`
+	CafeName           string `json:"cafe_name"`
+	GameCode           string `json:"game_code"`
+	GameName           string `json:"game_name"`
+	GameImgUrl         string `json:"game_img_url"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	StartDate          string `json:"start_date"`
+	StartTime          string `json:"start_time"`
+	EndTime            string `json:"end_time"`
+	MaximumParticipant int    `json:"maximum_participant"`
+	Status             string `json:"status"`
+	RoomCode           string `json:"room_code"`
+	RejectReason       string `json:"reject_reason"`
+	ReviewedBy         string `json:"reviewed_by"`
+	ReviewedDate       string `json:"reviewed_date"`
+	CreatedDate        string `json:"created_date"`
+}
@@ -0,0 +1,41 @@
+package response
+
+type (
+	RoomSeriesRes struct {
+		SeriesCode         string                    `json:"series_code"`
+		GameMasterCode     string                    `json:"game_master_code"`
+		GameMasterName     string                    `json:"game_master_name"`
+		GameCode           string                    `json:"game_code"`
+		GameName           string                    `json:"game_name"`
+		RoomType           string                    `json:"room_type"`
+		Name               string                    `json:"name"`
+		Description        string                    `json:"description"`
+		Instruction        string                    `json:"instruction"`
+		Difficulty         string                    `json:"difficulty"`
+		StartTime          string                    `json:"start_time"`
+		EndTime            string                    `json:"end_time"`
+		MaximumParticipant int                       `json:"maximum_participant"`
+		BookingPrice       float64                   `json:"booking_price"`
+		RewardPoint        int                       `json:"reward_point"`
+		InstagramLink      string                    `json:"instagram_link"`
+		ImageUrl           string                    `json:"image_url"`
+		LocationCity       string                    `json:"location_city"`
+		Frequency          string                    `json:"frequency"`
+		Weekdays           []int32                   `json:"weekdays"`
+		StartDate          string                    `json:"start_date"`
+		UntilDate          string                    `json:"until_date"`
+		OccurrenceCount    int                       `json:"occurrence_count"`
+		SkipDates          []string                  `json:"skip_dates"`
+		Status             string                    `json:"status"`
+		SubscriberCount    int                       `json:"subscriber_count"`
+		IsSubscribed       bool                      `json:"is_subscribed"`
+		Occurrences        []RoomSeriesOccurrenceRes `json:"occurrences,omitempty"`
+	}
+
+	RoomSeriesOccurrenceRes struct {
+		RoomCode       string `json:"room_code"`
+		OccurrenceDate string `json:"occurrence_date"`
+		Status         string `json:"status"`
+		IsDetached     bool   `json:"is_detached"`
+	}
+)
@@ -0,0 +1,9 @@
+package response
+
+type ShareCardRes struct {
+	CardType      string `json:"card_type"`
+	ReferenceCode string `json:"reference_code"`
+	ImageURL      string `json:"image_url"`
+	CreatedDate   string `json:"created_date"`
+	UpdatedDate   string `json:"updated_date"`
+}
@@ -18,6 +18,7 @@ type TournamentRes struct {
 	StartTime              string                     `json:"start_time"`
 	EndTime                string                     `json:"end_time"`
 	PlayerSlot             int64                      `json:"player_slot"`
+	MinimalParticipant     int64                      `json:"minimal_participant"`
 	BookingPrice           float64                    `json:"booking_price"`
 	ParticipantVP          int64                      `json:"participant_vp"`
 	Status                 string                     `json:"status"`
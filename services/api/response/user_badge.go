@@ -13,4 +13,47 @@ type UserBadgeRes struct {
 	CreatedDate   string `json:"created_date"`
 	IsBadgeOwned  bool   `json:"is_badge_owned"`
 	NeedToClaim   bool   `json:"need_to_claim"`
+	ExpiredDate   string `json:"expired_date"`
+	IsArchived    bool   `json:"is_archived"`
+}
+
+type UserBadgeProgressRes struct {
+	BadgeCode     string                 `json:"badge_code"`
+	BadgeName     string                 `json:"badge_name"`
+	BadgeImageURL string                 `json:"badge_image_url"`
+	BadgeCategory string                 `json:"badge_category"`
+	Description   string                 `json:"description"`
+	VPPoint       int64                  `json:"vp_point"`
+	RuleOperator  string                 `json:"rule_operator"`
+	IsBadgeOwned  bool                   `json:"is_badge_owned"`
+	Percentage    int                    `json:"percentage"`
+	Rules         []BadgeRuleProgressRes `json:"rules"`
+}
+
+type BadgeRuleProgressRes struct {
+	KeyCondition string      `json:"key_condition"`
+	Value        interface{} `json:"value"`
+	Current      int64       `json:"current"`
+	Target       int64       `json:"target"`
+	Percentage   int         `json:"percentage"`
+}
+
+type UserBadgeSeriesRes struct {
+	SeriesCode   string                    `json:"series_code"`
+	Name         string                    `json:"name"`
+	Description  string                    `json:"description"`
+	ImageURL     string                    `json:"image_url"`
+	CurrentLevel int64                     `json:"current_level"`
+	TotalLevel   int                       `json:"total_level"`
+	Levels       []UserBadgeSeriesLevelRes `json:"levels"`
+}
+
+type UserBadgeSeriesLevelRes struct {
+	Level         int64  `json:"level"`
+	BadgeCode     string `json:"badge_code"`
+	BadgeName     string `json:"badge_name"`
+	BadgeImageURL string `json:"badge_image_url"`
+	VPPoint       int64  `json:"vp_point"`
+	IsBadgeOwned  bool   `json:"is_badge_owned"`
+	Percentage    int    `json:"percentage"`
 }
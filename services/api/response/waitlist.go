@@ -0,0 +1,14 @@
+package response
+
+type WaitlistRes struct {
+	EventType        string `json:"event_type"`
+	EventCode        string `json:"event_code"`
+	EventName        string `json:"event_name"`
+	EventImageUrl    string `json:"event_image_url"`
+	UserCode         string `json:"user_code"`
+	UserName         string `json:"user_name"`
+	Status           string `json:"status"`
+	Position         int    `json:"position"`
+	OfferExpiredDate string `json:"offer_expired_date"`
+	CreatedDate      string `json:"created_date"`
+}
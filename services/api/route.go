@@ -64,9 +64,14 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 
 		//User Badges
 		r.With(app.VerifyAccessRoute).Get("/{code}/badges", nrWrap(h.GetUserBadgeListAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/badges/progress", nrWrap(h.GetUserBadgeProgressAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/badge-series", nrWrap(h.GetUserBadgeSeriesAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Get("/{code}/badges/{badge-code}", nrWrap(h.GetUserBadgeByBadgeCodeAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}/badges/{badge-code}", nrWrap(h.UpdateUserBadgeByBadgeCodeAct, app.NewRelic))
 
+		//User share cards
+		r.With(app.VerifyAccessRoute).Post("/{code}/share-cards", nrWrap(h.CreateShareCardAct, app.NewRelic))
+
 		//User favourite game
 		r.With(app.VerifyAccessRoute).Get("/{code}/favourite-games", nrWrap(h.GetUserFavouriteGameAct, app.NewRelic))
 
@@ -74,6 +79,12 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Get("/{code}/game-collection", nrWrap(h.GetUserGameCollectionAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Post("/{code}/game-collection", nrWrap(h.AddUserGameCollectionAct, app.NewRelic))
 
+		//User waitlists
+		r.With(app.VerifyAccessRoute).Get("/{code}/waitlists", nrWrap(h.GetUserWaitlistAct, app.NewRelic))
+
+		//User attendance
+		r.With(app.VerifyAccessRoute).Get("/{code}/attendance", nrWrap(h.GetUserAttendanceAct, app.NewRelic))
+
 		//User history game
 		r.With(app.VerifyAccessRoute).Get("/{code}/history-games", nrWrap(h.GetUserGameHistoryAct, app.NewRelic))
 
@@ -100,6 +111,13 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetCafeDetailAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateCafeAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteCafeAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/floor-plan", nrWrap(h.GetCafeFloorPlanAct, app.NewRelic))
+
+		// Table
+		r.With(app.VerifyAccessRoute).Get("/{code}/tables", nrWrap(h.GetCafeTablesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/tables", nrWrap(h.AddCafeTableAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/tables/{table_code}", nrWrap(h.UpdateCafeTableAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/tables/{table_code}", nrWrap(h.DeleteCafeTableAct, app.NewRelic))
 	})
 
 	// Master Cafe
@@ -121,6 +139,7 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateGameAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteGameAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Get("/{code}/qrcode", nrWrap(h.GetGameQRCodeAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/leaderboard", nrWrap(h.GetGameLeaderboardAct, app.NewRelic))
 	})
 
 	// Master Admin
@@ -172,8 +191,62 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Put("/{code}/close", nrWrap(h.SetWinnerRoomAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetRoomByCode, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Post("/{code}/book", nrWrap(h.BookingRoom, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/cancel", nrWrap(h.CancelRoomBookingAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/participants/{user_code}", nrWrap(h.RemoveRoomParticipantAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/participants/{user_code}/check-in", nrWrap(h.CheckInRoomParticipantAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/check-in-qr", nrWrap(h.GetRoomCheckInQRAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/attendance", nrWrap(h.GetRoomAttendanceAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}/status", nrWrap(h.UpdateRoomStatus, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/table", nrWrap(h.AssignRoomTableAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteRoom, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/cancel-event", nrWrap(h.CancelRoomEventAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/cancellation", nrWrap(h.GetRoomCancellationAct, app.NewRelic))
+
+		// Waitlist
+		r.With(app.VerifyAccessRoute).Get("/{code}/waitlist", nrWrap(h.GetRoomWaitlistAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/waitlist", nrWrap(h.JoinRoomWaitlistAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/waitlist", nrWrap(h.LeaveRoomWaitlistAct, app.NewRelic))
+
+		// Private room
+		r.With(app.VerifyAccessRoute).Post("/join", nrWrap(h.JoinRoomAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/invite", nrWrap(h.GetRoomInviteAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/invite/reset", nrWrap(h.ResetRoomInviteAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/join-requests", nrWrap(h.GetRoomJoinRequestsAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/join-requests/{user_code}", nrWrap(h.ReviewRoomJoinRequestAct, app.NewRelic))
+	})
+
+	// Room Series
+	r.Route("/room-series", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Get("/", nrWrap(h.GetRoomSeriesListAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/", nrWrap(h.AddRoomSeriesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetRoomSeriesDetailAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateRoomSeriesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/subscription", nrWrap(h.SubscribeRoomSeriesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/subscription", nrWrap(h.UnsubscribeRoomSeriesAct, app.NewRelic))
+	})
+
+	// Room Proposal
+	r.Route("/room-proposals", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Get("/", nrWrap(h.GetRoomProposalListAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/", nrWrap(h.AddRoomProposalAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetRoomProposalDetailAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.CancelRoomProposalAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/approve", nrWrap(h.ApproveRoomProposalAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/reject", nrWrap(h.RejectRoomProposalAct, app.NewRelic))
+	})
+
+	// Game Master
+	r.Route("/game-masters", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Get("/workload", nrWrap(h.GetGameMasterWorkloadAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/me/schedule", nrWrap(h.GetOwnGameMasterScheduleAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/schedule", nrWrap(h.GetGameMasterScheduleAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/availability", nrWrap(h.GetGameMasterAvailabilityAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/availability", nrWrap(h.UpdateGameMasterAvailabilityAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/days-off", nrWrap(h.AddGameMasterDayOffAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/days-off/{date}", nrWrap(h.DeleteGameMasterDayOffAct, app.NewRelic))
 	})
 
 	// Badges
@@ -184,10 +257,21 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Get("/unowned/{user_code}", nrWrap(h.GetUnownedBadgeUserListAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetBadgeDetailAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Post("/", nrWrap(h.AddBadgeAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/preview", nrWrap(h.PreviewBadgeAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateBadgeAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteBadgeAct, app.NewRelic))
 	})
 
+	// Badge Series
+	r.Route("/badge-series", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Get("/", nrWrap(h.GetBadgeSeriesListAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}", nrWrap(h.GetBadgeSeriesDetailAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/", nrWrap(h.AddBadgeSeriesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateBadgeSeriesAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteBadgeSeriesAct, app.NewRelic))
+	})
+
 	// Badges
 	r.Route("/tournament-badges", func(r chi.Router) {
 		r.Use(app.VerifyJwtToken)
@@ -205,8 +289,21 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Put("/{code}", nrWrap(h.UpdateTournamentAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}/close", nrWrap(h.SetWinnerTournamentAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Put("/{code}/status", nrWrap(h.UpdateTournamentStatus, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Put("/{code}/table", nrWrap(h.AssignTournamentTableAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteTournamentAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/cancel-event", nrWrap(h.CancelTournamentEventAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/cancellation", nrWrap(h.GetTournamentCancellationAct, app.NewRelic))
 		r.With(app.VerifyAccessRoute).Post("/{code}/book", nrWrap(h.BookingTournamentAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/cancel", nrWrap(h.CancelTournamentBookingAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/participants/{user_code}", nrWrap(h.RemoveTournamentParticipantAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/participants/{user_code}/check-in", nrWrap(h.CheckInTournamentParticipantAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/check-in-qr", nrWrap(h.GetTournamentCheckInQRAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Get("/{code}/attendance", nrWrap(h.GetTournamentAttendanceAct, app.NewRelic))
+
+		// Waitlist
+		r.With(app.VerifyAccessRoute).Get("/{code}/waitlist", nrWrap(h.GetTournamentWaitlistAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Post("/{code}/waitlist", nrWrap(h.JoinTournamentWaitlistAct, app.NewRelic))
+		r.With(app.VerifyAccessRoute).Delete("/{code}/waitlist", nrWrap(h.LeaveTournamentWaitlistAct, app.NewRelic))
 	})
 
 	// Upload
@@ -287,6 +384,26 @@ func AppSubsRoute(r chi.Router, app *bootstrap.App) {
 		r.With(app.VerifyAccessRoute).Delete("/{code}", nrWrap(h.DeleteRoleAct, app.NewRelic))
 	})
 
+	// Event check-in
+	r.Route("/check-in", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Post("/", nrWrap(h.CheckInAct, app.NewRelic))
+	})
+
+	// Worker job history
+	r.Route("/job-runs", func(r chi.Router) {
+		r.Use(app.VerifyJwtToken)
+		r.With(app.VerifyAccessRoute).Get("/", nrWrap(h.GetJobRunListAct, app.NewRelic))
+	})
+
+	// Calendar feed
+	r.Route("/calendar", func(r chi.Router) {
+		r.Get("/users/{token}.ics", nrWrap(h.GetUserCalendarAct, app.NewRelic))
+		r.Get("/cafes/{code}.ics", nrWrap(h.GetCafeCalendarAct, app.NewRelic))
+		r.With(app.VerifyJwtToken, app.VerifyAccessRoute).Get("/me", nrWrap(h.GetCalendarFeedAct, app.NewRelic))
+		r.With(app.VerifyJwtToken, app.VerifyAccessRoute).Post("/me/reset", nrWrap(h.ResetCalendarFeedAct, app.NewRelic))
+	})
+
 	// User Notification
 	r.Route("/notifications", func(r chi.Router) {
 		r.Use(app.VerifyJwtToken)
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"dots-api/bootstrap"
+	"dots-api/lib/queue"
+	"dots-api/services/worker/command"
 	"fmt"
 	"log"
 	"net"
@@ -87,6 +89,14 @@ func (b Boot) Start(c *cli.Context) error {
 	// call routes
 	RegisterRoutes(r, b.App)
 
+	// The in-memory queue only lives in this process, so its consumers do too
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if b.App.Config.GetString("queue.driver") == queue.DriverMemory {
+		log.Printf("Queue driver: %s, running the consumers in the API", queue.DriverMemory)
+		go command.Contract{App: b.App}.RunInProcess(bgCtx)
+	}
+
 	// handle grace full shutdown
 	srv := http.Server{Addr: host, Handler: r}
 	srv.BaseContext = func(_ net.Listener) context.Context {
@@ -97,6 +107,7 @@ func (b Boot) Start(c *cli.Context) error {
 	go func() {
 		for range sng {
 			fmt.Println("shutting down..")
+			stopBackground()
 			err = valv.Shutdown(20 * time.Second)
 			if err != nil {
 				log.Println("Can't shutdown this server until all process are done!")
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// CheckMinimumParticipants cancels the rooms and tournaments that did not
+// reach their minimum participants by the cutoff, and warns their game master
+// and the admins shortly before it.
+func (app Contract) CheckMinimumParticipants(c *cli.Context) error {
+	return app.trackJob(utils.JobCheckMinimumParticipants, app.checkMinimumParticipants)
+}
+
+// checkMinimumParticipants returns how many events were warned or cancelled.
+func (app Contract) checkMinimumParticipants(ctx context.Context) (int, error) {
+	var (
+		m            = model.Contract{App: app.App}
+		cutoffHours  = app.Config.GetInt("minimum_participant.cutoff_hours")
+		warningHours = app.Config.GetInt("minimum_participant.warning_hours")
+	)
+
+	if cutoffHours <= 0 {
+		cutoffHours = utils.MinimumCutoffHours
+	}
+	if warningHours <= 0 {
+		warningHours = utils.MinimumWarningHours
+	}
+
+	return m.CheckMinimumParticipants(ctx, cutoffHours, warningHours)
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DeadLetterFlags ...
+var DeadLetterFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "queue",
+		Usage:    "Queue whose dead letters are inspected (dots_badges|dots_user_badge)",
+		Required: true,
+	},
+	&cli.BoolFlag{
+		Name:  "requeue",
+		Usage: "Move the dead letters back to the queue instead of listing them",
+	},
+	&cli.IntFlag{
+		Name:  "limit",
+		Value: 50,
+		Usage: "Maximum number of dead letters to list or requeue",
+	},
+}
+
+// DeadLetters lists the messages of a queue that failed every attempt, or
+// moves them back to the queue to be processed again.
+func (app Contract) DeadLetters(c *cli.Context) error {
+	var (
+		ctx   = context.Background()
+		name  = c.String("queue")
+		limit = c.Int("limit")
+	)
+
+	if !utils.Contains(rabbit.Queues, name) {
+		return fmt.Errorf("unknown queue %s", name)
+	}
+
+	if c.Bool("requeue") {
+		total, err := app.Queue.RequeueDeadLetters(ctx, name, limit)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Requeued %d dead letters to %s\n", total, name)
+		return nil
+	}
+
+	list, total, err := app.Queue.DeadLetters(ctx, name, limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d dead letters in %s, showing %d\n", total, rabbit.DeadLetterQueueName(name), len(list))
+	for i, v := range list {
+		fmt.Printf("%d. [%s] attempts: %d, error: %s\n   %s\n", i+1, v.DeadDate.Format(time.RFC3339), v.Attempt, v.LastError, v.Body)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// GenerateRoomSeries creates the upcoming rooms of the recurring room series
+// and notifies their subscribers.
+func (app Contract) GenerateRoomSeries(c *cli.Context) error {
+	return app.trackJob(utils.JobGenerateRoomSeries, app.generateRoomSeries)
+}
+
+// generateRoomSeries creates the upcoming rooms and returns how many were
+// created.
+func (app Contract) generateRoomSeries(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	return m.GenerateRoomSeries(ctx)
+}
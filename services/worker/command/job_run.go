@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"dots-api/services/worker/model"
+	"log"
+	"time"
+)
+
+// jobFunc runs a scheduled job and returns the number of items it processed.
+type jobFunc func(ctx context.Context) (int, error)
+
+// trackJob runs a job started from the command line, e.g. by crontab. The run
+// is recorded at the current time instead of a minute tick, so it never takes
+// the place of a scheduled run nor is skipped because of one.
+func (app Contract) trackJob(name string, job jobFunc) error {
+	return app.runJob(context.Background(), name, time.Now().UTC().Truncate(time.Microsecond), job)
+}
+
+// runJob runs a job once per schedule tick across all workers and records its
+// start, end, outcome and item count in job_runs. A tick that was already
+// claimed is skipped, unless its run was abandoned.
+func (app Contract) runJob(ctx context.Context, name string, tick time.Time, job jobFunc) error {
+	m := model.Contract{App: app.App}
+
+	acquired, err := m.AcquireJobRun(ctx, name, tick)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		log.Printf("[%s] Job skipped, already run for %s", name, tick.Format(time.RFC3339))
+		return nil
+	}
+
+	log.Printf("[%s] Job started", name)
+	var total int
+	err = safeRun(func() (err error) {
+		total, err = job(ctx)
+		return err
+	})
+
+	if finishErr := m.FinishJobRun(ctx, name, tick, total, err); finishErr != nil {
+		log.Printf("[%s] Job result err: %s", name, finishErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[%s] Job finished, %d items", name, total)
+	return nil
+}
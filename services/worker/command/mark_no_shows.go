@@ -0,0 +1,22 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// MarkNoShows flags the members who booked and paid for an event but did not
+// check in after it started.
+func (app Contract) MarkNoShows(c *cli.Context) error {
+	return app.trackJob(utils.JobMarkNoShows, app.markNoShows)
+}
+
+// markNoShows flags the no-shows and returns how many bookings were flagged.
+func (app Contract) markNoShows(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	return m.MarkNoShows(ctx)
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	outboxPollInterval  = time.Second
+	outboxMaxBackoff    = time.Minute
+	outboxPurgeInterval = time.Hour
+)
+
+// OutboxRelay publishes the events written to the outbox by the API over a
+// long-lived connection. When RabbitMQ is unavailable the events stay in the
+// outbox and the relay retries with a growing delay.
+func (app Contract) OutboxRelay(c *cli.Context) error {
+	return app.relayOutbox(c.Context)
+}
+
+// relayOutbox publishes the outbox events until ctx is cancelled.
+func (app Contract) relayOutbox(ctx context.Context) error {
+	var (
+		m         = model.Contract{App: app.App}
+		backoff   = outboxPollInterval
+		lastPurge time.Time
+	)
+
+	log.Printf("Outbox relay ready, PID: %d", os.Getpid())
+	for {
+		if ctx.Err() != nil {
+			log.Printf("Outbox relay stopped")
+			return nil
+		}
+
+		published, err := m.RelayOutboxEvents(ctx, utils.OutboxBatchSize)
+		if err != nil {
+			log.Printf("Outbox relay err: %s, retrying in %s", err, backoff)
+			sleep(ctx, backoff)
+			if backoff *= 2; backoff > outboxMaxBackoff {
+				backoff = outboxMaxBackoff
+			}
+			continue
+		}
+		backoff = outboxPollInterval
+
+		if time.Since(lastPurge) > outboxPurgeInterval {
+			purged, err := m.PurgeOutboxEvents(ctx, time.Now().UTC().AddDate(0, 0, -utils.OutboxRetentionDays))
+			if err != nil {
+				log.Printf("Outbox purge err: %s", err)
+			} else if purged > 0 {
+				log.Printf("Outbox purged %d published events", purged)
+			}
+			lastPurge = time.Now()
+		}
+
+		// Keep draining while full batches are published
+		if published < utils.OutboxBatchSize {
+			sleep(ctx, outboxPollInterval)
+		}
+	}
+}
+
+// sleep waits for d or until ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
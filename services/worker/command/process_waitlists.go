@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ProcessWaitlists rolls the seat offers of the room and tournament waitlists
+// to the next member once an offer expires.
+func (app Contract) ProcessWaitlists(c *cli.Context) error {
+	return app.trackJob(utils.JobProcessWaitlists, app.processWaitlists)
+}
+
+// processWaitlists processes the waitlists and returns how many seats were
+// offered.
+func (app Contract) processWaitlists(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	return m.ProcessWaitlists(ctx)
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// PublishScheduledBadges queues the badges whose earning window has opened
+// so the badge consumer awards them to the qualifying members. The messages go
+// through the outbox and are published by the outbox relay.
+func (app Contract) PublishScheduledBadges(c *cli.Context) error {
+	return app.trackJob(utils.JobPublishScheduledBadges, app.publishScheduledBadges)
+}
+
+// publishScheduledBadges queues the scheduled badges and returns how many were
+// queued.
+func (app Contract) publishScheduledBadges(ctx context.Context) (int, error) {
+	var (
+		m   = model.Contract{App: app.App}
+		now = time.Now().UTC()
+	)
+
+	total, err := m.QueueScheduledBadges(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("Published %d scheduled badges at %v", total, now.Format("Monday 2006-01-02 15:04:05"))
+	return total, nil
+}
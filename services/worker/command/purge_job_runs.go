@@ -0,0 +1,24 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// PurgeJobRuns deletes the job runs finished more than
+// utils.JobRunRetentionDays ago.
+func (app Contract) PurgeJobRuns(c *cli.Context) error {
+	return app.trackJob(utils.JobPurgeJobRuns, app.purgeJobRuns)
+}
+
+// purgeJobRuns returns how many job runs were deleted.
+func (app Contract) purgeJobRuns(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	purged, err := m.PurgeJobRuns(ctx, time.Now().UTC().AddDate(0, 0, -utils.JobRunRetentionDays))
+	return int(purged), err
+}
@@ -0,0 +1,23 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ReleaseSeatHolds frees the seats of the bookings that were not paid before
+// their invoice expired, so they can be booked again.
+func (app Contract) ReleaseSeatHolds(c *cli.Context) error {
+	return app.trackJob(utils.JobReleaseSeatHolds, app.releaseSeatHolds)
+}
+
+// releaseSeatHolds releases the expired seat holds and returns how many
+// bookings were released.
+func (app Contract) releaseSeatHolds(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	return m.ReleaseExpiredSeatHolds(ctx)
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"dots-api/lib/utils"
 	"dots-api/services/worker/model"
 	"fmt"
 	"time"
@@ -11,40 +12,47 @@ import (
 
 // UpdateStatusRoomAndTournament ...
 func (app Contract) UpdateStatusRoomAndTournament(c *cli.Context) error {
+	return app.trackJob(utils.JobSetInactiveRoomAndTournament, app.setInactiveRoomAndTournament)
+}
+
+// setInactiveRoomAndTournament deactivates the rooms and tournaments that have
+// ended, and returns how many were deactivated.
+func (app Contract) setInactiveRoomAndTournament(ctx context.Context) (int, error) {
 	var (
 		dataListRoomCode       []string
 		dataListTournamentCode []string
 		err                    error
-		// Begin Context
-		ctx = context.Background()
-		m   = model.Contract{App: app.App}
-		now = time.Now().UTC()
+		total                  int
+		m                      = model.Contract{App: app.App}
+		now                    = time.Now().UTC()
 	)
 
 	dataListRoomCode, err = m.GetListRoomCodes(m.DB, ctx)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, roomCode := range dataListRoomCode {
 		err = m.UpdateRoomStatus(app.DB, ctx, roomCode, "inactive")
 		if err != nil {
-			return err
+			return total, err
 		}
+		total++
 	}
 
 	dataListTournamentCode, err = m.GetListTournamentCodes(m.DB, ctx)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, tournamentCode := range dataListTournamentCode {
 		err = m.UpdateTournamentStatus(app.DB, ctx, tournamentCode, "inactive")
 		if err != nil {
-			return err
+			return total, err
 		}
+		total++
 	}
 
 	fmt.Printf("Set inactive tournament and room success at %v", now.Format("Monday 2006-01-02 15:04:05"))
-	return nil
+	return total, nil
 }
@@ -16,9 +16,18 @@ import (
 
 // UserRoomReminder ...
 func (app Contract) UserRoomReminder(c *cli.Context) error {
+	return app.trackJob(utils.JobRoomReminder, app.remindRoomParticipants)
+}
+
+// remindRoomParticipants notifies the participants of the rooms starting in
+// one and three days, and returns the number of notifications sent.
+func (app Contract) remindRoomParticipants(ctx context.Context) (int, error) {
 	var dataListRoom []model.RoomEnt
 
-	var err error
+	var (
+		err   error
+		total int
+	)
 	now := time.Now().UTC()
 
 	// Calculate h-3 reminder
@@ -27,20 +36,18 @@ func (app Contract) UserRoomReminder(c *cli.Context) error {
 	// Calculate h-1 reminder
 	h1Reminder := now.AddDate(0, 0, 1).Format(utils.DATE_FORMAT)
 
-	// Begin Context
-	ctx := context.Background()
 	m := model.Contract{App: app.App}
 
 	dataListRoom, err = m.GetRoomList(m.DB, ctx, h1Reminder)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, room := range dataListRoom {
 
 		dataListUser, err := m.GetAllParticipantByRoomCode(m.DB, ctx, room.RoomCode)
 		if err != nil {
-			return err
+			return total, err
 		}
 
 		// Populate response
@@ -59,14 +66,15 @@ func (app Contract) UserRoomReminder(c *cli.Context) error {
 
 			descriptionJSON, err := json.Marshal(description)
 			if err != nil {
-				return err
+				return total, err
 			}
 
 			// Insert data into db
 			err = m.AddNotification(m.DB, ctx, notifCode, "user", user.UserCode, room.RoomCode, utils.RoomBookingType, room.Name, descriptionJSON, room.RoomImgUrl)
 			if err != nil {
-				return err
+				return total, err
 			}
+			total++
 
 			onesignal := onesignal.New(m.App)
 			OSDescription := utils.RoomReminderPushNotificationDescription + "\n\n" + "Room name: " + room.Name + "\n" + "Date: " + room.StartDate.Format("2006-01-02") + "\n" + "Location: " + room.CafeName
@@ -80,14 +88,14 @@ func (app Contract) UserRoomReminder(c *cli.Context) error {
 
 	dataListRoom, err = m.GetRoomList(m.DB, ctx, h3Reminder)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, room := range dataListRoom {
 
 		dataListUser, err := m.GetAllParticipantByRoomCode(m.DB, ctx, room.RoomCode)
 		if err != nil {
-			return err
+			return total, err
 		}
 		// Populate response
 		for _, user := range dataListUser {
@@ -105,25 +113,26 @@ func (app Contract) UserRoomReminder(c *cli.Context) error {
 
 			descriptionJSON, err := json.Marshal(description)
 			if err != nil {
-				return err
+				return total, err
 			}
 
 			// Insert data into db
 			err = m.AddNotification(m.DB, ctx, notifCode, "user", user.UserCode, room.RoomCode, utils.RoomBookingType, room.Name, descriptionJSON, room.RoomImgUrl)
 			if err != nil {
-				return err
+				return total, err
 			}
+			total++
 
 			onesignal := onesignal.New(m.App)
 			OSDescription := utils.RoomReminderPushNotificationDescription + "\n\n" + "Room name: " + room.Name + "\n" + "Date: " + room.StartDate.Format("2006-01-02") + "\n" + "Location: " + room.CafeName
 			_, err = onesignal.CreateOSNotifications(user.UserXPlayer, utils.RoomReminderPushNotificationTitle, OSDescription, utils.Room)
 			if err != nil {
-				return err
+				return total, err
 			}
 
 		}
 	}
 
 	fmt.Printf("Send Room Reminder Notification success at %v", now.Format("Monday 2006-01-02 15:04:05"))
-	return nil
+	return total, nil
 }
@@ -0,0 +1,22 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
+
+	"github.com/urfave/cli/v2"
+)
+
+// SettleEventCancellations refunds the bookings of the cancelled rooms and
+// tournaments, notifies their members and retries the failed refunds.
+func (app Contract) SettleEventCancellations(c *cli.Context) error {
+	return app.trackJob(utils.JobSettleEventCancellations, app.settleEventCancellations)
+}
+
+// settleEventCancellations returns how many bookings were settled.
+func (app Contract) settleEventCancellations(ctx context.Context) (int, error) {
+	m := model.Contract{App: app.App}
+
+	return m.SettleEventCancellations(ctx)
+}
@@ -16,8 +16,17 @@ import (
 
 // UserRoomReminder ...
 func (app Contract) UserTournamentReminder(c *cli.Context) error {
+	return app.trackJob(utils.JobTournamentReminder, app.remindTournamentParticipants)
+}
+
+// remindTournamentParticipants notifies the participants of the tournaments
+// starting in one and three days, and returns the number of notifications sent.
+func (app Contract) remindTournamentParticipants(ctx context.Context) (int, error) {
 	var dataListTournament []model.TournamentsEnt
-	var err error
+	var (
+		err   error
+		total int
+	)
 	now := time.Now().UTC()
 
 	// Calculate h-3 reminder
@@ -26,20 +35,18 @@ func (app Contract) UserTournamentReminder(c *cli.Context) error {
 	// Calculate h-1 reminder
 	h1Reminder := now.AddDate(0, 0, 1).Format(utils.DATE_FORMAT)
 
-	// Begin Context
-	ctx := context.Background()
 	m := model.Contract{App: app.App}
 
 	dataListTournament, err = m.GetTournamentList(m.DB, ctx, h1Reminder)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, tournament := range dataListTournament {
 
 		dataListUser, err := m.GetAllParticipantByTournamentCode(m.DB, ctx, tournament.TournamentCode)
 		if err != nil {
-			return err
+			return total, err
 		}
 		// Populate response
 		for _, user := range dataListUser {
@@ -59,14 +66,15 @@ func (app Contract) UserTournamentReminder(c *cli.Context) error {
 
 			descriptionJSON, err := json.Marshal(description)
 			if err != nil {
-				return err
+				return total, err
 			}
 
 			// Insert data into db
 			err = m.AddNotification(m.DB, ctx, notifCode, "user", user.UserCode, tournament.TournamentCode, utils.TournamentBookingType, tournament.Name.String, descriptionJSON, tournament.ImageUrl.String)
 			if err != nil {
-				return err
+				return total, err
 			}
+			total++
 
 			onesignal := onesignal.New(m.App)
 			OSDescription := utils.TournamentReminderPushNotificationDescription + "\n\n" + "Tournament name: " + tournament.Name.String + "\n" + "Date: " + tournament.StartDate.Time.Format("2006-01-02") + "\n" + "Location: " + tournament.CafeName
@@ -80,14 +88,14 @@ func (app Contract) UserTournamentReminder(c *cli.Context) error {
 
 	dataListTournament, err = m.GetTournamentList(m.DB, ctx, h3Reminder)
 	if err != nil {
-		return err
+		return total, err
 	}
 
 	for _, tournament := range dataListTournament {
 
 		dataListUser, err := m.GetAllParticipantByTournamentCode(m.DB, ctx, tournament.TournamentCode)
 		if err != nil {
-			return err
+			return total, err
 		}
 		// Populate response
 		for _, user := range dataListUser {
@@ -107,14 +115,15 @@ func (app Contract) UserTournamentReminder(c *cli.Context) error {
 
 			descriptionJSON, err := json.Marshal(description)
 			if err != nil {
-				return err
+				return total, err
 			}
 
 			// Insert data into db
 			err = m.AddNotification(m.DB, ctx, notifCode, "user", user.UserCode, tournament.TournamentCode, utils.TournamentBookingType, tournament.Name.String, descriptionJSON, tournament.ImageUrl.String)
 			if err != nil {
-				return err
+				return total, err
 			}
+			total++
 
 			onesignal := onesignal.New(m.App)
 			OSDescription := utils.TournamentReminderPushNotificationDescription + "\n\n" + "Tournament name: " + tournament.Name.String + "\n" + "Date: " + tournament.StartDate.Time.Format("2006-01-02") + "\n" + "Location: " + tournament.CafeName
@@ -127,5 +136,5 @@ func (app Contract) UserTournamentReminder(c *cli.Context) error {
 	}
 
 	fmt.Printf("Send Tournament Reminder Notification success at %v", now.Format("Monday 2006-01-02 15:04:05"))
-	return nil
+	return total, nil
 }
@@ -0,0 +1,212 @@
+package command
+
+import (
+	"context"
+	"dots-api/lib/utils"
+	"dots-api/services/worker"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	workerRestartDelay    = time.Second
+	workerMaxRestartDelay = time.Minute
+)
+
+// serviceStatus tracks which long-running services of the worker are up.
+type serviceStatus struct {
+	mu sync.Mutex
+	up map[string]bool
+}
+
+func (s *serviceStatus) set(name string, up bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.up[name] = up
+}
+
+func (s *serviceStatus) list() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make(map[string]bool, len(s.up))
+	for name, up := range s.up {
+		list[name] = up
+	}
+
+	return list
+}
+
+// Worker runs every consumer, the outbox relay and the scheduled jobs in one
+// process, and serves its health and metrics over HTTP. A service that fails
+// or panics is restarted with a growing delay. On SIGINT or SIGTERM the worker
+// stops taking new work and waits for the messages and jobs in progress to
+// finish.
+func (app Contract) Worker(c *cli.Context) error {
+	ctx, stop := signal.NotifyContext(c.Context, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	host := c.String("host")
+	if len(host) == 0 {
+		host = app.Config.GetString("worker.status_host")
+	}
+
+	scheduler, err := app.newScheduler()
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg     sync.WaitGroup
+		status = &serviceStatus{up: map[string]bool{}}
+	)
+	app.startServices(ctx, &wg, status)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := worker.Boot{App: app.App, Services: status.list}.Start(ctx, host)
+		if err != nil {
+			log.Printf("Worker status err: %s", err)
+		}
+	}()
+
+	scheduler.Start()
+	log.Printf("Worker ready, PID: %d", os.Getpid())
+
+	<-ctx.Done()
+	log.Printf("Worker shutting down")
+
+	<-scheduler.Stop().Done()
+	wg.Wait()
+
+	log.Printf("Worker stopped")
+	return nil
+}
+
+// RunInProcess runs the consumers and the outbox relay until ctx is cancelled.
+// The API runs them itself with the in-memory queue driver, because a separate
+// worker process cannot reach its messages.
+func (app Contract) RunInProcess(ctx context.Context) {
+	var wg sync.WaitGroup
+	app.startServices(ctx, &wg, &serviceStatus{up: map[string]bool{}})
+	wg.Wait()
+}
+
+// startServices supervises every long-running service in its own goroutine.
+func (app Contract) startServices(ctx context.Context, wg *sync.WaitGroup, status *serviceStatus) {
+	services := map[string]func(context.Context) error{
+		"consumer-badges":      app.consumeBadges,
+		"consumer-user-badges": app.consumeUserBadge,
+		"outbox-relay":         app.relayOutbox,
+	}
+
+	for name, run := range services {
+		status.set(name, false)
+
+		wg.Add(1)
+		go func(name string, run func(context.Context) error) {
+			defer wg.Done()
+			supervise(ctx, name, status, run)
+		}(name, run)
+	}
+}
+
+// workerJobs lists the jobs that can be scheduled in the worker.jobs config.
+func (app Contract) workerJobs() map[string]jobFunc {
+	return map[string]jobFunc{
+		utils.JobRoomReminder:                 app.remindRoomParticipants,
+		utils.JobTournamentReminder:           app.remindTournamentParticipants,
+		utils.JobSetInactiveRoomAndTournament: app.setInactiveRoomAndTournament,
+		utils.JobPublishScheduledBadges:       app.publishScheduledBadges,
+		utils.JobProcessWaitlists:             app.processWaitlists,
+		utils.JobReleaseSeatHolds:             app.releaseSeatHolds,
+		utils.JobGenerateRoomSeries:           app.generateRoomSeries,
+		utils.JobMarkNoShows:                  app.markNoShows,
+		utils.JobCheckMinimumParticipants:     app.checkMinimumParticipants,
+		utils.JobSettleEventCancellations:     app.settleEventCancellations,
+		utils.JobPurgeJobRuns:                 app.purgeJobRuns,
+	}
+}
+
+// newScheduler schedules the jobs of the worker.jobs config, a map of job name
+// to cron expression in WIB. Each tick is claimed in the database first, so
+// only one worker runs it when several are deployed.
+func (app Contract) newScheduler() (*cron.Cron, error) {
+	var (
+		jobs      = app.workerJobs()
+		logger    = cron.PrintfLogger(log.Default())
+		scheduler = cron.New(
+			cron.WithLocation(utils.GetTimeLocationWIB()),
+			cron.WithLogger(logger),
+			cron.WithChain(cron.SkipIfStillRunning(logger)),
+		)
+	)
+
+	for name, spec := range app.Config.GetStringMapString("worker.jobs") {
+		job, ok := jobs[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", utils.ErrUnknownWorkerJob, name)
+		}
+
+		name, job := name, job
+		_, err := scheduler.AddFunc(spec, func() {
+			tick := time.Now().UTC().Truncate(time.Minute)
+			if err := app.runJob(context.Background(), name, tick, job); err != nil {
+				log.Printf("[%s] Job err: %s", name, err)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[%s] invalid schedule %q: %s", name, spec, err)
+		}
+		log.Printf("[%s] Scheduled at %q", name, spec)
+	}
+
+	return scheduler, nil
+}
+
+// supervise runs a long-running service until ctx is cancelled, restarting it
+// after an error or a panic.
+func supervise(ctx context.Context, name string, status *serviceStatus, run func(context.Context) error) {
+	delay := workerRestartDelay
+	for {
+		started := time.Now()
+		status.set(name, true)
+		err := safeRun(func() error { return run(ctx) })
+		status.set(name, false)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A service that ran for a while starts over with a short delay
+		if time.Since(started) > workerMaxRestartDelay {
+			delay = workerRestartDelay
+		}
+		log.Printf("[%s] Stopped unexpectedly: %v, restarting in %s", name, err, delay)
+
+		sleep(ctx, delay)
+		if delay *= 2; delay > workerMaxRestartDelay {
+			delay = workerMaxRestartDelay
+		}
+	}
+}
+
+// safeRun calls fn and turns a panic into an error.
+func safeRun(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"context"
+	"dots-api/services/api/model"
+)
+
+// MarkNoShows flags the paid bookings that did not check in after their event
+// started. It returns the number of bookings flagged.
+func (h *Contract) MarkNoShows(ctx context.Context) (int, error) {
+	m := model.Contract{App: h.App}
+
+	return m.MarkNoShows(h.DB, ctx)
+}
@@ -2,205 +2,208 @@ package model
 
 import (
 	"context"
+	"dots-api/lib/badge"
+	"dots-api/lib/rabbit"
 	"dots-api/lib/utils"
 	"dots-api/services/api/model"
-	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/sirupsen/logrus"
 )
 
+// CheckBadges awards a badge to every member who satisfies its rules. The
+// qualifying members are computed with one aggregate query per rule and the
+// user badges are inserted in batches of utils.UserBadgeBatchSize. A level of
+// a badge series is checked together with the rest of its series.
 func (h *Contract) CheckBadges(ctx context.Context, badgeCode string) error {
 	var (
-		m = model.Contract{App: h.App}
+		m   = model.Contract{App: h.App}
+		src = m.NewBadgeSource(h.DB)
 	)
-	userIdList, err := m.GetListUsersByUserId(h.DB, ctx)
+
+	badgeData, err := m.GetBadgeDetailByCode(h.DB, ctx, badgeCode)
+	if err != nil {
+		return h.errHandler("model.CheckBadge", err, utils.ErrGettingBadgeID)
+	}
+
+	if badgeData.SeriesLevel.Valid {
+		return h.CheckBadgeSeries(ctx, badgeData.ParentCode.String)
+	}
+
+	if !badgeData.IsAvailable(time.Now().UTC()) {
+		h.Log.FromDefault().WithField("badgeCode", badgeCode).Infof("Checking badge : skipped, outside of earning window")
+		return nil
+	}
+
+	badgeRuleList, err := m.GetBadgeRuleByBadgeCode(h.DB, ctx, badgeCode)
 	if err != nil {
 		return h.errHandler("model.CheckBadge", err, utils.ErrGettingBadgeRule)
 	}
 
-	for _, userId := range userIdList {
-		badgeRuleList, err := m.GetBadgeRuleByBadgeCode(h.DB, ctx, badgeCode)
+	userIdList, err := badge.EvaluateUsers(ctx, src.Within(badgeData.AvailableStartDate, badgeData.AvailableEndDate), badgeData.RuleOperator, model.BadgeRules(badgeRuleList))
+	if err != nil {
+		return h.errHandler("model.CheckBadge", err, utils.ErrGettingQualifiedUsers)
+	}
+
+	if err = h.awardBadge(ctx, badgeData, userIdList); err != nil {
+		return err
+	}
+
+	return h.MarkBadgePublished(h.DB, ctx, badgeCode)
+}
+
+// CheckBadgeSeries awards every member the highest level of a badge series
+// they qualify for. Lower levels are not awarded to members reaching or
+// already owning a higher one, so each member only holds the level matching
+// their progress.
+func (h *Contract) CheckBadgeSeries(ctx context.Context, seriesCode string) error {
+	var (
+		m        = model.Contract{App: h.App}
+		src      = m.NewBadgeSource(h.DB)
+		now      = time.Now().UTC()
+		assigned = map[int64]bool{}
+	)
+
+	levels, err := m.GetBadgeSeriesLevels(h.DB, ctx, seriesCode)
+	if err != nil {
+		return h.errHandler("model.CheckBadgeSeries", err, utils.ErrGettingBadgeSeries)
+	}
+
+	for i := len(levels) - 1; i >= 0; i-- {
+		level := levels[i]
+		if level.Status != "active" || !level.IsAvailable(now) {
+			continue
+		}
+
+		badgeRuleList, err := m.GetBadgeRuleList(h.DB, ctx, level.Id)
 		if err != nil {
-			return h.errHandler("model.CheckBadge", err, utils.ErrGettingBadgeRule)
+			return h.errHandler("model.CheckBadgeSeries", err, utils.ErrGettingBadgeRule)
 		}
 
-		var badgeRules []bool
-
-		for _, badgeRule := range badgeRuleList {
-			// check type is spesific board game category
-			if badgeRule.KeyCondition == utils.SpesificBoardGameCategory {
-				var specificBoardGameCategory SpesificBoardGameCategory
-				valueJSON, err := json.Marshal(badgeRule.Value)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				err = json.Unmarshal(valueJSON, &specificBoardGameCategory)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				isGameMaster := specificBoardGameCategory.NeedGM
-
-				var totalGamesPlayed int64
-				for _, gameCode := range specificBoardGameCategory.GameCode {
-
-					gameId, err := m.GetGameIdByCode(h.DB, ctx, gameCode)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrGettingGameByCode)
-					}
-
-					roomGameCount, err := m.CountRoomParticipantByUserIdAndGameIdAndIsGameMasterAndBookingPrice(h.DB, ctx, userId, gameId, specificBoardGameCategory.BookingPrice, isGameMaster)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingRoomParticipants)
-					}
-
-					tournamentGameCount, err := m.CountTournamentParticipantByUserIdAndGameIdAndIsGameMasterAndBookingPrice(h.DB, ctx, userId, gameId, specificBoardGameCategory.BookingPrice)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingTournamentParticipants)
-					}
-
-					totalGamesPlayed += roomGameCount + tournamentGameCount
-				}
-
-				if totalGamesPlayed >= specificBoardGameCategory.TotalPlayed {
-					badgeRules = append(badgeRules, true)
-				} else {
-					badgeRules = append(badgeRules, false)
-				}
-				// check if condition is time limit
-			} else if badgeRule.KeyCondition == utils.TimeLimit {
-				var timeLimitCategory TimeLimitCategory
-				valueJSON, err := json.Marshal(badgeRule.Value)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				err = json.Unmarshal(valueJSON, &timeLimitCategory)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				// check if type time limit
-				if timeLimitCategory.Category == utils.TimeLimit {
-					roomCount, err := m.CountRoomParticipantByUserIdAndStartDateAndEndDate(h.DB, ctx, userId, timeLimitCategory.StartDate, timeLimitCategory.EndDate)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingRoomParticipants)
-					}
-					tournamentCount, err := m.CountTournamentParticipantByUserIdAndStartDateAndEndDate(h.DB, ctx, userId, timeLimitCategory.StartDate, timeLimitCategory.EndDate)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingTournamentParticipants)
-					}
-
-					if roomCount > 1 && tournamentCount > 1 {
-						badgeRules = append(badgeRules, true)
-					} else {
-						badgeRules = append(badgeRules, false)
-					}
-
-					// check if type life time
-				} else if timeLimitCategory.Category == utils.LifeTime {
-					roomCount, err := m.CountRoomParticipantByUserIdAndStartDateAndLifeTime(h.DB, ctx, userId, timeLimitCategory.StartDate, timeLimitCategory.EndDate)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingRoomParticipants)
-					}
-					tournamentCount, err := m.CountTournamentParticipantByUserIdAndStartDateAndLifeTime(h.DB, ctx, userId, timeLimitCategory.StartDate, timeLimitCategory.EndDate)
-					if err != nil {
-						return h.errHandler("model.CheckBadge", err, utils.ErrCountingTournamentParticipants)
-					}
-
-					if roomCount > 1 && tournamentCount > 1 {
-						badgeRules = append(badgeRules, true)
-					} else {
-						badgeRules = append(badgeRules, false)
-					}
-				}
-
-				// check if condition is total spend
-			} else if badgeRule.KeyCondition == utils.TotalSpend {
-				var requiredSpendAmount int
-				valueJSON, err := json.Marshal(badgeRule.Value)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				err = json.Unmarshal(valueJSON, &requiredSpendAmount)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				totalClaimedInvoiceAmount, err := m.GetTotalInvoiceAmountByUserID(h.DB, ctx, userId)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrGettingTotalInvoiceAmount)
-				}
-
-				totalBookingAmount, err := m.GetTotalBookingAmountByUserID(h.DB, ctx, userId)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrGettingTotalBookingAmount)
-				}
-
-				totalSpentAmount := totalBookingAmount + totalClaimedInvoiceAmount
-				if requiredSpendAmount <= totalSpentAmount {
-					badgeRules = append(badgeRules, true)
-				} else {
-					badgeRules = append(badgeRules, false)
-				}
-			} else if badgeRule.KeyCondition == utils.TournamentWon {
-				var requiredTournamentWon int
-				valueJSON, err := json.Marshal(badgeRule.Value)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				err = json.Unmarshal(valueJSON, &requiredTournamentWon)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				totalTournamenWon, err := m.CountTournamentWinnerByUserId(h.DB, ctx, userId)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrGettingTotalInvoiceAmount)
-				}
-
-				if requiredTournamentWon <= totalTournamenWon {
-					badgeRules = append(badgeRules, true)
-				} else {
-					badgeRules = append(badgeRules, false)
-				}
-
-			} else if badgeRule.KeyCondition == utils.PlayingGames {
-				var requiredDifferentGames int
-				valueJSON, err := json.Marshal(badgeRule.Value)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				err = json.Unmarshal(valueJSON, &requiredDifferentGames)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrUnmarshallingBadgeRule)
-				}
-
-				totalGames, err := m.CountUserGameCollectionsByUserID(h.DB, ctx, userId)
-				if err != nil {
-					return h.errHandler("model.CheckBadge", err, utils.ErrGettingTotalInvoiceAmount)
-				}
-
-				if requiredDifferentGames <= totalGames {
-					badgeRules = append(badgeRules, true)
-				} else {
-					badgeRules = append(badgeRules, false)
-				}
-			}
+		qualified, err := badge.EvaluateUsers(ctx, src.Within(level.AvailableStartDate, level.AvailableEndDate), level.RuleOperator, model.BadgeRules(badgeRuleList))
+		if err != nil {
+			return h.errHandler("model.CheckBadgeSeries", err, utils.ErrGettingQualifiedUsers)
 		}
-		if utils.ContainsFalse(badgeRules) {
-			badgeId, err := m.GetBadgeIdByCode(h.DB, ctx, badgeCode)
-			if err != nil {
-				return h.errHandler("model.CheckBadge", err, utils.ErrGettingBadgeID)
-			}
 
-			err = m.AddUserBadge(h.DB, ctx, userId, badgeId)
-			if err != nil {
-				return h.errHandler("model.CheckBadge", err, utils.ErrAddingUserBadge)
+		userIdList := make([]int64, 0, len(qualified))
+		for _, userId := range qualified {
+			if !assigned[userId] {
+				assigned[userId] = true
+				userIdList = append(userIdList, userId)
 			}
 		}
+
+		if err = h.awardBadge(ctx, level, userIdList); err != nil {
+			return err
+		}
+
+		if err = h.MarkBadgePublished(h.DB, ctx, level.BadgeCode); err != nil {
+			return err
+		}
 	}
+
+	return nil
+}
+
+// awardBadge inserts the user badges in batches and logs the progress.
+func (h *Contract) awardBadge(ctx context.Context, badgeData model.BadgeEnt, userIdList []int64) error {
+	var (
+		m       = model.Contract{App: h.App}
+		awarded int64
+	)
+
+	logger := h.Log.FromDefault().WithFields(logrus.Fields{
+		"badgeCode": badgeData.BadgeCode,
+		"qualified": len(userIdList),
+	})
+	logger.Infof("Checking badge : %d qualified users", len(userIdList))
+
+	for start := 0; start < len(userIdList); start += utils.UserBadgeBatchSize {
+		end := start + utils.UserBadgeBatchSize
+		if end > len(userIdList) {
+			end = len(userIdList)
+		}
+
+		inserted, err := m.AddUserBadges(h.DB, ctx, badgeData.Id, userIdList[start:end])
+		if err != nil {
+			return h.errHandler("model.CheckBadge", err, utils.ErrAddingUserBadge)
+		}
+		awarded += inserted
+
+		logger.Infof("Checking badge : processed %d/%d users, %d awarded", end, len(userIdList), awarded)
+	}
+
+	return nil
+}
+
+// QueueScheduledBadges claims the active badges whose earning window has
+// opened but which have not been checked against the members yet, and writes
+// a badge message to the outbox for each of them. Claimed badges are marked
+// published in the same transaction so the next run does not queue them again.
+func (h *Contract) QueueScheduledBadges(ctx context.Context) (int, error) {
+	var (
+		m          = model.Contract{App: h.App}
+		now        = time.Now().UTC()
+		badgeCodes []string
+		query      = `
+		UPDATE badges SET published_date = $3
+		WHERE status = 'active' AND deleted_date IS NULL AND published_date IS NULL
+			AND badge_category NOT IN ($1, $2)
+			AND available_start_date IS NOT NULL AND available_start_date <= $3
+			AND (available_end_date IS NULL OR available_end_date >= $3)
+		RETURNING badge_code`
+	)
+
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return 0, h.errHandler("model.QueueScheduledBadges", err, utils.ErrGettingBadgeList)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query, utils.BadgeCategoryGift.String(), utils.BadgeCategoryTournament.String(), now)
+	if err != nil {
+		return 0, h.errHandler("model.QueueScheduledBadges", err, utils.ErrGettingBadgeList)
+	}
+
+	for rows.Next() {
+		var badgeCode string
+		if err = rows.Scan(&badgeCode); err != nil {
+			rows.Close()
+			return 0, h.errHandler("model.QueueScheduledBadges", err, utils.ErrGettingBadgeList)
+		}
+		badgeCodes = append(badgeCodes, badgeCode)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, h.errHandler("model.QueueScheduledBadges", err, utils.ErrGettingBadgeList)
+	}
+
+	for _, badgeCode := range badgeCodes {
+		err = m.AddOutboxEventTrx(tx, ctx, rabbit.QueueDataPayload(
+			rabbit.QueueBadges,
+			rabbit.QueueBadgeReq(
+				badgeCode,
+			),
+		))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, h.errHandler("model.QueueScheduledBadges", err, utils.ErrCommittingTransaction)
+	}
+
+	return len(badgeCodes), nil
+}
+
+// MarkBadgePublished records that a badge has been checked against the members.
+func (h *Contract) MarkBadgePublished(db *pgxpool.Pool, ctx context.Context, badgeCode string) error {
+	_, err := db.Exec(ctx, `UPDATE badges SET published_date = $1 WHERE badge_code = $2`, time.Now().UTC(), badgeCode)
+	if err != nil {
+		return h.errHandler("model.MarkBadgePublished", err, utils.ErrUpdatingBadge)
+	}
+
 	return nil
 }
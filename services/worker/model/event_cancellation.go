@@ -0,0 +1,14 @@
+package model
+
+import (
+	"context"
+	"dots-api/services/api/model"
+)
+
+// SettleEventCancellations refunds the bookings of cancelled events and
+// notifies their members. It returns the number of bookings settled.
+func (h *Contract) SettleEventCancellations(ctx context.Context) (int, error) {
+	m := model.Contract{App: h.App}
+
+	return m.SettleEventCancellations(h.DB, ctx)
+}
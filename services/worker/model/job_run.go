@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/utils"
+	"time"
+)
+
+// AcquireJobRun claims the run of a job for one schedule tick. It returns
+// false when another worker already claimed the tick. A run still running
+// after utils.JobRunTimeoutMinutes was abandoned by a worker that stopped
+// mid-run: it is marked failed, and its tick can be claimed again.
+func (h *Contract) AcquireJobRun(ctx context.Context, jobName string, scheduledDate time.Time) (bool, error) {
+	var (
+		now   = time.Now().UTC()
+		stale = now.Add(-time.Duration(utils.JobRunTimeoutMinutes) * time.Minute)
+	)
+
+	query := `
+		UPDATE job_runs SET status = $1, error_message = $2, finished_date = $3
+		WHERE job_name = $4 AND status = $5 AND started_date < $6 AND scheduled_date != $7`
+	_, err := h.DB.Exec(ctx, query, utils.JobRunFailed, utils.JobRunAbandoned, now, jobName, utils.JobRunRunning, stale, scheduledDate)
+	if err != nil {
+		return false, h.errHandler("model.AcquireJobRun", err, utils.ErrAcquiringJobRun)
+	}
+
+	query = `
+		INSERT INTO job_runs (job_name, scheduled_date, status, started_date)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_name, scheduled_date) DO UPDATE SET
+			status = EXCLUDED.status, error_message = NULL, item_count = 0,
+			started_date = EXCLUDED.started_date, finished_date = NULL
+		WHERE job_runs.status = EXCLUDED.status AND job_runs.started_date < $5`
+	tag, err := h.DB.Exec(ctx, query, jobName, scheduledDate, utils.JobRunRunning, now, stale)
+	if err != nil {
+		return false, h.errHandler("model.AcquireJobRun", err, utils.ErrAcquiringJobRun)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// FinishJobRun records the result and the number of processed items of a
+// claimed job run.
+func (h *Contract) FinishJobRun(ctx context.Context, jobName string, scheduledDate time.Time, itemCount int, runErr error) error {
+	var (
+		status  = utils.JobRunSuccess
+		message *string
+	)
+	if runErr != nil {
+		status = utils.JobRunFailed
+		msg := runErr.Error()
+		message = &msg
+	}
+
+	query := `
+		UPDATE job_runs
+		SET status = $1, error_message = $2, item_count = $3, finished_date = $4
+		WHERE job_name = $5 AND scheduled_date = $6`
+	_, err := h.DB.Exec(ctx, query, status, message, itemCount, time.Now().UTC(), jobName, scheduledDate)
+	if err != nil {
+		return h.errHandler("model.FinishJobRun", err, utils.ErrFinishingJobRun)
+	}
+
+	return nil
+}
+
+// PurgeJobRuns deletes the finished job runs started before the given time.
+func (h *Contract) PurgeJobRuns(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := h.DB.Exec(ctx, `DELETE FROM job_runs WHERE status != $1 AND started_date < $2`, utils.JobRunRunning, before)
+	if err != nil {
+		return 0, h.errHandler("model.PurgeJobRuns", err, utils.ErrDeletingJobRuns)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// JobRunEnt is one run of a scheduled job.
+type JobRunEnt struct {
+	JobName       string         `db:"job_name"`
+	ScheduledDate time.Time      `db:"scheduled_date"`
+	Status        string         `db:"status"`
+	ErrorMessage  sql.NullString `db:"error_message"`
+	ItemCount     int            `db:"item_count"`
+	StartedDate   time.Time      `db:"started_date"`
+	FinishedDate  sql.NullTime   `db:"finished_date"`
+}
+
+// GetLatestJobRuns returns the latest run of every job.
+func (h *Contract) GetLatestJobRuns(ctx context.Context) ([]JobRunEnt, error) {
+	query := `
+		SELECT DISTINCT ON (job_name) job_name, scheduled_date, status, error_message, item_count, started_date, finished_date
+		FROM job_runs
+		ORDER BY job_name, scheduled_date DESC`
+	rows, err := h.DB.Query(ctx, query)
+	if err != nil {
+		return nil, h.errHandler("model.GetLatestJobRuns", err, utils.ErrGettingJobRuns)
+	}
+	defer rows.Close()
+
+	var list []JobRunEnt
+	for rows.Next() {
+		var data JobRunEnt
+		err = rows.Scan(&data.JobName, &data.ScheduledDate, &data.Status, &data.ErrorMessage, &data.ItemCount, &data.StartedDate, &data.FinishedDate)
+		if err != nil {
+			return nil, h.errHandler("model.GetLatestJobRuns", err, utils.ErrGettingJobRuns)
+		}
+		list = append(list, data)
+	}
+
+	return list, nil
+}
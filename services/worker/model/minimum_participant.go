@@ -0,0 +1,15 @@
+package model
+
+import (
+	"context"
+	"dots-api/services/api/model"
+)
+
+// CheckMinimumParticipants cancels the events short of their minimum
+// participants at the cutoff and warns their staff before it. It returns how
+// many events were warned or cancelled.
+func (h *Contract) CheckMinimumParticipants(ctx context.Context, cutoffHours, warningHours int) (int, error) {
+	m := model.Contract{App: h.App}
+
+	return m.CheckMinimumParticipants(h.DB, ctx, cutoffHours, warningHours)
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"time"
+)
+
+// OutboxEventEnt is a queue message waiting to be published.
+type OutboxEventEnt struct {
+	Id        int64          `db:"id"`
+	QueueName string         `db:"queue_name"`
+	Payload   []byte         `db:"payload"`
+	Attempts  int            `db:"attempts"`
+	LastError sql.NullString `db:"last_error"`
+}
+
+// AddOutboxEvent writes a queue message to the outbox.
+func (h *Contract) AddOutboxEvent(ctx context.Context, data rabbit.QueueData) error {
+	m := model.Contract{App: h.App}
+
+	return m.AddOutboxEvent(h.DB, ctx, data)
+}
+
+// RelayOutboxEvents publishes up to limit pending outbox events to the queue
+// driver in order and returns how many were published. The events are locked while they are
+// published, so several relays never publish the same event. Publishing stops
+// at the first failure to keep the order of the remaining events.
+func (h *Contract) RelayOutboxEvents(ctx context.Context, limit int) (int, error) {
+	tx, err := h.DB.Begin(ctx)
+	if err != nil {
+		return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrGettingOutboxEvents)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, queue_name, payload, attempts, last_error
+		FROM outbox_events
+		WHERE published_date IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrGettingOutboxEvents)
+	}
+
+	var events []OutboxEventEnt
+	for rows.Next() {
+		var data OutboxEventEnt
+		if err = rows.Scan(&data.Id, &data.QueueName, &data.Payload, &data.Attempts, &data.LastError); err != nil {
+			rows.Close()
+			return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrGettingOutboxEvents)
+		}
+		events = append(events, data)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrGettingOutboxEvents)
+	}
+
+	var (
+		published  int
+		publishErr error
+	)
+	for _, event := range events {
+		if publishErr = h.Queue.Publish(ctx, event.QueueName, event.Payload); publishErr != nil {
+			_, err = tx.Exec(ctx, `UPDATE outbox_events SET attempts = attempts + 1, last_error = $1 WHERE id = $2`, publishErr.Error(), event.Id)
+			if err != nil {
+				return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrUpdatingOutboxEvent)
+			}
+			break
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE outbox_events SET published_date = $1 WHERE id = $2`, time.Now().UTC(), event.Id)
+		if err != nil {
+			return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrUpdatingOutboxEvent)
+		}
+		published++
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, h.errHandler("model.RelayOutboxEvents", err, utils.ErrUpdatingOutboxEvent)
+	}
+
+	return published, publishErr
+}
+
+// PurgeOutboxEvents deletes the events published before the given time.
+func (h *Contract) PurgeOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := h.DB.Exec(ctx, `DELETE FROM outbox_events WHERE published_date IS NOT NULL AND published_date < $1`, before)
+	if err != nil {
+		return 0, h.errHandler("model.PurgeOutboxEvents", err, utils.ErrDeletingOutboxEvents)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -125,7 +125,7 @@ func (c *Contract) GetListRoomCodes(db *pgxpool.Pool, ctx context.Context) ([]st
 	var (
 		err       error
 		roomCodes []string
-		query     = `SELECT room_code FROM rooms WHERE end_date < NOW() AND deleted_date IS NULL`
+		query     = `SELECT room_code FROM rooms WHERE end_date < NOW() AND status != 'cancelled' AND deleted_date IS NULL`
 	)
 
 	rows, err := db.Query(ctx, query)
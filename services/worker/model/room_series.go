@@ -0,0 +1,57 @@
+package model
+
+import (
+	"context"
+	"dots-api/lib/recurrence"
+	"dots-api/lib/utils"
+	"dots-api/services/api/model"
+	"time"
+)
+
+// GenerateRoomSeries creates the rooms of every active series for the next
+// utils.RoomSeriesGenerateDays days and tells the subscribers about them. A
+// date the game master of the series cannot host, as checked when a series is
+// created, gets no room. It returns the number of rooms created.
+func (h *Contract) GenerateRoomSeries(ctx context.Context) (int, error) {
+	var (
+		m     = model.Contract{App: h.App}
+		today = recurrence.Today()
+		upTo  = today.AddDate(0, 0, utils.RoomSeriesGenerateDays)
+		total int
+	)
+
+	list, err := m.GetRoomSeriesToGenerate(h.DB, ctx, upTo)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, series := range list {
+		tx, err := h.DB.Begin(ctx)
+		if err != nil {
+			return total, h.errHandler("model.GenerateRoomSeries", err, utils.ErrGeneratingRoomSeries)
+		}
+
+		// Hold the lock of the game master until the rooms are saved
+		if err = m.LockGameMasterTrx(tx, ctx, series.GameMasterId); err != nil {
+			tx.Rollback(ctx)
+			return total, err
+		}
+
+		occurrences, err := m.GenerateRoomSeriesTrx(tx, ctx, series, today, upTo, func(date time.Time) error {
+			return m.CheckGameMasterSchedule(h.DB, ctx, series.Slot(date))
+		})
+		if err != nil {
+			tx.Rollback(ctx)
+			return total, err
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			return total, h.errHandler("model.GenerateRoomSeries", err, utils.ErrGeneratingRoomSeries)
+		}
+
+		m.NotifyRoomSeriesSubscribers(h.DB, ctx, series, occurrences)
+		total += len(occurrences)
+	}
+
+	return total, nil
+}
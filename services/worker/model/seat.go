@@ -0,0 +1,21 @@
+package model
+
+import (
+	"context"
+	"dots-api/services/api/model"
+)
+
+// ReleaseExpiredSeatHolds cancels the unpaid bookings whose hold expired and
+// offers their seats to the waitlists. It returns the number of bookings
+// released.
+func (h *Contract) ReleaseExpiredSeatHolds(ctx context.Context) (int, error) {
+	m := model.Contract{App: h.App}
+
+	total, offers, err := m.ReleaseExpiredSeatHolds(h.DB, ctx)
+	if err != nil {
+		return 0, err
+	}
+	m.PushWaitlistOffers(offers)
+
+	return total, nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"context"
+	"dots-api/services/api/model"
+)
+
+// ProcessWaitlists expires the seat offers that were not booked in time and
+// offers every free seat to the next members on the waitlists. It returns the
+// number of offers made.
+func (h *Contract) ProcessWaitlists(ctx context.Context) (int, error) {
+	m := model.Contract{App: h.App}
+
+	if _, err := m.ExpireWaitlistOffers(h.DB, ctx); err != nil {
+		return 0, err
+	}
+
+	events, err := m.GetWaitingWaitlistEvents(h.DB, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, event := range events {
+		offers, err := m.OfferWaitlistSeats(h.DB, ctx, event.EventType, event.EventId)
+		if err != nil {
+			return total, err
+		}
+
+		m.PushWaitlistOffers(offers)
+		total += len(offers)
+	}
+
+	return total, nil
+}
@@ -3,24 +3,28 @@ package worker
 import (
 	"context"
 	"dots-api/bootstrap"
-	"fmt"
+	"dots-api/lib/rabbit"
+	"dots-api/lib/utils"
+	"dots-api/services/worker/model"
 	"log"
-	"net"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
 
-	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
-	"github.com/go-chi/cors"
-	"github.com/go-chi/valve"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/urfave/cli/v2"
 )
 
-// Boot ...
+const (
+	readyCheckTimeout = 2 * time.Second
+	shutdownTimeout   = 20 * time.Second
+)
+
+// Boot serves the status of the worker over HTTP.
 type Boot struct {
 	*bootstrap.App
+	// Services reports whether each long-running service of the worker is up.
+	Services func() map[string]bool
 }
 
 var (
@@ -28,89 +32,156 @@ var (
 	Flags = []cli.Flag{
 		&cli.StringFlag{
 			Name:  "host",
-			Value: "127.0.0.1:4000",
-			Usage: "Run API service with custom host",
+			Usage: "Run worker status service with custom host, defaults to worker.status_host",
 		},
 	}
 )
 
-// Start main function to run the http host
-func (app Boot) Start(c *cli.Context) error {
-	var err error
+type (
+	// HealthRes is the liveness and readiness of the worker.
+	HealthRes struct {
+		Status   string            `json:"status"`
+		Uptime   string            `json:"uptime"`
+		Checks   map[string]string `json:"checks,omitempty"`
+		Services map[string]bool   `json:"services,omitempty"`
+	}
 
-	host := c.String("host")
-	if len(host) == 0 {
-		host = app.Config.GetString("app.host")
+	// MetricsRes is the activity of the worker since it started.
+	MetricsRes struct {
+		Uptime   string                       `json:"uptime"`
+		Services map[string]bool              `json:"services"`
+		Queues   map[string]rabbit.QueueStats `json:"queues"`
+		Jobs     []JobRunRes                  `json:"jobs"`
 	}
-	if app.Debug {
-		log.Printf("Check Appointment Service -> Running on Debug Mode: On at host [%v]", host)
+
+	// JobRunRes is the latest run of a scheduled job.
+	JobRunRes struct {
+		JobName       string `json:"job_name"`
+		Status        string `json:"status"`
+		ItemCount     int    `json:"item_count"`
+		ErrorMessage  string `json:"error_message"`
+		ScheduledDate string `json:"scheduled_date"`
+		StartedDate   string `json:"started_date"`
+		FinishedDate  string `json:"finished_date"`
 	}
+)
 
-	// gracefull shutdown handler
-	valv := valve.New()
-	baseCtx := valv.Context()
+var startedDate = time.Now()
 
-	// start new app
+// Start serves the worker status on host until ctx is cancelled.
+//
+//	GET /health/live   the process is running
+//	GET /health/ready  the database is reachable and every service is up
+//	GET /metrics       queue counters and the latest run of every job
+func (app Boot) Start(ctx context.Context, host string) error {
 	r := chi.NewRouter()
-	cr := cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{
-			"Accept",
-			"Authorization",
-			"Content-Type",
-			"X-Timezone",
-			"X-CHANNEL",
-			"X-PLAYER",
-			"Access-Control-Allow-Headers",
-			"X-Requested-With",
-			"application/json",
-			"Cache-Control",
-			"multipart/form-data; boundary=<calculated when request is sent>",
-			"multipart/form-data",
-		},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	})
-	r.Use(cr.Handler)
 	if app.Debug {
 		r.Use(middleware.Logger)
 	}
 	r.Use(app.Recoverer)
 	r.Use(app.NotfoundMiddleware)
 
-	// handle grace full shutdown
-	srv := http.Server{Addr: host, Handler: r}
-	srv.BaseContext = func(_ net.Listener) context.Context {
-		return baseCtx
-	}
-	sng := make(chan os.Signal, 1)
-	signal.Notify(sng, os.Interrupt)
+	r.Get("/health/live", app.LiveAct)
+	r.Get("/health/ready", app.ReadyAct)
+	r.Get("/metrics", app.MetricsAct)
 
+	srv := http.Server{Addr: host, Handler: r}
 	go func() {
-		for range sng {
-			fmt.Println("shutting down..")
-			err = valv.Shutdown(20 * time.Second)
-			if err != nil {
-				log.Println("Can't shutdown this server until all process are done!")
-			}
-
-			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-			defer cancel()
-
-			err = srv.Shutdown(ctx)
-			if err != nil {
-				log.Println("Can't shutdown this server until all process are done!")
-			}
-			select {
-			case <-time.After(21 * time.Second):
-				fmt.Println("not all connections done")
-			case <-ctx.Done():
-
-			}
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Println("Can't shutdown the worker status server:", err)
 		}
 	}()
 
-	return srv.ListenAndServe()
+	log.Printf("Worker status running at [%v]", host)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// LiveAct reports that the process is running.
+func (app Boot) LiveAct(w http.ResponseWriter, r *http.Request) {
+	app.SendSuccess(w, HealthRes{Status: "ok", Uptime: uptime()}, nil)
+}
+
+// ReadyAct reports whether the worker can process work. It responds with 503
+// while the database is unreachable or a service is down.
+func (app Boot) ReadyAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		res = HealthRes{
+			Status:   "ok",
+			Uptime:   uptime(),
+			Checks:   map[string]string{"database": "ok"},
+			Services: app.Services(),
+		}
+	)
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+	defer cancel()
+
+	if err := app.DB.Ping(ctx); err != nil {
+		res.Status = "unavailable"
+		res.Checks["database"] = err.Error()
+	}
+	for _, up := range res.Services {
+		if !up {
+			res.Status = "unavailable"
+		}
+	}
+
+	if res.Status != "ok" {
+		app.RespondWithJSON(w, http.StatusServiceUnavailable, bootstrap.MsgBadReq, utils.ErrWorkerNotReady, res, app.EmptyJSONArr())
+		return
+	}
+
+	app.SendSuccess(w, res, nil)
+}
+
+// MetricsAct reports the queue counters and the latest run of every job.
+func (app Boot) MetricsAct(w http.ResponseWriter, r *http.Request) {
+	var (
+		m   = model.Contract{App: app.App}
+		res = MetricsRes{
+			Uptime:   uptime(),
+			Services: app.Services(),
+			Queues:   rabbit.Stats(),
+			Jobs:     make([]JobRunRes, 0),
+		}
+	)
+
+	list, err := m.GetLatestJobRuns(r.Context())
+	if err != nil {
+		app.SendBadRequest(w, err.Error())
+		return
+	}
+
+	for _, v := range list {
+		// A run still in progress has no finished date
+		finishedDate := ""
+		if v.FinishedDate.Valid {
+			finishedDate = v.FinishedDate.Time.Format(utils.DATE_TIME_FORMAT)
+		}
+
+		res.Jobs = append(res.Jobs, JobRunRes{
+			JobName:       v.JobName,
+			Status:        v.Status,
+			ItemCount:     v.ItemCount,
+			ErrorMessage:  v.ErrorMessage.String,
+			ScheduledDate: v.ScheduledDate.Format(utils.DATE_TIME_FORMAT),
+			StartedDate:   v.StartedDate.Format(utils.DATE_TIME_FORMAT),
+			FinishedDate:  finishedDate,
+		})
+	}
+
+	app.SendSuccess(w, res, nil)
+}
+
+func uptime() string {
+	return time.Since(startedDate).Round(time.Second).String()
 }